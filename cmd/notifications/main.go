@@ -8,6 +8,7 @@ import (
 	"notifications/internal/db/tx"
 	"notifications/internal/gateway"
 	"notifications/internal/handler"
+	"notifications/internal/lib/country"
 	"notifications/internal/repo"
 	"notifications/internal/service"
 	"notifications/pkg/lib"
@@ -22,6 +23,7 @@ func main() {
 		repo.Module,
 		db.Module,
 		tx.Module,
+		country.Module,
 		lib.Module,
 	).Run()
 
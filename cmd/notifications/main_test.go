@@ -10,6 +10,7 @@ import (
 	"notifications/internal/db/tx"
 	"notifications/internal/gateway"
 	"notifications/internal/handler"
+	"notifications/internal/lib/country"
 	"notifications/internal/repo"
 	"notifications/internal/service"
 	"notifications/pkg/lib"
@@ -31,6 +32,7 @@ func deps() fx.Option {
 		repo.Module,
 		db.Module,
 		tx.Module,
+		country.Module,
 		lib.Module,
 	)
 }
@@ -2,6 +2,9 @@ package job
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -9,45 +12,146 @@ import (
 	"notifications/internal/api/transport/broker/stream"
 	"notifications/internal/api/transport/broker/subject"
 	"notifications/pkg/lib/broker/nats"
+	"notifications/pkg/lib/cache"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/scheduler"
 )
 
 var Module = fx.Invoke(New)
 
+// the worker which holds the lease is the leader, only the leader publishes the jobs
+const (
+	_leaderLease    = "worker:leader"
+	_leaderLeaseTTL = 30 * time.Second
+	_electEvery     = 10 * time.Second
+)
+
+// the daily jobs run at the fixed time in UTC, so they are published by whichever worker is the leader then
+// and a restart doesn't postpone them
+const (
+	_pushCleanCron      = "0 2 * * *"
+	_tokenValidatorCron = "0 3 * * *"
+)
+
 type Params struct {
 	fx.In
 	fx.Lifecycle
 
 	Scheduler scheduler.Scheduler
 	Nats      nats.Event
+	Cache     cache.Cache
 	Logger    logger.Logger
 }
 
+type worker struct {
+	Params
+
+	leader atomic.Bool
+}
+
 func New(p Params) {
-	_, _ = p.Scheduler.Every(1).Minute().Do(p.launchEventRunner)
-	_, _ = p.Scheduler.Every(60 * 24).Minute().Do(p.launchPushCleaner)
+	var (
+		w           = &worker{Params: p}
+		ctx, cancel = context.WithCancel(context.Background())
+	)
 
 	p.Lifecycle.Append(fx.Hook{
 		OnStart: func(_ context.Context) error {
+			// the scheduler is already started and fires the interval jobs right away,
+			// so the leader is elected before they are scheduled not to skip the first run
+			go w.elect(ctx, w.acquire(ctx))
+
+			_, _ = p.Scheduler.Every(1).Minute().Do(w.launchEventRunner)
+			_, _ = p.Scheduler.Every(10).Seconds().Do(w.launchOutboxRelay)
+			_, _ = p.Scheduler.Cron(_pushCleanCron).Do(w.launchPushCleaner)
+			_, _ = p.Scheduler.Cron(_tokenValidatorCron).Do(w.launchTokenValidator)
+
 			p.Logger.Info("Notification worker started")
 			return nil
 		},
 		OnStop: func(_ context.Context) error {
+			cancel()
 			p.Logger.Info("Notification worker stopped")
 			return nil
 		},
 	})
 }
 
-func (p Params) launchEventRunner() {
-	if err := p.Nats.Publish(stream.Notifications, subject.NotificationsJobEventRun, nil); err != nil {
-		p.Logger.Error("err publishing event run", zap.Error(err))
+// elect holds the leader lease until it's lost or the worker is stopped and acquires it again whenever it's free
+func (w *worker) elect(ctx context.Context, lease *cache.Lease) {
+	var ticker = time.NewTicker(_electEvery)
+	defer ticker.Stop()
+
+	for {
+		if lease != nil {
+			<-lease.Context().Done()
+
+			w.leader.Store(false)
+			w.Logger.Info("worker is not leader anymore", zap.Error(context.Cause(lease.Context())))
+
+			if err := lease.Release(context.WithoutCancel(ctx)); err != nil {
+				w.Logger.Error("err releasing leader lease", zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		lease = w.acquire(ctx)
+	}
+}
+
+// acquire tries to acquire the leader lease, nil if it's held by another worker
+func (w *worker) acquire(ctx context.Context) *cache.Lease {
+	lease, err := w.Cache.Acquire(ctx, _leaderLease, _leaderLeaseTTL)
+	if err != nil {
+		if !errors.Is(err, cache.ErrLocked) {
+			w.Logger.Error("err acquiring leader lease", zap.Error(err))
+		}
+		return nil
+	}
+
+	w.leader.Store(true)
+	w.Logger.Info("worker is elected as leader", zap.Int64("token", lease.Token()))
+
+	return lease
+}
+
+func (w *worker) launchEventRunner() {
+	if !w.leader.Load() {
+		return
+	}
+	if err := w.Nats.Publish(stream.Notifications, subject.NotificationsJobEventRun, nil); err != nil {
+		w.Logger.Error("err publishing event run", zap.Error(err))
+	}
+}
+
+func (w *worker) launchOutboxRelay() {
+	if !w.leader.Load() {
+		return
+	}
+	if err := w.Nats.Publish(stream.Notifications, subject.NotificationsJobOutboxRelayed, nil); err != nil {
+		w.Logger.Error("err publishing outbox relay", zap.Error(err))
+	}
+}
+
+func (w *worker) launchPushCleaner() {
+	if !w.leader.Load() {
+		return
+	}
+	if err := w.Nats.Publish(stream.Notifications, subject.NotificationsJobPushCleaned, nil); err != nil {
+		w.Logger.Error("err publishing push cleaned", zap.Error(err))
 	}
 }
 
-func (p Params) launchPushCleaner() {
-	if err := p.Nats.Publish(stream.Notifications, subject.NotificationsJobPushCleaned, nil); err != nil {
-		p.Logger.Error("err publishing push cleaned", zap.Error(err))
+func (w *worker) launchTokenValidator() {
+	if !w.leader.Load() {
+		return
+	}
+	if err := w.Nats.Publish(stream.Notifications, subject.NotificationsJobTokensValidated, nil); err != nil {
+		w.Logger.Error("err publishing tokens validated", zap.Error(err))
 	}
 }
@@ -5,6 +5,7 @@ import (
 
 	"notifications/cmd/worker/job"
 	"notifications/pkg/lib/broker/nats"
+	"notifications/pkg/lib/cache"
 	"notifications/pkg/lib/config"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/scheduler"
@@ -14,6 +15,7 @@ func main() {
 	fx.New(
 		job.Module,
 		nats.Module,
+		cache.Module,
 		logger.Module,
 		config.WorkerModule,
 		scheduler.Module,
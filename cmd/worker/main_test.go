@@ -7,6 +7,7 @@ import (
 
 	"notifications/cmd/worker/job"
 	"notifications/pkg/lib/broker/nats"
+	"notifications/pkg/lib/cache"
 	"notifications/pkg/lib/config"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/scheduler"
@@ -23,6 +24,7 @@ func deps() fx.Option {
 	return fx.Options(
 		job.Module,
 		nats.Module,
+		cache.Module,
 		logger.Module,
 		config.WorkerModule,
 		scheduler.Module,
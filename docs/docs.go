@@ -19,14 +19,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/notifications-external/v1/push": {
+        "/notifications-external/v1/analytics": {
             "post": {
                 "security": [
                     {
                         "SignatureAuth": []
                     }
                 ],
-                "description": "All fields except ` + "`" + `personExternalRef` + "`" + ` (crm_client_id) are required.\n- If you want to send push with ` + "`" + `personExternalRef` + "`" + `, do not provide ` + "`" + `phone` + "`" + `.\n- If ` + "`" + `showInFeed` + "`" + ` is true, the push will be shown in the feed; otherwise, it will be hidden.\n- If the users status is inactive or their push setting is disabled, the push will be saved in the feed but not sent to the device.\nIn that case, the payload will be ` + "`" + `inactive_user#fake_message_id` + "`" + ` or ` + "`" + `disabled_push#fake_message_id` + "`" + `.",
+                "description": "Reports the action of the user with the notification of the event or the push, one of ` + "`" + `eventID` + "`" + ` and ` + "`" + `pushID` + "`" + ` is required.\nThe action is counted once per user, the previous actions of the funnel are implied: the opened notification is delivered as well.",
                 "consumes": [
                     "application/json"
                 ],
@@ -60,7 +60,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Provide user action (push, sms) to send push",
+                        "description": "Provide user action (analytics) to report the action",
                         "name": "X-UserAction",
                         "in": "header",
                         "required": true
@@ -73,12 +73,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Request payload",
+                        "description": "Request",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/push.externalRequest"
+                            "$ref": "#/definitions/analytics.trackRequest"
                         }
                     }
                 ],
@@ -101,8 +101,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/resp.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not found",
+                    "403": {
+                        "description": "Permission denied",
                         "schema": {
                             "$ref": "#/definitions/resp.Response"
                         }
@@ -116,47 +116,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/notifications-internal/v1/events": {
+        "/notifications-external/v1/inbox/{userID}": {
             "get": {
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "SignatureAuth": []
+                    }
                 ],
+                "description": "Returns the inbox of the user (stateful pushes and events) from the newest to the oldest.\nPass ` + "`" + `nextCursor` + "`" + ` of the response as ` + "`" + `cursor` + "`" + ` to get the next page, empty ` + "`" + `nextCursor` + "`" + ` means there are no more items.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "External"
                 ],
-                "summary": "Get list of events",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "apply filter with id",
-                        "name": "id",
-                        "in": "query"
+                        "description": "Provide user ID created on the server side",
+                        "name": "X-UserId",
+                        "in": "header",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "apply filter with status",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Provide unique request ID to build hash and track the request",
+                        "name": "X-RequestId",
+                        "in": "header",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "apply filter with topic",
-                        "name": "topic",
-                        "in": "query"
+                        "description": "Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash",
+                        "name": "X-Date",
+                        "in": "header",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "apply filter with limit, 10 settled by default",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Provide user action (inbox) to read the inbox",
+                        "name": "X-UserAction",
+                        "in": "header",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "apply filter with offset, 0 settled by default",
-                        "name": "offset",
+                        "description": "Provide hash sum built with HMAC-SHA256 from the ` + "`" + `X-Date:X-RequestId` + "`" + ` using the secret key created on the server side",
+                        "name": "X-RequestDigest",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 20 by default, 100 at most",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
@@ -172,24 +198,27 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "payload": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/event.eventModel"
-                                            }
+                                            "$ref": "#/definitions/inbox.pageModel"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
                     "401": {
                         "description": "Invalid authorization data",
                         "schema": {
                             "$ref": "#/definitions/resp.Response"
                         }
                     },
-                    "404": {
-                        "description": "List not found",
+                    "403": {
+                        "description": "Permission denied",
                         "schema": {
                             "$ref": "#/definitions/resp.Response"
                         }
@@ -201,46 +230,85 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/notifications-external/v1/inbox/{userID}/items/{source}/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "SignatureAuth": []
+                    }
                 ],
+                "description": "Deletes the inbox item of the user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "External"
                 ],
-                "summary": "Create event",
                 "parameters": [
                     {
-                        "description": "Request",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/event.request"
-                        }
+                        "type": "string",
+                        "description": "Provide user ID created on the server side",
+                        "name": "X-UserId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide unique request ID to build hash and track the request",
+                        "name": "X-RequestId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash",
+                        "name": "X-Date",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide user action (inbox) to update the inbox",
+                        "name": "X-UserAction",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide hash sum built with HMAC-SHA256 from the ` + "`" + `X-Date:X-RequestId` + "`" + ` using the secret key created on the server side",
+                        "name": "X-RequestDigest",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item source (push, event)",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/resp.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "payload": {
-                                            "$ref": "#/definitions/event.eventModel"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/resp.Response"
                         }
                     },
                     "400": {
@@ -255,6 +323,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/resp.Response"
                         }
                     },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Error",
                         "schema": {
@@ -264,46 +344,83 @@ const docTemplate = `{
                 }
             }
         },
-        "/notifications-internal/v1/events/{id}": {
+        "/notifications-external/v1/inbox/{userID}/items/{source}/{id}/read": {
             "put": {
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "SignatureAuth": []
+                    }
                 ],
+                "description": "Marks the inbox item as read.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "External"
                 ],
-                "summary": "Update event",
                 "parameters": [
                     {
-                        "description": "Request",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/event.request"
-                        }
+                        "type": "string",
+                        "description": "Provide user ID created on the server side",
+                        "name": "X-UserId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide unique request ID to build hash and track the request",
+                        "name": "X-RequestId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash",
+                        "name": "X-Date",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide user action (inbox) to update the inbox",
+                        "name": "X-UserAction",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide hash sum built with HMAC-SHA256 from the ` + "`" + `X-Date:X-RequestId` + "`" + ` using the secret key created on the server side",
+                        "name": "X-RequestDigest",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item source (push, event)",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/resp.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "payload": {
-                                            "$ref": "#/definitions/event.eventModel"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/resp.Response"
                         }
                     },
                     "400": {
@@ -318,6 +435,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/resp.Response"
                         }
                     },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not found",
                         "schema": {
@@ -331,22 +454,76 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/notifications-external/v1/inbox/{userID}/read-all": {
+            "put": {
+                "security": [
+                    {
+                        "SignatureAuth": []
+                    }
                 ],
+                "description": "Marks all the inbox items of the user as read.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "External"
                 ],
-                "summary": "Delete event",
-                "responses": {
-                    "200": {
-                        "description": "Success",
-                        "schema": {
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provide user ID created on the server side",
+                        "name": "X-UserId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide unique request ID to build hash and track the request",
+                        "name": "X-RequestId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash",
+                        "name": "X-Date",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide user action (inbox) to update the inbox",
+                        "name": "X-UserAction",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide hash sum built with HMAC-SHA256 from the ` + "`" + `X-Date:X-RequestId` + "`" + ` using the secret key created on the server side",
+                        "name": "X-RequestDigest",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
                             "$ref": "#/definitions/resp.Response"
                         }
                     },
@@ -356,8 +533,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/resp.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not found",
+                    "403": {
+                        "description": "Permission denied",
                         "schema": {
                             "$ref": "#/definitions/resp.Response"
                         }
@@ -371,25 +548,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/notifications-internal/v1/events/{id}/image/{language}": {
-            "post": {
-                "description": "Provider event id - ` + "`" + `{id}` + "`" + ` and multi-lang image key - ` + "`" + `{language}` + "`" + ` as api route var to upload image for event",
-                "consumes": [
-                    "multipart/form-data"
+        "/notifications-external/v1/inbox/{userID}/unread-count": {
+            "get": {
+                "security": [
+                    {
+                        "SignatureAuth": []
+                    }
                 ],
+                "description": "Returns the number of unread inbox items of the user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "External"
                 ],
-                "summary": "Upload image",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Load images with png, jpg, jpeg extensions",
-                        "name": "image",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "Provide user ID created on the server side",
+                        "name": "X-UserId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide unique request ID to build hash and track the request",
+                        "name": "X-RequestId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash",
+                        "name": "X-Date",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide user action (inbox) to read the inbox",
+                        "name": "X-UserAction",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide hash sum built with HMAC-SHA256 from the ` + "`" + `X-Date:X-RequestId` + "`" + ` using the secret key created on the server side",
+                        "name": "X-RequestDigest",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -405,7 +618,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "payload": {
-                                            "$ref": "#/definitions/event.eventModel"
+                                            "$ref": "#/definitions/inbox.countModel"
                                         }
                                     }
                                 }
@@ -424,8 +637,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/resp.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not found",
+                    "403": {
+                        "description": "Permission denied",
                         "schema": {
                             "$ref": "#/definitions/resp.Response"
                         }
@@ -437,16 +650,66 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Provider event id - ` + "`" + `{id}` + "`" + ` and multi-lang image key - ` + "`" + `{language}` + "`" + ` as api route var to remove specific image of event",
+            }
+        },
+        "/notifications-external/v1/preferences/{userID}": {
+            "get": {
+                "security": [
+                    {
+                        "SignatureAuth": []
+                    }
+                ],
+                "description": "Returns the notification preferences of the user for every category and channel, the ones never changed are enabled.\nNon-suppressible categories (otp) are always enabled, they are changed with ` + "`" + `preferences` + "`" + ` of the user settings update.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "External"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provide user ID created on the server side",
+                        "name": "X-UserId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide unique request ID to build hash and track the request",
+                        "name": "X-RequestId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash",
+                        "name": "X-Date",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide user action (preferences) to read the preferences",
+                        "name": "X-UserAction",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide hash sum built with HMAC-SHA256 from the ` + "`" + `X-Date:X-RequestId` + "`" + ` using the secret key created on the server side",
+                        "name": "X-RequestDigest",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Remove image",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -459,7 +722,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "payload": {
-                                            "$ref": "#/definitions/event.eventModel"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/preference.preferenceModel"
+                                            }
                                         }
                                     }
                                 }
@@ -478,8 +744,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/resp.Response"
                         }
                     },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
                     "404": {
-                        "description": "Not found",
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/resp.Response"
                         }
@@ -493,32 +765,74 @@ const docTemplate = `{
                 }
             }
         },
-        "/notifications-internal/v1/events/{id}/load-all-users": {
+        "/notifications-external/v1/push": {
             "post": {
+                "security": [
+                    {
+                        "SignatureAuth": []
+                    }
+                ],
+                "description": "All fields except ` + "`" + `personExternalRef` + "`" + ` (crm_client_id) are required.\n- If you want to send push with ` + "`" + `personExternalRef` + "`" + `, do not provide ` + "`" + `phone` + "`" + `.\n- If ` + "`" + `showInFeed` + "`" + ` is true, the push will be shown in the feed; otherwise, it will be hidden.\n- If the users status is inactive or their push setting is disabled, the push will be saved in the feed but not sent to the device.\nIn that case, the payload will be ` + "`" + `inactive_user#fake_message_id` + "`" + ` or ` + "`" + `disabled_push#fake_message_id` + "`" + `.\n- If the user opted out of the push category, the push is neither saved nor sent, the payload will be ` + "`" + `opted_out#fake_message_id` + "`" + `.\n- ` + "`" + `push` + "`" + ` type pushes landing inside the quiet hours of the user are sent at their end, the payload will be ` + "`" + `quiet_hours#fake_message_id` + "`" + `.\n` + "`" + `otp` + "`" + ` pushes are sent right away.\n- The request consumes the rate limit and daily/monthly quotas of the api client, remaining values are returned in\n` + "`" + `X-RateLimit-Remaining` + "`" + `, ` + "`" + `X-Quota-Daily-Remaining` + "`" + ` and ` + "`" + `X-Quota-Monthly-Remaining` + "`" + ` headers.\n- The delivery status of the push can be checked by ` + "`" + `X-RequestId` + "`" + ` with ` + "`" + `GET /notifications-external/v1/push/{id}` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "External"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provide user ID created on the server side",
+                        "name": "X-UserId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide unique request ID to build hash and track the request",
+                        "name": "X-RequestId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash",
+                        "name": "X-Date",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide user action (push, sms) to send push",
+                        "name": "X-UserAction",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide hash sum built with HMAC-SHA256 from the ` + "`" + `X-Date:X-RequestId` + "`" + ` using the secret key created on the server side",
+                        "name": "X-RequestDigest",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Request payload",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/push.externalRequest"
+                        }
+                    }
                 ],
-                "summary": "Subscribe all users to the event",
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "Success",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/resp.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "payload": {
-                                            "$ref": "#/definitions/event.eventModel"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/resp.Response"
                         }
                     },
                     "400": {
@@ -539,6 +853,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/resp.Response"
                         }
                     },
+                    "429": {
+                        "description": "Rate limit or quota exceeded, see ` + "`" + `Retry-After` + "`" + ` header",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Error",
                         "schema": {
@@ -548,30 +868,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/notifications-internal/v1/events/{id}/load-users": {
-            "post": {
-                "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+        "/notifications-external/v1/push/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "SignatureAuth": []
+                    }
+                ],
+                "description": "Returns the delivery status of the push sent by the api client, ` + "`" + `id` + "`" + ` is the ` + "`" + `X-RequestId` + "`" + ` of the send request.\nStatuses:\n- ` + "`" + `queued` + "`" + ` - the push is accepted and is being sent\n- ` + "`" + `sent` + "`" + ` - the push is sent to FCM, ` + "`" + `messageID` + "`" + ` is the FCM message ID\n- ` + "`" + `failed` + "`" + ` - FCM returned an error, see ` + "`" + `errorCode` + "`" + ` and ` + "`" + `errorMessage` + "`" + `\n- ` + "`" + `token_invalid` + "`" + ` - the device token of the user is not valid anymore\n- ` + "`" + `suppressed_inactive` + "`" + ` - the user is inactive, the push is saved in the feed only\n- ` + "`" + `suppressed_disabled` + "`" + ` - the user disabled pushes, the push is saved in the feed only\n- ` + "`" + `suppressed_opted_out` + "`" + ` - the user opted out of the push category, the push is neither sent nor saved\n- ` + "`" + `deferred_quiet_hours` + "`" + ` - the push landed inside the quiet hours of the user, it will be sent at their end",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Events"
+                    "External"
                 ],
-                "summary": "Subscribe list of users to event",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "CSV file with ` + "`" + `userID` + "`" + ` header and data. Do not provide other headers",
-                        "name": "users",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "Provide user ID created on the server side",
+                        "name": "X-UserId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide unique request ID to build hash and track the request",
+                        "name": "X-RequestId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash",
+                        "name": "X-Date",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide user action (push) to get the push status",
+                        "name": "X-UserAction",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide hash sum built with HMAC-SHA256 from the ` + "`" + `X-Date:X-RequestId` + "`" + ` using the secret key created on the server side",
+                        "name": "X-RequestDigest",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-RequestId of the send request",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "Success",
                         "schema": {
                             "allOf": [
                                 {
@@ -581,7 +938,183 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "payload": {
-                                            "$ref": "#/definitions/event.eventModel"
+                                            "$ref": "#/definitions/push.deliveryModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-external/v1/usage": {
+            "get": {
+                "security": [
+                    {
+                        "SignatureAuth": []
+                    }
+                ],
+                "description": "Returns the rate limit and daily/monthly quotas of the api client with the current usage.\nQuotas are counted per UTC day and month, ` + "`" + `0` + "`" + ` limit and ` + "`" + `-1` + "`" + ` remaining mean the quota is unlimited.\nThe request doesn't consume the quota.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "External"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provide user ID created on the server side",
+                        "name": "X-UserId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide unique request ID to build hash and track the request",
+                        "name": "X-RequestId",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash",
+                        "name": "X-Date",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide any user action allowed for the api client",
+                        "name": "X-UserAction",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provide hash sum built with HMAC-SHA256 from the ` + "`" + `X-Date:X-RequestId` + "`" + ` using the secret key created on the server side",
+                        "name": "X-RequestDigest",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/apiclient.usageModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/dlq": {
+            "get": {
+                "description": "Returns the messages moved to the dead letter queue after all the delivery attempts failed, from the oldest to the newest.\nPass ` + "`" + `nextCursor` + "`" + ` of the response as ` + "`" + `cursor` + "`" + ` to get the next page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DLQ"
+                ],
+                "summary": "Get list of dlq messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "apply filter with dlq subject, e.g. notifications.dlq.push.sent",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "sequence to start from",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "apply filter with limit, 20 settled by default, 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/dlq.pageModel"
                                         }
                                     }
                                 }
@@ -600,8 +1133,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/resp.Response"
                         }
                     },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
                     "404": {
-                        "description": "Not found",
+                        "description": "List not found",
                         "schema": {
                             "$ref": "#/definitions/resp.Response"
                         }
@@ -615,71 +1154,2588 @@ const docTemplate = `{
                 }
             }
         },
-        "/notifications-internal/v1/events/{id}/run": {
-            "post": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Events"
-                ],
-                "summary": "Run event manually",
-                "responses": {
-                    "200": {
-                        "description": "Success",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/resp.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "payload": {
-                                            "$ref": "#/definitions/event.eventModel"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "$ref": "#/definitions/resp.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Invalid authorization data",
-                        "schema": {
-                            "$ref": "#/definitions/resp.Response"
-                        }
+        "/notifications-internal/v1/dlq/{seq}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DLQ"
+                ],
+                "summary": "Get dlq message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sequence of the message in the dlq stream",
+                        "name": "seq",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/dlq.messageModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/dlq/{seq}/replay": {
+            "post": {
+                "description": "Publishes the message to its original subject as a new message with a fresh retry budget and removes it from the dlq.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DLQ"
+                ],
+                "summary": "Replay dlq message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sequence of the message in the dlq stream",
+                        "name": "seq",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get list of events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "apply filter with id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply filter with status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply filter with topic",
+                        "name": "topic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply filter with country ID",
+                        "name": "countryID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply filter with limit, 10 settled by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply filter with offset, 0 settled by default",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/event.eventModel"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "List not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Create event",
+                "parameters": [
+                    {
+                        "description": "Request",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/event.request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/audience/estimate": {
+            "post": {
+                "description": "Counts the users of the segment without loading them, reachable users have a token and didn't opt out of marketing pushes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Estimate audience of segment",
+                "parameters": [
+                    {
+                        "description": "Segment",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/event.segment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.estimateModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Update event",
+                "parameters": [
+                    {
+                        "description": "Request",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/event.request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Delete event",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/approve": {
+            "post": {
+                "description": "Moves the submitted event to scheduled, the admin who submitted the event cannot approve it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Approve event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/cancel": {
+            "post": {
+                "description": "Cancels the event which is pending approval, scheduled or ready, the loaded users are unsubscribed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Cancel event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/image/{language}": {
+            "post": {
+                "description": "Provider event id - ` + "`" + `{id}` + "`" + ` and multi-lang image key - ` + "`" + `{language}` + "`" + ` as api route var to upload image for event",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Upload image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Load images with png, jpg, jpeg extensions",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Provider event id - ` + "`" + `{id}` + "`" + ` and multi-lang image key - ` + "`" + `{language}` + "`" + ` as api route var to remove specific image of event",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Remove image",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/load-all-users": {
+            "post": {
+                "description": "Subscribes the users of the event segment if the event has one, the users of the event country otherwise.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Subscribe all users to the event",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/load-users": {
+            "post": {
+                "description": "The users are referred by the ` + "`" + `userID` + "`" + `, ` + "`" + `personExternalRef` + "`" + ` or ` + "`" + `phone` + "`" + ` column of the file header, the other columns are ignored.\nThe phones are normalized to the international format, the national numbers are completed with the calling code of the event country.\nThe counts are saved to the event ` + "`" + `extraData` + "`" + `, the rejected rows (invalid, not_found, deleted, no_token, other_country, opted_out) are in the report.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Subscribe list of users to event",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file, the first sheet of XLSX is read",
+                        "name": "users",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/reject": {
+            "post": {
+                "description": "Gets the submitted or scheduled event back to draft.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Reject event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/rejected-users": {
+            "get": {
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Download report of rejected users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV with line, value and reason columns",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/run": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Run event manually",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/runs": {
+            "get": {
+                "description": "Returns the runs of the event starting from the last one, the recurring events have one per occurrence.\nThe funnel of the run is returned by the stats of the event with ` + "`" + `runID` + "`" + `.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get runs of event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply filter with limit, 20 settled by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply filter with offset, 0 settled by default",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/event.runModel"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/runs/{runID}/deliveries": {
+            "get": {
+                "description": "Returns the results of the users of the run of the event sent directly, the invalid tokens are removed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get deliveries of run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Run ID",
+                        "name": "runID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply filter with limit, 20 settled by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply filter with offset, 0 settled by default",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/event.deliveryModel"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/runs/{runID}/progress": {
+            "get": {
+                "description": "Returns the progress of the run with the percent of the processed users and the estimated time of the end,\nthe run of the crashed pod is resumed from the last checkpoint.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get progress of run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Run ID",
+                        "name": "runID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.progressModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/stats": {
+            "get": {
+                "description": "Returns the funnel of the event: targeted users are counted when the event is run, the rest are reported by the app.\nThe rates are the shares of the previous step of the funnel, the events with A/B test are broken down by variant as well.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get stats of event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Run ID, the funnel of the run is returned",
+                        "name": "runID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/analytics.eventStatsModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/submit": {
+            "post": {
+                "description": "Moves the draft to pending_approval, it must be approved by another admin to be scheduled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Submit event for approval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/events/{id}/winner": {
+            "post": {
+                "description": "Sends the variant to the holdout of the sent event, the empty variant picks the one with the best click rate.\nThe winner is sent automatically after ` + "`" + `winnerAfter` + "`" + ` minutes if it's set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Send winner of A/B test",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request",
+                        "name": "data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/event.winnerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/event.eventModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/messages/{id}": {
+            "get": {
+                "description": "Returns the message sent by ` + "`" + `notifications.message.send` + "`" + ` with the outcome of every channel of its policy.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Get message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the request given by the caller",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/message.messageModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/templates": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get list of templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "apply filter with limit, 20 settled by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply filter with offset, 0 settled by default",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/template.templateModel"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "List not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Texts are Go templates, variables are referred as ` + "`" + `{{.amount}}` + "`" + ` and must be declared with the type: string, number, integer or date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Create template",
+                "parameters": [
+                    {
+                        "description": "Request",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/template.request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/template.templateModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/templates/{key}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/template.templateModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Update template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/template.request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/template.templateModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Delete template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications-internal/v1/templates/{key}/preview": {
+            "post": {
+                "description": "Renders the template with the variables in the language, the same way it's rendered for the recipients.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Preview template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/template.previewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resp.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/template.contentModel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid authorization data",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/resp.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "analytics.eventStatsModel": {
+            "type": "object",
+            "properties": {
+                "clickRate": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "clicked": {
+                    "type": "integer"
+                },
+                "delivered": {
+                    "type": "integer"
+                },
+                "deliveryRate": {
+                    "type": "number",
+                    "example": 0.95
+                },
+                "eventID": {
+                    "type": "integer"
+                },
+                "languages": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/analytics.funnelModel"
+                    }
+                },
+                "openRate": {
+                    "type": "number",
+                    "example": 0.2
+                },
+                "opened": {
+                    "type": "integer"
+                },
+                "runID": {
+                    "type": "integer"
+                },
+                "targeted": {
+                    "type": "integer"
+                },
+                "variants": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/analytics.funnelModel"
+                    }
+                }
+            }
+        },
+        "analytics.funnelModel": {
+            "type": "object",
+            "properties": {
+                "clickRate": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "clicked": {
+                    "type": "integer"
+                },
+                "delivered": {
+                    "type": "integer"
+                },
+                "deliveryRate": {
+                    "type": "number",
+                    "example": 0.95
+                },
+                "openRate": {
+                    "type": "number",
+                    "example": 0.2
+                },
+                "opened": {
+                    "type": "integer"
+                },
+                "targeted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "analytics.trackRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "userID"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "delivered, opened, clicked"
+                },
+                "eventID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "language": {
+                    "type": "string",
+                    "example": "ru"
+                },
+                "pushID": {
+                    "type": "integer"
+                },
+                "runID": {
+                    "type": "integer"
+                },
+                "userID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "variant": {
+                    "type": "string",
+                    "example": "a"
+                }
+            }
+        },
+        "apiclient.quotaModel": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "remaining": {
+                    "type": "integer",
+                    "example": -1
+                },
+                "resetAt": {
+                    "type": "string"
+                },
+                "used": {
+                    "type": "integer"
+                }
+            }
+        },
+        "apiclient.usageModel": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "type": "string"
+                },
+                "daily": {
+                    "$ref": "#/definitions/apiclient.quotaModel"
+                },
+                "monthly": {
+                    "$ref": "#/definitions/apiclient.quotaModel"
+                },
+                "rateLimit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dlq.messageModel": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "consumer": {
+                    "type": "string",
+                    "example": "notifications-push-processor"
+                },
+                "data": {
+                    "type": "object"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failedAt": {
+                    "type": "string"
+                },
+                "originalStream": {
+                    "type": "string",
+                    "example": "notifications"
+                },
+                "originalSubject": {
+                    "type": "string",
+                    "example": "notifications.push.sent"
+                },
+                "receivedAt": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "notifications.dlq.push.sent"
+                }
+            }
+        },
+        "dlq.pageModel": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dlq.messageModel"
+                    }
+                },
+                "nextCursor": {
+                    "type": "integer"
+                }
+            }
+        },
+        "event.abTest": {
+            "type": "object",
+            "properties": {
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/event.variant"
+                    }
+                },
+                "winnerAfter": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "event.deliveryModel": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "errorCode": {
+                    "type": "string",
+                    "example": "UNREGISTERED"
+                },
+                "fcmMessageID": {
+                    "type": "string"
+                },
+                "runID": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "failed"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "event.estimateModel": {
+            "type": "object",
+            "properties": {
+                "reachable": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "event.eventModel": {
+            "type": "object",
+            "properties": {
+                "abTest": {
+                    "$ref": "#/definitions/event.abTest"
+                },
+                "approvedBy": {
+                    "type": "integer"
+                },
+                "body": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "countryID": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "delivery": {
+                    "type": "string",
+                    "example": "topic"
+                },
+                "extraData": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "$ref": "#/definitions/event.recurrence"
+                },
+                "scheduledAt": {
+                    "type": "string"
+                },
+                "segment": {
+                    "$ref": "#/definitions/event.segment"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "scheduled"
+                },
+                "submittedBy": {
+                    "type": "integer"
+                },
+                "title": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "topic": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "event.progressModel": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "eta": {
+                    "type": "string"
+                },
+                "eventID": {
+                    "type": "integer"
+                },
+                "failedCount": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "number",
+                    "example": 42.5
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "runID": {
+                    "type": "integer"
+                },
+                "stage": {
+                    "type": "string",
+                    "enum": [
+                        "users",
+                        "send",
+                        "finish"
+                    ]
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "running",
+                        "sent",
+                        "failed"
+                    ]
+                },
+                "successCount": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "event.recurrence": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "type": "string",
+                    "example": "0 10 * * 1"
+                },
+                "endAt": {
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "maxOccurrences": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "event.request": {
+            "type": "object",
+            "properties": {
+                "abTest": {
+                    "$ref": "#/definitions/event.abTest"
+                },
+                "body": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "countryID": {
+                    "type": "integer"
+                },
+                "delivery": {
+                    "type": "string",
+                    "enum": [
+                        "topic",
+                        "direct"
+                    ],
+                    "example": "topic"
+                },
+                "extraData": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "link": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "$ref": "#/definitions/event.recurrence"
+                },
+                "scheduledAt": {
+                    "type": "string"
+                },
+                "segment": {
+                    "$ref": "#/definitions/event.segment"
+                },
+                "status": {
+                    "description": "Status is draft or pending_approval to submit the event for the approval right away",
+                    "type": "string",
+                    "example": "draft"
+                },
+                "title": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "event.runModel": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "eventID": {
+                    "type": "integer"
+                },
+                "failedCount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "scheduledAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "sent"
+                },
+                "successCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "event.segment": {
+            "type": "object",
+            "properties": {
+                "countryID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "createdAfter": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "createdBefore": {
+                    "type": "string"
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     },
-                    "404": {
-                        "description": "Not found",
-                        "schema": {
-                            "$ref": "#/definitions/resp.Response"
-                        }
+                    "example": [
+                        "uz"
+                    ]
+                },
+                "pushEnabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     },
-                    "500": {
-                        "description": "Internal Error",
-                        "schema": {
-                            "$ref": "#/definitions/resp.Response"
-                        }
-                    }
+                    "example": [
+                        "active"
+                    ]
                 }
             }
-        }
-    },
-    "definitions": {
-        "event.eventModel": {
+        },
+        "event.variant": {
             "type": "object",
             "properties": {
                 "body": {
                     "$ref": "#/definitions/language.Language"
                 },
-                "category": {
-                    "type": "string"
+                "image": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "a"
+                },
+                "percent": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "title": {
+                    "$ref": "#/definitions/language.Language"
+                }
+            }
+        },
+        "event.winnerRequest": {
+            "type": "object",
+            "properties": {
+                "variant": {
+                    "type": "string",
+                    "example": "a"
+                }
+            }
+        },
+        "inbox.countModel": {
+            "type": "object",
+            "properties": {
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "inbox.itemModel": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/language.Language"
                 },
                 "createdAt": {
                     "type": "string"
@@ -696,71 +3752,191 @@ const docTemplate = `{
                 "image": {
                     "$ref": "#/definitions/language.Language"
                 },
-                "link": {
+                "isRead": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "push, event"
+                },
+                "title": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "type": {
                     "type": "string"
+                }
+            }
+        },
+        "inbox.pageModel": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/inbox.itemModel"
+                    }
                 },
-                "scheduledAt": {
+                "nextCursor": {
                     "type": "string"
+                }
+            }
+        },
+        "language.Language": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "message.attemptModel": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "enum": [
+                        "push",
+                        "sms",
+                        "email",
+                        "telegram"
+                    ]
                 },
-                "status": {
+                "error": {
                     "type": "string"
                 },
-                "title": {
-                    "$ref": "#/definitions/language.Language"
+                "finishedAt": {
+                    "type": "string"
                 },
-                "topic": {
+                "messageID": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "startedAt": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "sent",
+                        "failed",
+                        "skipped"
+                    ]
                 }
             }
         },
-        "event.request": {
+        "message.messageModel": {
             "type": "object",
             "properties": {
-                "body": {
-                    "$ref": "#/definitions/language.Language"
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/message.attemptModel"
+                    }
                 },
-                "category": {
+                "channel": {
+                    "type": "string",
+                    "enum": [
+                        "push",
+                        "sms",
+                        "email",
+                        "telegram"
+                    ]
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "extraData": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                "id": {
+                    "type": "integer"
                 },
-                "link": {
+                "phone": {
                     "type": "string"
                 },
-                "scheduledAt": {
+                "policy": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/message.stepModel"
+                    }
+                },
+                "requestID": {
                     "type": "string"
                 },
                 "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "delivered",
+                        "failed"
+                    ]
+                },
+                "updatedAt": {
                     "type": "string"
                 },
-                "title": {
-                    "$ref": "#/definitions/language.Language"
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "message.stepModel": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "enum": [
+                        "push",
+                        "sms",
+                        "email",
+                        "telegram"
+                    ]
                 },
-                "topic": {
+                "timeout": {
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "preference.preferenceModel": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "otp, transactional, marketing"
+                },
+                "channel": {
+                    "type": "string",
+                    "example": "push, sms, email"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "suppressible": {
+                    "type": "boolean"
+                },
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "language.Language": {
+        "push.deliveryModel": {
             "type": "object",
             "properties": {
-                "en": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "errorCode": {
+                    "type": "string",
+                    "example": "UNREGISTERED"
+                },
+                "errorMessage": {
                     "type": "string"
                 },
-                "ru": {
+                "messageID": {
                     "type": "string"
                 },
-                "tg": {
+                "requestID": {
                     "type": "string"
                 },
-                "uz": {
+                "status": {
+                    "type": "string",
+                    "example": "queued, sent, failed, token_invalid, suppressed_inactive, suppressed_disabled, suppressed_opted_out, deferred_quiet_hours"
+                },
+                "updatedAt": {
                     "type": "string"
                 }
             }
@@ -809,6 +3985,101 @@ const docTemplate = `{
                 },
                 "payload": {}
             }
+        },
+        "template.contentModel": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "ru"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "template.previewRequest": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string",
+                    "example": "ru"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "template.request": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "payment.received"
+                },
+                "title": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/template.variable"
+                    }
+                }
+            }
+        },
+        "template.templateModel": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "payment.received"
+                },
+                "title": {
+                    "$ref": "#/definitions/language.Language"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/template.variable"
+                    }
+                }
+            }
+        },
+        "template.variable": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "amount"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "number",
+                        "integer",
+                        "date"
+                    ],
+                    "example": "number"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -1,6 +1,8 @@
 package broker
 
 import (
+	"time"
+
 	"go.uber.org/fx"
 
 	"notifications/internal/api/transport/broker/consumer"
@@ -8,6 +10,9 @@ import (
 	"notifications/internal/api/transport/broker/subject"
 	"notifications/internal/handler/broker/email"
 	"notifications/internal/handler/broker/event"
+	"notifications/internal/handler/broker/inbox"
+	"notifications/internal/handler/broker/message"
+	"notifications/internal/handler/broker/outbox"
 	"notifications/internal/handler/broker/push"
 	"notifications/internal/handler/broker/sms"
 	"notifications/internal/handler/broker/telegram"
@@ -17,37 +22,72 @@ import (
 
 var Module = fx.Options(fx.Invoke(RegisterEvents))
 
+// retry policies of the notifiers, exhausted messages are moved to the dlq and can be replayed by admin
+var (
+	_pushRetryPolicy = nats.RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     2 * time.Second,
+		MaxBackoff:  time.Minute,
+		DlqStream:   stream.NotificationsDlq,
+		DlqSubject:  subject.NotificationsDlqPushSent,
+	}
+	_smsRetryPolicy = nats.RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     5 * time.Second,
+		MaxBackoff:  2 * time.Minute,
+		DlqStream:   stream.NotificationsDlq,
+		DlqSubject:  subject.NotificationsDlqSmsSent,
+	}
+	_messageRetryPolicy = nats.RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     5 * time.Second,
+		MaxBackoff:  2 * time.Minute,
+		DlqStream:   stream.NotificationsDlq,
+		DlqSubject:  subject.NotificationsDlqMessage,
+	}
+)
+
+// _messageAckWait covers the timeouts of all the channels of the message policy
+const _messageAckWait = 10 * time.Minute
+
 type Params struct {
 	fx.In
 
 	Nats nats.Event
 
-	User  user.Handler
-	Push  push.Handler
-	Event event.Handler
-	Email email.Handler
-	Sms   sms.Handler
-	Tg    telegram.Handler
+	User    user.Handler
+	Push    push.Handler
+	Event   event.Handler
+	Email   email.Handler
+	Sms     sms.Handler
+	Tg      telegram.Handler
+	Inbox   inbox.Handler
+	Outbox  outbox.Handler
+	Message message.Handler
 }
 
 func RegisterEvents(p Params) {
 	// user data manager
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsUserCreated, consumer.NotificationsUserProcessor, p.User.UserCreated)
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsFcmRegistrationTokenUpdated, consumer.NotificationsFcmTokenProcessor, p.User.TokenUpdated)
+	p.Nats.Subscribe(stream.Notifications, subject.NotificationsFcmRegistrationTokenRemoved, consumer.NotificationsFcmTokenRemovedProcessor, p.User.TokenRemoved)
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsUserStatusUpdated, consumer.NotificationsUserStatusProcessor, p.User.StatusUpdated)
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsUserSettingsUpdated, consumer.NotificationsUserSettingsProcessor, p.User.SettingsUpdated)
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsUserPhoneUpdated, consumer.NotificationsUserPhoneProcessor, p.User.PhoneUpdated)
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsUserPersonRefUpdated, consumer.NotificationsUserPersonRefProcessor, p.User.PersonExternalRefUpdated)
 	// notifier
-	p.Nats.Subscribe(stream.Notifications, subject.NotificationsPushSent, consumer.NotificationsPushProcessor, p.Push.Sent, nats.WithMaxDelivery(1))
+	p.Nats.SubscribeWithRetry(stream.Notifications, subject.NotificationsPushSent, consumer.NotificationsPushProcessor, p.Push.Sent, _pushRetryPolicy)
+	p.Nats.SubscribeWithRetry(stream.Notifications, subject.NotificationsPushDeferred, consumer.NotificationsPushDeferredProcessor, p.Push.Deferred, _pushRetryPolicy)
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsEmailSent, consumer.NotificationsEmailProcessor, p.Email.Sent)
-	p.Nats.Subscribe(stream.Notifications, subject.NotificationsSmsSent, consumer.NotificationsSmsProcessor, p.Sms.Sent)
+	p.Nats.SubscribeWithRetry(stream.Notifications, subject.NotificationsSmsSent, consumer.NotificationsSmsProcessor, p.Sms.Sent, _smsRetryPolicy)
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsTgSent, consumer.NotificationsTgProcessor, p.Tg.Sent)
+	p.Nats.SubscribeWithRetry(stream.Notifications, subject.NotificationsMessageSend, consumer.NotificationsMessageProcessor, p.Message.Send, _messageRetryPolicy, nats.WithAckWait(_messageAckWait))
 	// fcm topic subscribe/unsubscribe
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsTopicUsersSubscribed, consumer.NotificationsTopicUsersSubProcessor, p.Event.TopicSubscribed)
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsTopicUsersUnsubscribed, consumer.NotificationsTopicUsersUnsubProcessor, p.Event.TopicUnsubscribed)
 
 	p.Nats.Reply(subject.NotificationsSyncPushSent, consumer.NotificationsGroup, p.Push.SyncSent)
+	p.Nats.Reply(subject.NotificationsSyncInbox, consumer.NotificationsGroup, p.Inbox.Sync)
 
 	p.registerJobs()
 }
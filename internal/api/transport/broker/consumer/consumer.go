@@ -1,24 +1,29 @@
 package consumer
 
 const (
-	NotificationsFcmTokenProcessor      = "notifications-fcm-token-processor"
-	NotificationsUserStatusProcessor    = "notifications-user-status-processor"
-	NotificationsUserPhoneProcessor     = "notifications-user-phone-processor"
-	NotificationsUserPersonRefProcessor = "notifications-user-personref-processor"
-	NotificationsUserSettingsProcessor  = "notifications-user-settings-processor"
-	NotificationsUserProcessor          = "notifications-user-processor"
+	NotificationsFcmTokenProcessor        = "notifications-fcm-token-processor"
+	NotificationsFcmTokenRemovedProcessor = "notifications-fcm-token-removed-processor"
+	NotificationsUserStatusProcessor      = "notifications-user-status-processor"
+	NotificationsUserPhoneProcessor       = "notifications-user-phone-processor"
+	NotificationsUserPersonRefProcessor   = "notifications-user-personref-processor"
+	NotificationsUserSettingsProcessor    = "notifications-user-settings-processor"
+	NotificationsUserProcessor            = "notifications-user-processor"
 )
 
 const (
-	NotificationsPushProcessor  = "notifications-push-processor"
-	NotificationsEmailProcessor = "notifications-email-processor"
-	NotificationsSmsProcessor   = "notifications-sms-processor"
-	NotificationsTgProcessor    = "notifications-tg-processor"
+	NotificationsPushProcessor         = "notifications-push-processor"
+	NotificationsPushDeferredProcessor = "notifications-push-deferred-processor"
+	NotificationsEmailProcessor        = "notifications-email-processor"
+	NotificationsSmsProcessor          = "notifications-sms-processor"
+	NotificationsTgProcessor           = "notifications-tg-processor"
+	NotificationsMessageProcessor      = "notifications-message-processor"
 )
 
 const (
-	NotificationsJobEventRunProcessor  = "notifications-job-event-run-processor"
-	NotificationsJobPushCleanProcessor = "notifications-job-push-clean-processor"
+	NotificationsJobEventRunProcessor       = "notifications-job-event-run-processor"
+	NotificationsJobPushCleanProcessor      = "notifications-job-push-clean-processor"
+	NotificationsJobOutboxRelayProcessor    = "notifications-job-outbox-relay-processor"
+	NotificationsJobTokensValidateProcessor = "notifications-job-tokens-validate-processor"
 )
 
 const (
@@ -9,4 +9,6 @@ import (
 func (p Params) registerJobs() {
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsJobEventRun, consumer.NotificationsJobEventRunProcessor, p.Event.Run)
 	p.Nats.Subscribe(stream.Notifications, subject.NotificationsJobPushCleaned, consumer.NotificationsJobPushCleanProcessor, p.Push.Clean)
+	p.Nats.Subscribe(stream.Notifications, subject.NotificationsJobOutboxRelayed, consumer.NotificationsJobOutboxRelayProcessor, p.Outbox.Relay)
+	p.Nats.Subscribe(stream.Notifications, subject.NotificationsJobTokensValidated, consumer.NotificationsJobTokensValidateProcessor, p.User.ValidateTokens)
 }
@@ -1,6 +1,7 @@
 package stream
 
 const (
-	Notifications = "notifications"
-	Audit         = "audit"
+	Notifications    = "notifications"
+	NotificationsDlq = "notifications_dlq"
+	Audit            = "audit"
 )
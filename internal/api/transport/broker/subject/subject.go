@@ -11,15 +11,19 @@ const (
 )
 
 const (
-	NotificationsPushSent  = "notifications.push.sent"
-	NotificationsEmailSent = "notifications.email.sent"
-	NotificationsSmsSent   = "notifications.sms.sent"
-	NotificationsTgSent    = "notifications.tg.sent"
+	NotificationsPushSent     = "notifications.push.sent"
+	NotificationsPushDeferred = "notifications.push.deferred"
+	NotificationsEmailSent    = "notifications.email.sent"
+	NotificationsSmsSent      = "notifications.sms.sent"
+	NotificationsTgSent       = "notifications.tg.sent"
+	NotificationsMessageSend  = "notifications.message.send"
 )
 
 const (
-	NotificationsJobEventRun    = "notifications.job.event.run"
-	NotificationsJobPushCleaned = "notifications.job.push.cleaned"
+	NotificationsJobEventRun        = "notifications.job.event.run"
+	NotificationsJobPushCleaned     = "notifications.job.push.cleaned"
+	NotificationsJobOutboxRelayed   = "notifications.job.outbox.relayed"
+	NotificationsJobTokensValidated = "notifications.job.tokens.validated"
 )
 
 const (
@@ -27,5 +31,15 @@ const (
 	NotificationsTopicUsersUnsubscribed = "notifications.topic.users.unsubscribed"
 )
 
-const NotificationsSyncPushSent = "notifications.sync.push.sent"
+const (
+	NotificationsDlq         = "notifications.dlq.>"
+	NotificationsDlqPushSent = "notifications.dlq.push.sent"
+	NotificationsDlqSmsSent  = "notifications.dlq.sms.sent"
+	NotificationsDlqMessage  = "notifications.dlq.message.send"
+)
+
+const (
+	NotificationsSyncPushSent = "notifications.sync.push.sent"
+	NotificationsSyncInbox    = "notifications.sync.inbox"
+)
 const AuditAdd = "audit.add"
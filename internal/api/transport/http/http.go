@@ -15,8 +15,16 @@ import (
 	"notifications/internal/api/resp"
 	"notifications/internal/api/resp/code"
 	"notifications/internal/api/transport/http/middleware"
+	"notifications/internal/handler/http/analytics"
+	"notifications/internal/handler/http/apiclient"
+	"notifications/internal/handler/http/dlq"
 	"notifications/internal/handler/http/event"
+	"notifications/internal/handler/http/inbox"
+	"notifications/internal/handler/http/message"
+	"notifications/internal/handler/http/preference"
 	"notifications/internal/handler/http/push"
+	"notifications/internal/handler/http/template"
+	"notifications/internal/service/admin"
 	"notifications/pkg/lib/config"
 	"notifications/pkg/lib/observer/logger"
 )
@@ -31,8 +39,15 @@ type Params struct {
 	Logger     logger.Logger
 	Middleware middleware.Protector
 
-	Push  push.Handler
-	Event event.Handler
+	Push       push.Handler
+	Event      event.Handler
+	APIClient  apiclient.Handler
+	Inbox      inbox.Handler
+	Dlq        dlq.Handler
+	Template   template.Handler
+	Preference preference.Handler
+	Analytics  analytics.Handler
+	Message    message.Handler
 }
 
 // NewHTTPRouter
@@ -66,17 +81,59 @@ func NewHTTPRouter(p Params) {
 	internalBase.GET("/health", func(c *gin.Context) { resp.JSON(c.Writer, code.Success, resp.Success) })
 
 	internalEvents := internalBase.Group("/events").Use(p.Middleware.ProtectInternal())
-	internalEvents.GET("/", p.Event.Get)
-	internalEvents.POST("/", p.Event.Create)
-	internalEvents.PUT("/:id", p.Event.Update)
-	internalEvents.DELETE("/:id", p.Event.Delete)
-	internalEvents.POST("/:id/load-users", p.Event.LoadUsers)
-	internalEvents.POST("/:id/load-all-users", p.Event.LoadAllUsers)
-	internalEvents.POST("/:id/run", p.Event.Run)
-	internalEvents.POST("/:id/image/:language", p.Event.UploadImage)
-	internalEvents.DELETE("/:id/image/:language", p.Event.RemoveImage)
-
-	externalBase.Group("/push").Use(p.Middleware.ProtectExternal()).POST("/", p.Push.Send)
+	internalEvents.GET("/", p.Middleware.Permit(admin.ReadEventPermission), p.Event.Get)
+	internalEvents.POST("/", p.Middleware.Permit(admin.CreateEventPermission), p.Event.Create)
+	internalEvents.POST("/audience/estimate", p.Middleware.Permit(admin.ReadEventPermission), p.Event.EstimateAudience)
+	internalEvents.PUT("/:id", p.Middleware.Permit(admin.UpdateEventPermission), p.Event.Update)
+	internalEvents.DELETE("/:id", p.Middleware.Permit(admin.DeleteEventPermission), p.Event.Delete)
+	internalEvents.POST("/:id/load-users", p.Middleware.Permit(admin.LoadUsersPermission), p.Event.LoadUsers)
+	internalEvents.POST("/:id/load-all-users", p.Middleware.Permit(admin.LoadUsersPermission), p.Event.LoadAllUsers)
+	internalEvents.GET("/:id/rejected-users", p.Middleware.Permit(admin.LoadUsersPermission), p.Event.GetRejectedReport)
+	internalEvents.GET("/:id/stats", p.Middleware.Permit(admin.ReadEventPermission), p.Analytics.EventStats)
+	internalEvents.GET("/:id/runs", p.Middleware.Permit(admin.ReadEventPermission), p.Event.GetRuns)
+	internalEvents.GET("/:id/runs/:runID/deliveries", p.Middleware.Permit(admin.ReadEventPermission), p.Event.GetDeliveries)
+	internalEvents.GET("/:id/runs/:runID/progress", p.Middleware.Permit(admin.ReadEventPermission), p.Event.GetProgress)
+	internalEvents.POST("/:id/submit", p.Middleware.Permit(admin.UpdateEventPermission), p.Event.Submit)
+	internalEvents.POST("/:id/approve", p.Middleware.Permit(admin.ApproveEventPermission), p.Event.Approve)
+	internalEvents.POST("/:id/reject", p.Middleware.Permit(admin.ApproveEventPermission), p.Event.Reject)
+	internalEvents.POST("/:id/cancel", p.Middleware.Permit(admin.UpdateEventPermission), p.Event.Cancel)
+	internalEvents.POST("/:id/run", p.Middleware.Permit(admin.RunEventPermission), p.Event.Run)
+	internalEvents.POST("/:id/winner", p.Middleware.Permit(admin.RunEventPermission), p.Event.SendWinner)
+	internalEvents.POST("/:id/image/:language", p.Middleware.Permit(admin.UploadImagePermission), p.Event.UploadImage)
+	internalEvents.DELETE("/:id/image/:language", p.Middleware.Permit(admin.UploadImagePermission), p.Event.RemoveImage)
+
+	internalTemplates := internalBase.Group("/templates").Use(p.Middleware.ProtectInternal())
+	internalTemplates.GET("/", p.Middleware.Permit(admin.ReadTemplatePermission), p.Template.Get)
+	internalTemplates.GET("/:key", p.Middleware.Permit(admin.ReadTemplatePermission), p.Template.GetByKey)
+	internalTemplates.POST("/", p.Middleware.Permit(admin.WriteTemplatePermission), p.Template.Create)
+	internalTemplates.PUT("/:key", p.Middleware.Permit(admin.WriteTemplatePermission), p.Template.Update)
+	internalTemplates.DELETE("/:key", p.Middleware.Permit(admin.WriteTemplatePermission), p.Template.Delete)
+	internalTemplates.POST("/:key/preview", p.Middleware.Permit(admin.ReadTemplatePermission), p.Template.Preview)
+
+	internalDlq := internalBase.Group("/dlq").Use(p.Middleware.ProtectInternal())
+	internalDlq.GET("/", p.Middleware.Permit(admin.ReadDlqPermission), p.Dlq.Get)
+	internalDlq.GET("/:seq", p.Middleware.Permit(admin.ReadDlqPermission), p.Dlq.GetByID)
+	internalDlq.POST("/:seq/replay", p.Middleware.Permit(admin.ReplayDlqPermission), p.Dlq.Replay)
+
+	internalBase.Group("/messages").Use(p.Middleware.ProtectInternal()).
+		GET("/:id", p.Middleware.Permit(admin.ReadMessagePermission), p.Message.GetByRequestID)
+
+	externalPush := externalBase.Group("/push").Use(p.Middleware.ProtectExternal())
+	externalPush.POST("/", p.Middleware.Limit(), p.Push.Send)
+	externalPush.GET("/:id", p.Push.GetDelivery)
+
+	externalBase.Group("/usage").Use(p.Middleware.ProtectExternal()).GET("/", p.APIClient.Usage)
+
+	externalInbox := externalBase.Group("/inbox").Use(p.Middleware.ProtectExternal(), p.Middleware.PermitExternal(middleware.InboxAction))
+	externalInbox.GET("/:userID", p.Inbox.Get)
+	externalInbox.GET("/:userID/unread-count", p.Inbox.CountUnread)
+	externalInbox.PUT("/:userID/read-all", p.Inbox.MarkAllRead)
+	externalInbox.PUT("/:userID/items/:source/:id/read", p.Inbox.MarkRead)
+	externalInbox.DELETE("/:userID/items/:source/:id", p.Inbox.Delete)
+
+	externalBase.Group("/preferences").Use(p.Middleware.ProtectExternal(), p.Middleware.PermitExternal(middleware.PreferencesAction)).GET("/:userID", p.Preference.Get)
+
+	externalBase.Group("/analytics").Use(p.Middleware.ProtectExternal(), p.Middleware.PermitExternal(middleware.AnalyticsAction)).POST("/", p.Analytics.Track)
 
 	var server = http.Server{
 		Addr:    p.Config.GetString("notifications.server.port"),
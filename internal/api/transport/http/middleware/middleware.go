@@ -3,7 +3,9 @@ package middleware
 import (
 	"context"
 	"errors"
+	"math"
 	"slices"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,7 +14,9 @@ import (
 	"notifications/internal/api/resp"
 	"notifications/internal/api/resp/code"
 	"notifications/internal/repo/repomodel"
+	"notifications/internal/service/admin"
 	"notifications/pkg/lib/security/hasher"
+	"notifications/pkg/lib/security/ratelimiter"
 	"notifications/pkg/util/strset"
 )
 
@@ -25,6 +29,26 @@ const (
 	_authorization = "Authorization"
 )
 
+const (
+	_retryAfterKey       = "Retry-After"
+	_rateLimitKey        = "X-RateLimit-Limit"
+	_rateRemainingKey    = "X-RateLimit-Remaining"
+	_dailyRemainingKey   = "X-Quota-Daily-Remaining"
+	_monthlyRemainingKey = "X-Quota-Monthly-Remaining"
+)
+
+const (
+	_rateLimitPolicy = "rateLimitPolicy"
+	_permissionsKey  = "apiClientPermissions"
+)
+
+// Actions the api clients are permitted to perform by the routes, the X-UserAction of the client isn't trusted by them
+const (
+	InboxAction       = "inbox"
+	PreferencesAction = "preferences"
+	AnalyticsAction   = "analytics"
+)
+
 func (m *mw) ProtectExternal() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
@@ -67,21 +91,6 @@ func (m *mw) ProtectExternal() gin.HandlerFunc {
 			return
 		}
 
-		// Each client can make 3 requests per second. After a second, the counter will reset
-		//limiter := m.rateLimiter.NewSlidingWindowLimiter(userID, 3, time.Second)
-		//if !limiter.IsAllowed() {
-		//	m.logger.Warning("rate limit exceeded",
-		//		zap.String("userID", userID),
-		//		zap.String("userAction", userAction),
-		//		zap.String("clientIP", clientIP),
-		//		zap.String("requestID", requestID))
-		//
-		//	response = resp.TooManyRequests
-		//	response.Message = "Rate limit exceeded, try again later"
-		//	resp.GinJSONAbort(c, code.TooManyRequests, response)
-		//	return
-		//}
-
 		if !slices.Contains(client.Permissions, userAction) {
 			m.logger.Warning("user permission denied",
 				zap.String("userID", userID),
@@ -138,6 +147,101 @@ func (m *mw) ProtectExternal() gin.HandlerFunc {
 
 		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "requestID", requestID))
 		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "apiClient", userID))
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), _rateLimitPolicy, ratelimiter.Policy{
+			Window:       time.Second,
+			Rate:         client.RateLimit,
+			DailyQuota:   client.DailyQuota,
+			MonthlyQuota: client.MonthlyQuota,
+		}))
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), _permissionsKey, client.Permissions))
+		c.Next()
+	}
+}
+
+// PermitExternal must be used after ProtectExternal, it checks the api client is permitted to perform the action
+// of the route regardless of the X-UserAction it provided
+func (m *mw) PermitExternal(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			ctx          = c.Request.Context()
+			userID, _    = ctx.Value("apiClient").(string)
+			requestID, _ = ctx.Value("requestID").(string)
+			response     resp.Response
+		)
+
+		permissions, ok := ctx.Value(_permissionsKey).([]string)
+		if !ok {
+			resp.GinJSONAbort(c, code.Unauthorized, resp.Unauthorized)
+			return
+		}
+
+		if !slices.Contains(permissions, action) {
+			m.logger.Warning("user permission denied",
+				zap.String("userID", userID),
+				zap.String("action", action),
+				zap.String("requestID", requestID))
+
+			response = resp.Forbidden
+			response.Message = "User doesn't have permission to perform this action"
+			resp.GinJSONAbort(c, code.Forbidden, response)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Limit must be used after ProtectExternal, it consumes the rate limit and quotas of the api client
+func (m *mw) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			ctx          = c.Request.Context()
+			userID, _    = ctx.Value("apiClient").(string)
+			requestID, _ = ctx.Value("requestID").(string)
+			response     resp.Response
+		)
+
+		policy, ok := ctx.Value(_rateLimitPolicy).(ratelimiter.Policy)
+		if !ok {
+			resp.GinJSONAbort(c, code.Unauthorized, resp.Unauthorized)
+			return
+		}
+
+		result, err := m.rateLimiter.Allow(ctx, userID, policy)
+		if err != nil {
+			m.logger.Error("err occurred during checking rate limit", zap.Error(err), zap.String("userID", userID))
+			resp.GinJSONAbort(c, code.InternalErr, resp.InternalErr)
+			return
+		}
+
+		if policy.Rate > 0 {
+			c.Header(_rateLimitKey, strconv.FormatInt(policy.Rate, 10))
+			c.Header(_rateRemainingKey, strconv.FormatInt(result.RateRemaining, 10))
+		}
+		if policy.DailyQuota > 0 {
+			c.Header(_dailyRemainingKey, strconv.FormatInt(result.DailyRemaining, 10))
+		}
+		if policy.MonthlyQuota > 0 {
+			c.Header(_monthlyRemainingKey, strconv.FormatInt(result.MonthlyRemaining, 10))
+		}
+
+		if !result.Allowed {
+			m.logger.Warning("rate limit exceeded",
+				zap.String("userID", userID),
+				zap.String("requestID", requestID),
+				zap.String("exceeded", result.Exceeded))
+
+			c.Header(_retryAfterKey, strconv.FormatInt(int64(math.Ceil(result.RetryAfter.Seconds())), 10))
+
+			response = resp.TooManyRequests
+			response.Message = "Rate limit exceeded, try again later"
+			if result.Exceeded != ratelimiter.ExceededRate {
+				response.Message = "The " + result.Exceeded + " quota is exhausted, try again after it resets"
+			}
+			resp.GinJSONAbort(c, code.TooManyRequests, response)
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -179,3 +283,21 @@ func (m *mw) ProtectInternal() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+func (m *mw) Permit(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminUser, ok := c.Request.Context().Value(admin.CtxKey).(admin.Admin)
+		if !ok {
+			resp.GinJSONAbort(c, code.Unauthorized, resp.Unauthorized)
+			return
+		}
+
+		if err := m.admin.CheckPermission(adminUser, permission); err != nil {
+			response := resp.RespondErr(err)
+			resp.GinJSONAbort(c, response.Code, response)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -16,6 +16,9 @@ var Module = fx.Provide(New)
 type Protector interface {
 	ProtectExternal() gin.HandlerFunc
 	ProtectInternal() gin.HandlerFunc
+	Limit() gin.HandlerFunc
+	PermitExternal(action string) gin.HandlerFunc
+	Permit(permission string) gin.HandlerFunc
 }
 
 type Params struct {
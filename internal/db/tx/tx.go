@@ -8,6 +8,9 @@ import (
 
 	"notifications/internal/db"
 	"notifications/internal/repo/event"
+	"notifications/internal/repo/outbox"
+	"notifications/internal/repo/push"
+	"notifications/internal/repo/template"
 	"notifications/internal/repo/user"
 )
 
@@ -26,36 +29,51 @@ type Transactor interface {
 type repo interface {
 	UserRepo() user.Repo
 	EventRepo() event.Repo
+	PushRepo() push.Repo
+	OutboxRepo() outbox.Repo
+	TemplateRepo() template.Repo
 }
 
 type Params struct {
 	fx.In
 
-	DB        db.QueryExecutor
-	UserRepo  user.Repo
-	EventRepo event.Repo
+	DB           db.QueryExecutor
+	UserRepo     user.Repo
+	EventRepo    event.Repo
+	PushRepo     push.Repo
+	OutboxRepo   outbox.Repo
+	TemplateRepo template.Repo
 }
 
 type transactor struct {
-	db        db.QueryExecutor
-	tx        pgx.Tx
-	userRepo  user.Repo
-	eventRepo event.Repo
+	db           db.QueryExecutor
+	tx           pgx.Tx
+	userRepo     user.Repo
+	eventRepo    event.Repo
+	pushRepo     push.Repo
+	outboxRepo   outbox.Repo
+	templateRepo template.Repo
 }
 
 func New(p Params) Transactor {
 	return &transactor{
-		db:        p.DB,
-		userRepo:  p.UserRepo,
-		eventRepo: p.EventRepo,
+		db:           p.DB,
+		userRepo:     p.UserRepo,
+		eventRepo:    p.EventRepo,
+		pushRepo:     p.PushRepo,
+		outboxRepo:   p.OutboxRepo,
+		templateRepo: p.TemplateRepo,
 	}
 }
 
 func (t *transactor) New() Transactor {
 	return &transactor{
-		db:        t.db,
-		userRepo:  t.userRepo,
-		eventRepo: t.eventRepo,
+		db:           t.db,
+		userRepo:     t.userRepo,
+		eventRepo:    t.eventRepo,
+		pushRepo:     t.pushRepo,
+		outboxRepo:   t.outboxRepo,
+		templateRepo: t.templateRepo,
 	}
 }
 
@@ -85,3 +103,18 @@ func (t *transactor) EventRepo() event.Repo {
 	t.eventRepo = event.New(event.Params{DB: t.tx})
 	return t.eventRepo
 }
+
+func (t *transactor) PushRepo() push.Repo {
+	t.pushRepo = push.New(push.Params{DB: t.tx})
+	return t.pushRepo
+}
+
+func (t *transactor) OutboxRepo() outbox.Repo {
+	t.outboxRepo = outbox.New(outbox.Params{DB: t.tx})
+	return t.outboxRepo
+}
+
+func (t *transactor) TemplateRepo() template.Repo {
+	t.templateRepo = template.New(template.Params{DB: t.tx})
+	return t.templateRepo
+}
@@ -1,24 +1,25 @@
 package admin
 
-//type gatewayResponse struct {
-//	Code    int   `json:"code"`
-//	Payload Admin `json:"payload"`
-//}
+type gatewayResponse struct {
+	Code    int   `json:"code"`
+	Payload Admin `json:"payload"`
+}
 
 type Admin struct {
-	ID        int    `json:"id"`
-	CountryID int    `json:"countryID"`
-	Username  string `json:"username"`
-	FullName  string `json:"fullname"`
+	ID          int      `json:"id"`
+	CountryID   int      `json:"countryID"`
+	Username    string   `json:"username"`
+	FullName    string   `json:"fullname"`
+	Permissions []string `json:"permissions"`
 }
 
 // auth headers
-//const (
-//	_authorization = "Authorization"
-//	_serviceName   = "Service-Name"
-//	_reqUrl        = "ReqUrl"
-//	_method        = "Method"
-//)
+const (
+	_authorization = "Authorization"
+	_serviceName   = "Service-Name"
+	_reqUrl        = "ReqUrl"
+	_method        = "Method"
+)
 
 // service name
-//const _notifications = "notifications"
+const _notifications = "notifications"
@@ -2,7 +2,9 @@ package admin
 
 import (
 	"context"
+	"time"
 
+	"github.com/imroc/req/v3"
 	"go.uber.org/fx"
 
 	"notifications/pkg/lib/config"
@@ -25,11 +27,15 @@ type Params struct {
 type gateway struct {
 	config config.Config
 	logger logger.Logger
+	client *req.Client
 }
 
+const _timeout = 10 * time.Second
+
 func New(p Params) Gateway {
 	return &gateway{
 		config: p.Config,
 		logger: p.Logger,
+		client: req.C().SetTimeout(_timeout),
 	}
 }
@@ -16,7 +16,11 @@ func (h *handler) Sent(msg jetstream.Msg) {
 	var (
 		ctx  = context.Background()
 		data = struct {
-			Body map[string]string `json:"body"`
+			Body        map[string]string `json:"body"`
+			TemplateKey string            `json:"templateKey"`
+			Language    string            `json:"language"`
+			UserID      int               `json:"userID"`
+			Variables   map[string]any    `json:"variables"`
 		}{}
 	)
 
@@ -27,7 +31,11 @@ func (h *handler) Sent(msg jetstream.Msg) {
 	}
 
 	err = h.service.Send(ctx, email.Email{
-		Body: data.Body,
+		Body:        data.Body,
+		TemplateKey: data.TemplateKey,
+		Language:    data.Language,
+		UserID:      data.UserID,
+		Variables:   data.Variables,
 	})
 	if err != nil {
 		h.logger.Error("Send error", zap.Error(err))
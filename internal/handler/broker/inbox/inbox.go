@@ -0,0 +1,64 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bytedance/sonic"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+func (h *handler) Sync(msg *nats.Msg) {
+	h.logger.Info("msg Sync inbox", zap.ByteString("data", msg.Data))
+
+	var (
+		ctx     = context.Background()
+		err     error
+		payload any
+		message request
+	)
+
+	defer func() {
+		var reply = response{Payload: payload}
+		if err != nil {
+			reply.Error = err.Error()
+		}
+
+		respBytes, err := sonic.Marshal(reply)
+		if err != nil {
+			h.logger.Error("sonic.Marshal error", zap.Error(err), zap.Any("response", reply))
+			return
+		}
+
+		_ = msg.Respond(respBytes)
+	}()
+
+	err = sonic.Unmarshal(msg.Data, &message)
+	if err != nil {
+		h.logger.Error("sonic.Unmarshal error", zap.Error(err), zap.ByteString("data", msg.Data))
+		return
+	}
+
+	switch message.Action {
+	case _get:
+		payload, err = h.service.GetInbox(ctx, message.UserID, message.Cursor, message.Limit)
+	case _countUnread:
+		var count int
+		count, err = h.service.CountUnread(ctx, message.UserID)
+		payload = unread{Count: count}
+	case _markRead:
+		err = h.service.MarkRead(ctx, message.UserID, message.Source, message.ID)
+	case _markAllRead:
+		err = h.service.MarkAllRead(ctx, message.UserID)
+	case _delete:
+		err = h.service.Delete(ctx, message.UserID, message.Source, message.ID)
+	default:
+		err = errors.New("unknown action: " + message.Action)
+	}
+
+	if err != nil {
+		h.logger.Error("inbox Sync error", zap.Error(err), zap.String("action", message.Action), zap.Int("userID", message.UserID))
+		return
+	}
+}
@@ -0,0 +1,28 @@
+package inbox
+
+// Request actions
+const (
+	_get         = "get"
+	_countUnread = "count_unread"
+	_markRead    = "mark_read"
+	_markAllRead = "mark_all_read"
+	_delete      = "delete"
+)
+
+type request struct {
+	Action string `json:"action"`
+	Cursor string `json:"cursor"`
+	Source string `json:"source"`
+	UserID int    `json:"userID"`
+	ID     int    `json:"id"`
+	Limit  int    `json:"limit"`
+}
+
+type response struct {
+	Payload any    `json:"payload"`
+	Error   string `json:"error"`
+}
+
+type unread struct {
+	Count int `json:"unread"`
+}
@@ -0,0 +1,34 @@
+package inbox
+
+import (
+	"github.com/nats-io/nats.go"
+	"go.uber.org/fx"
+
+	"notifications/internal/service/inbox"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Handler interface {
+	Sync(*nats.Msg)
+}
+
+type Params struct {
+	fx.In
+
+	Logger  logger.Logger
+	Service inbox.Service
+}
+
+type handler struct {
+	logger  logger.Logger
+	service inbox.Service
+}
+
+func New(p Params) Handler {
+	return &handler{
+		logger:  p.Logger,
+		service: p.Service,
+	}
+}
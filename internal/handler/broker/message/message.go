@@ -0,0 +1,70 @@
+package message
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bytedance/sonic"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/service/message"
+	"notifications/pkg/lib/broker/nats"
+)
+
+// Send sends the message through the channels of the policy, e.g. push and sms if the push fails within its timeout
+func (h *handler) Send(msg jetstream.Msg) error {
+	h.logger.Info("msg Send", zap.ByteString("data", msg.Data()))
+
+	var (
+		ctx = context.Background()
+		r   request
+	)
+
+	err := sonic.Unmarshal(msg.Data(), &r)
+	if err != nil {
+		h.logger.Error("sonic.Unmarshal error", zap.Error(err))
+		return nats.Permanent(err)
+	}
+
+	result, err := h.service.Send(ctx, r.toService())
+	if err != nil {
+		h.logger.Error("Send error", zap.Error(err), zap.String("id", r.ID))
+		if errors.Is(err, resp.ErrBadRequest) {
+			return nats.Permanent(err)
+		}
+		return err
+	}
+
+	h.logger.Info("message is processed", zap.String("id", r.ID), zap.String("status", result.Status), zap.String("channel", result.Channel))
+
+	return nil
+}
+
+func (r *request) toService() *message.Request {
+	var request = &message.Request{
+		ID: r.ID,
+		Recipient: message.Recipient{
+			UserID: r.UserID,
+			Phone:  r.Phone,
+			Email:  r.Email,
+			ChatID: r.ChatID,
+			Bot:    r.Bot,
+		},
+		Policy:      make([]message.Step, 0, len(r.Policy)),
+		Category:    r.Category,
+		TemplateKey: r.TemplateKey,
+		Variables:   r.Variables,
+		Language:    r.Language,
+		Title:       r.Title,
+		Text:        r.Text,
+		Data:        r.Data,
+	}
+
+	for _, s := range r.Policy {
+		request.Policy = append(request.Policy, message.Step{Channel: s.Channel, Timeout: s.Timeout})
+	}
+
+	return request
+}
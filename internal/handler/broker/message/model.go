@@ -0,0 +1,23 @@
+package message
+
+type request struct {
+	ID          string            `json:"id"`
+	UserID      int               `json:"userID"`
+	Phone       string            `json:"phone"`
+	Email       string            `json:"email"`
+	ChatID      int64             `json:"chatID"`
+	Bot         string            `json:"bot"`
+	Policy      []step            `json:"policy"`
+	Category    string            `json:"category"`
+	TemplateKey string            `json:"templateKey"`
+	Variables   map[string]any    `json:"variables"`
+	Language    string            `json:"language"`
+	Title       string            `json:"title"`
+	Text        string            `json:"text"`
+	Data        map[string]string `json:"data"`
+}
+
+type step struct {
+	Channel string `json:"channel"`
+	Timeout int    `json:"timeout"`
+}
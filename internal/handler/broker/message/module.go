@@ -0,0 +1,34 @@
+package message
+
+import (
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/fx"
+
+	"notifications/internal/service/message"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Handler interface {
+	Send(jetstream.Msg) error
+}
+
+type Params struct {
+	fx.In
+
+	Logger  logger.Logger
+	Service message.Service
+}
+
+type handler struct {
+	logger  logger.Logger
+	service message.Service
+}
+
+func New(p Params) Handler {
+	return &handler{
+		logger:  p.Logger,
+		service: p.Service,
+	}
+}
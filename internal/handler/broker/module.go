@@ -5,6 +5,9 @@ import (
 
 	"notifications/internal/handler/broker/email"
 	"notifications/internal/handler/broker/event"
+	"notifications/internal/handler/broker/inbox"
+	"notifications/internal/handler/broker/message"
+	"notifications/internal/handler/broker/outbox"
 	"notifications/internal/handler/broker/push"
 	"notifications/internal/handler/broker/sms"
 	"notifications/internal/handler/broker/telegram"
@@ -18,4 +21,7 @@ var Module = fx.Options(
 	email.Module,
 	telegram.Module,
 	sms.Module,
+	inbox.Module,
+	outbox.Module,
+	message.Module,
 )
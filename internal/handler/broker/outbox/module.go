@@ -0,0 +1,34 @@
+package outbox
+
+import (
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/fx"
+
+	"notifications/internal/service/outbox"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Handler interface {
+	Relay(jetstream.Msg)
+}
+
+type Params struct {
+	fx.In
+
+	Logger  logger.Logger
+	Service outbox.Service
+}
+
+type handler struct {
+	logger  logger.Logger
+	service outbox.Service
+}
+
+func New(p Params) Handler {
+	return &handler{
+		logger:  p.Logger,
+		service: p.Service,
+	}
+}
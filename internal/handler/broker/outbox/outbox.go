@@ -0,0 +1,16 @@
+package outbox
+
+import (
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+func (h *handler) Relay(msg jetstream.Msg) {
+	err := msg.Ack()
+	if err != nil {
+		h.logger.Error("msg ack error", zap.Error(err))
+		return
+	}
+
+	h.service.Relay()
+}
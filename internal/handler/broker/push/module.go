@@ -12,8 +12,9 @@ import (
 var Module = fx.Provide(New)
 
 type Handler interface {
-	Sent(jetstream.Msg)
+	Sent(jetstream.Msg) error
 	Clean(jetstream.Msg)
+	Deferred(jetstream.Msg) error
 	SyncSent(*nats.Msg)
 }
 
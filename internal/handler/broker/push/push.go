@@ -2,52 +2,96 @@ package push
 
 import (
 	"context"
+	"errors"
+	"strconv"
 
 	"github.com/bytedance/sonic"
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"go.uber.org/zap"
 
+	"notifications/internal/api/resp"
 	"notifications/internal/service/push"
+	brokerlib "notifications/pkg/lib/broker/nats"
 )
 
-func (h *handler) Sent(msg jetstream.Msg) {
+func (h *handler) Sent(msg jetstream.Msg) error {
 	h.logger.Info("msg Sent", zap.ByteString("data", msg.Data()))
 
 	var (
 		ctx     = context.Background()
 		message struct {
-			UserID     int               `json:"userID"`
-			Token      string            `json:"token"`
-			Data       map[string]string `json:"data"`
-			ShowInFeed bool              `json:"showInFeed"`
+			UserID      int               `json:"userID"`
+			Token       string            `json:"token"`
+			Data        map[string]string `json:"data"`
+			ShowInFeed  bool              `json:"showInFeed"`
+			TemplateKey string            `json:"templateKey"`
+			Variables   map[string]any    `json:"variables"`
 		}
 	)
 
 	err := sonic.Unmarshal(msg.Data(), &message)
 	if err != nil {
 		h.logger.Error("sonic.Unmarshal error", zap.Error(err), zap.ByteString("data", msg.Data()))
-		return
-	}
-
-	err = msg.Ack()
-	if err != nil {
-		h.logger.Error("msg ack error", zap.Error(err), zap.Int("userID", message.UserID))
-		return
+		return brokerlib.Permanent(err)
 	}
 
 	var request = new(push.Request)
 	request.InternalRequest.UserID = message.UserID
 	request.InternalRequest.Token = message.Token
 	request.InternalRequest.Data = message.Data
+	request.InternalRequest.TemplateKey = message.TemplateKey
+	request.InternalRequest.Variables = message.Variables
 	request.ShowInFeed = message.ShowInFeed
 	request.IsInternal = true
 
+	// stream sequence doesn't change on redelivery, so it identifies the message across the retries
+	if meta, err := msg.Metadata(); err == nil {
+		request.InternalRequest.MsgID = meta.Stream + ":" + strconv.FormatUint(meta.Sequence.Stream, 10)
+	}
+
 	_, err = h.service.Send(ctx, request)
 	if err != nil {
 		h.logger.Error("SendInternal error", zap.Error(err), zap.Int("userID", message.UserID))
-		return
+		// invalid requests, e.g. unknown template or missing variables, won't succeed on retry
+		if errors.Is(err, resp.ErrBadRequest) {
+			return brokerlib.Permanent(err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Deferred sends the push held back by quiet hours, the request is published as it was received
+func (h *handler) Deferred(msg jetstream.Msg) error {
+	h.logger.Info("msg Deferred", zap.ByteString("data", msg.Data()))
+
+	var (
+		ctx     = context.Background()
+		request = new(push.Request)
+	)
+
+	err := sonic.Unmarshal(msg.Data(), request)
+	if err != nil {
+		h.logger.Error("sonic.Unmarshal error", zap.Error(err), zap.ByteString("data", msg.Data()))
+		return brokerlib.Permanent(err)
+	}
+
+	if meta, err := msg.Metadata(); err == nil {
+		request.InternalRequest.MsgID = meta.Stream + ":" + strconv.FormatUint(meta.Sequence.Stream, 10)
 	}
+
+	_, err = h.service.Send(ctx, request)
+	if err != nil {
+		h.logger.Error("Send deferred error", zap.Error(err), zap.Int("deliveryID", request.DeliveryID))
+		if errors.Is(err, resp.ErrBadRequest) || errors.Is(err, resp.ErrNotFound) {
+			return brokerlib.Permanent(err)
+		}
+		return err
+	}
+
+	return nil
 }
 
 func (h *handler) SyncSent(msg *nats.Msg) {
@@ -58,9 +102,11 @@ func (h *handler) SyncSent(msg *nats.Msg) {
 		err       error
 		messageID string
 		message   struct {
-			UserID int               `json:"userID"`
-			Token  string            `json:"token"`
-			Data   map[string]string `json:"data"`
+			UserID      int               `json:"userID"`
+			Token       string            `json:"token"`
+			Data        map[string]string `json:"data"`
+			TemplateKey string            `json:"templateKey"`
+			Variables   map[string]any    `json:"variables"`
 		}
 		response struct {
 			MessageID string `json:"messageID"`
@@ -93,6 +139,8 @@ func (h *handler) SyncSent(msg *nats.Msg) {
 	request.InternalRequest.UserID = message.UserID
 	request.InternalRequest.Token = message.Token
 	request.InternalRequest.Data = message.Data
+	request.InternalRequest.TemplateKey = message.TemplateKey
+	request.InternalRequest.Variables = message.Variables
 	request.IsInternal = true
 	request.Sync = true
 
@@ -11,7 +11,7 @@ import (
 var Module = fx.Provide(New)
 
 type Handler interface {
-	Sent(jetstream.Msg)
+	Sent(jetstream.Msg) error
 }
 
 type Params struct {
@@ -2,35 +2,54 @@ package sms
 
 import (
 	"context"
+	"errors"
 
 	"github.com/bytedance/sonic"
 	"github.com/nats-io/nats.go/jetstream"
 	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/service/sms"
+	"notifications/pkg/lib/broker/nats"
 )
 
-func (h *handler) Sent(msg jetstream.Msg) {
+func (h *handler) Sent(msg jetstream.Msg) error {
 	h.logger.Info("msg Sent", zap.ByteString("data", msg.Data()))
 
 	var (
-		ctx  = context.Background()
-		body = make(map[string]string)
+		ctx     = context.Background()
+		message struct {
+			Phone       string         `json:"phone"`
+			Text        string         `json:"text"`
+			TemplateKey string         `json:"templateKey"`
+			Language    string         `json:"language"`
+			UserID      int            `json:"userID"`
+			Variables   map[string]any `json:"variables"`
+		}
 	)
 
-	err := sonic.Unmarshal(msg.Data(), &body)
+	err := sonic.Unmarshal(msg.Data(), &message)
 	if err != nil {
 		h.logger.Error("sonic.Unmarshal error", zap.Error(err))
-		return
+		return nats.Permanent(err)
 	}
 
-	err = msg.Ack()
-	if err != nil {
-		h.logger.Error("msg ack error", zap.Error(err))
-		return
-	}
-
-	err = h.service.Send(ctx, body)
+	err = h.service.Send(ctx, sms.Message{
+		Phone:       message.Phone,
+		Text:        message.Text,
+		TemplateKey: message.TemplateKey,
+		Language:    message.Language,
+		UserID:      message.UserID,
+		Variables:   message.Variables,
+	})
 	if err != nil {
 		h.logger.Error("Send error", zap.Error(err))
-		return
+		// invalid requests, e.g. unknown template or missing variables, won't succeed on retry
+		if errors.Is(err, resp.ErrBadRequest) {
+			return nats.Permanent(err)
+		}
+		return err
 	}
+
+	return nil
 }
@@ -13,10 +13,12 @@ var Module = fx.Provide(New)
 type Handler interface {
 	UserCreated(jetstream.Msg)
 	TokenUpdated(jetstream.Msg)
+	TokenRemoved(jetstream.Msg)
 	StatusUpdated(jetstream.Msg)
 	SettingsUpdated(jetstream.Msg)
 	PhoneUpdated(jetstream.Msg)
 	PersonExternalRefUpdated(jetstream.Msg)
+	ValidateTokens(jetstream.Msg)
 }
 
 type Params struct {
@@ -149,9 +149,19 @@ func (h *handler) SettingsUpdated(msg jetstream.Msg) {
 	var (
 		ctx  = context.Background()
 		data = struct {
-			UserID    int    `json:"userID"`
-			Language  string `json:"language"`
-			IsEnabled *bool  `json:"isEnabled"`
+			UserID      int    `json:"userID"`
+			Language    string `json:"language"`
+			IsEnabled   *bool  `json:"isEnabled"`
+			Preferences []struct {
+				Category string `json:"category"`
+				Channel  string `json:"channel"`
+				Enabled  bool   `json:"enabled"`
+			} `json:"preferences"`
+			QuietHours *struct {
+				Start   string `json:"start"`
+				End     string `json:"end"`
+				Enabled bool   `json:"enabled"`
+			} `json:"quietHours"`
 		}{}
 	)
 
@@ -167,6 +177,29 @@ func (h *handler) SettingsUpdated(msg jetstream.Msg) {
 		return
 	}
 
+	var preferences = make([]user.Preference, 0, len(data.Preferences))
+	for _, p := range data.Preferences {
+		preferences = append(preferences, user.Preference{Category: p.Category, Channel: p.Channel, Enabled: p.Enabled})
+	}
+
+	err = h.service.UpdatePreferences(ctx, data.UserID, preferences)
+	if err != nil {
+		h.logger.Error("UpdatePreferences error", zap.Error(err))
+		return
+	}
+
+	if data.QuietHours != nil {
+		err = h.service.UpdateQuietHours(ctx, data.UserID, user.QuietHours{
+			Start:   data.QuietHours.Start,
+			End:     data.QuietHours.End,
+			Enabled: data.QuietHours.Enabled,
+		})
+		if err != nil {
+			h.logger.Error("UpdateQuietHours error", zap.Error(err))
+			return
+		}
+	}
+
 	err = msg.Ack()
 	if err != nil {
 		h.logger.Error("msg ack error", zap.Error(err))
@@ -180,8 +213,17 @@ func (h *handler) TokenUpdated(msg jetstream.Msg) {
 	var (
 		ctx  = context.Background()
 		data = struct {
-			UserID int    `json:"userID"`
-			Token  string `json:"token"`
+			UserID     int    `json:"userID"`
+			Token      string `json:"token"`
+			Provider   string `json:"provider"`
+			Platform   string `json:"platform"`
+			AppVersion string `json:"appVersion"`
+			Locale     string `json:"locale"`
+			// Keys are of the browser subscription of Web Push, the token is its endpoint
+			Keys struct {
+				P256dh string `json:"p256dh"`
+				Auth   string `json:"auth"`
+			} `json:"keys"`
 		}{}
 	)
 
@@ -191,9 +233,18 @@ func (h *handler) TokenUpdated(msg jetstream.Msg) {
 		return
 	}
 
-	err = h.service.UpdateToken(ctx, data.UserID, data.Token)
+	err = h.service.RegisterDevice(ctx, user.Device{
+		UserID:     data.UserID,
+		Token:      data.Token,
+		Provider:   data.Provider,
+		P256dh:     data.Keys.P256dh,
+		Auth:       data.Keys.Auth,
+		Platform:   data.Platform,
+		AppVersion: data.AppVersion,
+		Locale:     data.Locale,
+	})
 	if err != nil {
-		h.logger.Error("UpdateToken error", zap.Error(err))
+		h.logger.Error("RegisterDevice error", zap.Error(err))
 		return
 	}
 
@@ -203,3 +254,43 @@ func (h *handler) TokenUpdated(msg jetstream.Msg) {
 		return
 	}
 }
+
+// TokenRemoved deactivates the device of the token, the messages published before the devices carry the userID only
+func (h *handler) TokenRemoved(msg jetstream.Msg) {
+	h.logger.Info("TokenRemoved msg", zap.ByteString("data", msg.Data()))
+
+	var (
+		ctx  = context.Background()
+		data user.TokenRemoved
+	)
+
+	err := sonic.Unmarshal(msg.Data(), &data)
+	if err != nil {
+		if errX := sonic.Unmarshal(msg.Data(), &data.UserID); errX != nil {
+			h.logger.Error("sonic.Unmarshal error", zap.Error(err))
+			return
+		}
+	}
+
+	err = h.service.RemoveDevice(ctx, data.UserID, data.Token)
+	if err != nil {
+		h.logger.Error("RemoveDevice error", zap.Error(err))
+		return
+	}
+
+	err = msg.Ack()
+	if err != nil {
+		h.logger.Error("msg ack error", zap.Error(err))
+		return
+	}
+}
+
+func (h *handler) ValidateTokens(msg jetstream.Msg) {
+	err := msg.Ack()
+	if err != nil {
+		h.logger.Error("msg ack error", zap.Error(err))
+		return
+	}
+
+	h.service.ValidateTokens()
+}
@@ -0,0 +1,98 @@
+package analytics
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/resp/code"
+	"notifications/internal/service/analytics"
+	"notifications/pkg/util/serializer"
+	"notifications/pkg/util/strset"
+)
+
+// Track
+// @Description	Reports the action of the user with the notification of the event or the push, one of `eventID` and `pushID` is required.
+// @Description	The action is counted once per user, the previous actions of the funnel are implied: the opened notification is delivered as well.
+// @Tags			External
+// @Accept			application/json
+// @Produce		application/json
+// @Param			X-UserId		header		string			true	"Provide user ID created on the server side"
+// @Param			X-RequestId		header		string			true	"Provide unique request ID to build hash and track the request"
+// @Param			X-Date			header		string			true	"Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash"
+// @Param			X-UserAction	header		string			true	"Provide user action (analytics) to report the action"
+// @Param			X-RequestDigest	header		string			true	"Provide hash sum built with HMAC-SHA256 from the `X-Date:X-RequestId` using the secret key created on the server side"
+// @Param			data			body		trackRequest	true	"Request"
+// @Success		200				{object}	resp.Response	"Success"
+// @Failure		400				{object}	resp.Response	"Bad request"
+// @Failure		401				{object}	resp.Response	"Invalid authorization data"
+// @Failure		403				{object}	resp.Response	"Permission denied"
+// @Failure		500				{object}	resp.Response	"Internal Error"
+// @Security		SignatureAuth
+// @Router			/notifications-external/v1/analytics [post]
+func (h *handler) Track(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		response resp.Response
+		r        trackRequest
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	err := serializer.BodyToJSON(c.Request, &r)
+	if err != nil {
+		err = resp.Wrap(resp.ErrBadRequest, err.Error())
+		response = resp.RespondErr(err)
+		return
+	}
+
+	err = h.service.Track(ctx, analytics.TrackRequest{
+		EventID:  r.EventID,
+		PushID:   r.PushID,
+		UserID:   r.UserID,
+		Action:   r.Action,
+		Language: r.Language,
+		Variant:  r.Variant,
+		RunID:    r.RunID,
+	})
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+}
+
+// EventStats
+//
+//	@Summary		Get stats of event
+//	@Description	Returns the funnel of the event: targeted users are counted when the event is run, the rest are reported by the app.
+//	@Description	The rates are the shares of the previous step of the funnel, the events with A/B test are broken down by variant as well.
+//	@Tags			Events
+//	@Produce		application/json
+//	@Param			id		path		string									true	"Event ID"
+//	@Param			runID	query		string									false	"Run ID, the funnel of the run is returned"
+//	@Success		200		{object}	resp.Response{payload=eventStatsModel}	"Success"
+//	@Failure		401		{object}	resp.Response							"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response							"Permission denied"
+//	@Failure		404		{object}	resp.Response							"Not found"
+//	@Failure		500		{object}	resp.Response							"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/stats [get]
+func (h *handler) EventStats(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		id       = strset.ToInt(c.Param(_id))
+		runID    = strset.ToInt(c.Query(_runID))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	stats, err := h.service.GetEventStats(ctx, id, runID)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = stats
+}
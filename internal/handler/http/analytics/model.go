@@ -0,0 +1,37 @@
+package analytics
+
+// Route keys
+const (
+	_id    = "id"
+	_runID = "runID"
+)
+
+type trackRequest struct {
+	EventID  int    `json:"eventID" example:"1"`
+	PushID   int    `json:"pushID"`
+	UserID   int    `json:"userID" example:"1" validate:"required"`
+	Action   string `json:"action" example:"delivered, opened, clicked" validate:"required"`
+	Language string `json:"language" example:"ru"`
+	Variant  string `json:"variant" example:"a"`
+	RunID    int    `json:"runID"`
+}
+
+var _ eventStatsModel
+
+type eventStatsModel struct {
+	EventID int `json:"eventID"`
+	RunID   int `json:"runID,omitempty"`
+	funnelModel
+	Languages map[string]funnelModel `json:"languages"`
+	Variants  map[string]funnelModel `json:"variants,omitempty"`
+}
+
+type funnelModel struct {
+	Targeted     int     `json:"targeted"`
+	Delivered    int     `json:"delivered"`
+	Opened       int     `json:"opened"`
+	Clicked      int     `json:"clicked"`
+	DeliveryRate float64 `json:"deliveryRate" example:"0.95"`
+	OpenRate     float64 `json:"openRate" example:"0.2"`
+	ClickRate    float64 `json:"clickRate" example:"0.1"`
+}
@@ -0,0 +1,35 @@
+package analytics
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	"notifications/internal/service/analytics"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Handler interface {
+	Track(*gin.Context)
+	EventStats(*gin.Context)
+}
+
+type Params struct {
+	fx.In
+
+	Logger  logger.Logger
+	Service analytics.Service
+}
+
+type handler struct {
+	logger  logger.Logger
+	service analytics.Service
+}
+
+func New(p Params) Handler {
+	return &handler{
+		logger:  p.Logger,
+		service: p.Service,
+	}
+}
@@ -0,0 +1,21 @@
+package apiclient
+
+import "time"
+
+const _apiClient = "apiClient"
+
+var _ usageModel
+
+type usageModel struct {
+	Client    string     `json:"client"`
+	RateLimit int64      `json:"rateLimit"`
+	Daily     quotaModel `json:"daily"`
+	Monthly   quotaModel `json:"monthly"`
+}
+
+type quotaModel struct {
+	ResetAt   time.Time `json:"resetAt"`
+	Limit     int64     `json:"limit"`
+	Used      int64     `json:"used"`
+	Remaining int64     `json:"remaining" example:"-1"`
+}
@@ -0,0 +1,34 @@
+package apiclient
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	"notifications/internal/service/apiclient"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Handler interface {
+	Usage(*gin.Context)
+}
+
+type Params struct {
+	fx.In
+
+	Logger  logger.Logger
+	Service apiclient.Service
+}
+
+type handler struct {
+	logger  logger.Logger
+	service apiclient.Service
+}
+
+func New(p Params) Handler {
+	return &handler{
+		logger:  p.Logger,
+		service: p.Service,
+	}
+}
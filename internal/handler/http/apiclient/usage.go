@@ -0,0 +1,45 @@
+package apiclient
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/resp/code"
+)
+
+// Usage
+// @Description	Returns the rate limit and daily/monthly quotas of the api client with the current usage.
+// @Description	Quotas are counted per UTC day and month, `0` limit and `-1` remaining mean the quota is unlimited.
+// @Description	The request doesn't consume the quota.
+// @Tags			External
+// @Produce		application/json
+// @Param			X-UserId		header		string								true	"Provide user ID created on the server side"
+// @Param			X-RequestId		header		string								true	"Provide unique request ID to build hash and track the request"
+// @Param			X-Date			header		string								true	"Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash"
+// @Param			X-UserAction	header		string								true	"Provide any user action allowed for the api client"
+// @Param			X-RequestDigest	header		string								true	"Provide hash sum built with HMAC-SHA256 from the `X-Date:X-RequestId` using the secret key created on the server side"
+// @Success		200				{object}	resp.Response{payload=usageModel}	"Success"
+// @Failure		400				{object}	resp.Response						"Bad request"
+// @Failure		401				{object}	resp.Response						"Invalid authorization data"
+// @Failure		403				{object}	resp.Response						"Permission denied"
+// @Failure		500				{object}	resp.Response						"Internal Error"
+// @Security		SignatureAuth
+// @Router			/notifications-external/v1/usage [get]
+func (h *handler) Usage(c *gin.Context) {
+	var (
+		ctx          = c.Request.Context()
+		apiClient, _ = ctx.Value(_apiClient).(string)
+		response     resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	usage, err := h.service.Usage(ctx, apiClient)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = usage
+}
@@ -0,0 +1,135 @@
+package dlq
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/resp/code"
+	"notifications/internal/service/admin"
+	"notifications/internal/service/dlq"
+	"notifications/pkg/util/strset"
+)
+
+// Get
+//
+//	@Summary		Get list of dlq messages
+//	@Description	Returns the messages moved to the dead letter queue after all the delivery attempts failed, from the oldest to the newest.
+//	@Description	Pass `nextCursor` of the response as `cursor` to get the next page.
+//	@Tags			DLQ
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			subject	query		string								false	"apply filter with dlq subject, e.g. notifications.dlq.push.sent"
+//	@Param			cursor	query		int									false	"sequence to start from"
+//	@Param			limit	query		int									false	"apply filter with limit, 20 settled by default, 100 at most"
+//	@Success		200		{object}	resp.Response{payload=pageModel}	"Success"
+//	@Failure		400		{object}	resp.Response						"Bad request"
+//	@Failure		401		{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response						"Permission denied"
+//	@Failure		404		{object}	resp.Response						"List not found"
+//	@Failure		500		{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/dlq [get]
+func (h *handler) Get(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		subject  = c.Query(_subject)
+		cursor   = strset.ToInt(c.Query(_cursor))
+		limit    = strset.ToInt(c.Query(_limit))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	page, err := h.service.GetMessages(ctx, dlq.Filter{
+		Subject: subject,
+		Cursor:  uint64(max(cursor, 0)),
+		Limit:   limit,
+	})
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = page
+}
+
+// GetByID
+//
+//	@Summary	Get dlq message
+//	@Tags		DLQ
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Param		seq	path		int										true	"Sequence of the message in the dlq stream"
+//	@Success	200	{object}	resp.Response{payload=messageModel}	"Success"
+//	@Failure	401	{object}	resp.Response							"Invalid authorization data"
+//	@Failure	403	{object}	resp.Response							"Permission denied"
+//	@Failure	404	{object}	resp.Response							"Not found"
+//	@Failure	500	{object}	resp.Response							"Internal Error"
+//	@Router		/notifications-internal/v1/dlq/{seq} [get]
+func (h *handler) GetByID(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		seq      = strset.ToInt(c.Param(_seq))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	if seq <= 0 {
+		response = resp.RespondErr(resp.Wrap(resp.ErrBadRequest, "invalid sequence"))
+		return
+	}
+
+	message, err := h.service.GetMessage(ctx, uint64(seq))
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = message
+}
+
+// Replay
+//
+//	@Summary		Replay dlq message
+//	@Description	Publishes the message to its original subject as a new message with a fresh retry budget and removes it from the dlq.
+//	@Tags			DLQ
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			seq	path		int				true	"Sequence of the message in the dlq stream"
+//	@Success		200	{object}	resp.Response	"Success"
+//	@Failure		400	{object}	resp.Response	"Bad request"
+//	@Failure		401	{object}	resp.Response	"Invalid authorization data"
+//	@Failure		403	{object}	resp.Response	"Permission denied"
+//	@Failure		404	{object}	resp.Response	"Not found"
+//	@Failure		500	{object}	resp.Response	"Internal Error"
+//	@Router			/notifications-internal/v1/dlq/{seq}/replay [post]
+func (h *handler) Replay(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		seq      = strset.ToInt(c.Param(_seq))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	adminUser, ok := ctx.Value(admin.CtxKey).(admin.Admin)
+	if !ok {
+		response = resp.RespondErr(resp.ErrUnauthorized)
+		return
+	}
+
+	if seq <= 0 {
+		response = resp.RespondErr(resp.Wrap(resp.ErrBadRequest, "invalid sequence"))
+		return
+	}
+
+	err := h.service.Replay(ctx, adminUser, uint64(seq))
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+}
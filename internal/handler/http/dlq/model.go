@@ -0,0 +1,34 @@
+package dlq
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Route keys
+const (
+	_seq     = "seq"
+	_subject = "subject"
+	_cursor  = "cursor"
+	_limit   = "limit"
+)
+
+var _ pageModel
+
+type pageModel struct {
+	Items      []messageModel `json:"items"`
+	NextCursor uint64         `json:"nextCursor,omitempty"`
+}
+
+type messageModel struct {
+	Sequence        uint64          `json:"sequence"`
+	Subject         string          `json:"subject" example:"notifications.dlq.push.sent"`
+	OriginalStream  string          `json:"originalStream" example:"notifications"`
+	OriginalSubject string          `json:"originalSubject" example:"notifications.push.sent"`
+	Consumer        string          `json:"consumer" example:"notifications-push-processor"`
+	Error           string          `json:"error"`
+	Attempts        int             `json:"attempts"`
+	Data            json.RawMessage `json:"data" swaggertype:"object"`
+	FailedAt        time.Time       `json:"failedAt"`
+	ReceivedAt      time.Time       `json:"receivedAt"`
+}
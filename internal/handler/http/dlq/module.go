@@ -0,0 +1,36 @@
+package dlq
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	"notifications/internal/service/dlq"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Handler interface {
+	Get(*gin.Context)
+	GetByID(*gin.Context)
+	Replay(*gin.Context)
+}
+
+type Params struct {
+	fx.In
+
+	Logger  logger.Logger
+	Service dlq.Service
+}
+
+type handler struct {
+	logger  logger.Logger
+	service dlq.Service
+}
+
+func New(p Params) Handler {
+	return &handler{
+		logger:  p.Logger,
+		service: p.Service,
+	}
+}
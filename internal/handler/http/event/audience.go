@@ -0,0 +1,64 @@
+package event
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/resp/code"
+	"notifications/internal/service/event"
+	"notifications/pkg/util/serializer"
+)
+
+// EstimateAudience
+//
+//	@Summary		Estimate audience of segment
+//	@Description	Counts the users of the segment without loading them, reachable users have a token and didn't opt out of marketing pushes.
+//	@Tags			Events
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			data	body		segment									true	"Segment"
+//	@Success		200		{object}	resp.Response{payload=estimateModel}	"Success"
+//	@Failure		400		{object}	resp.Response							"Bad request"
+//	@Failure		401		{object}	resp.Response							"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response							"Permission denied"
+//	@Failure		500		{object}	resp.Response							"Internal Error"
+//	@Router			/notifications-internal/v1/events/audience/estimate [post]
+func (h *handler) EstimateAudience(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		response resp.Response
+		r        segment
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	err := serializer.BodyToJSON(c.Request, &r)
+	if err != nil {
+		err = resp.Wrap(resp.ErrBadRequest, err.Error())
+		response = resp.RespondErr(err)
+		return
+	}
+
+	estimate, err := h.service.EstimateAudience(ctx, *r.toService())
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = estimate
+}
+
+func (s *segment) toService() *event.Segment {
+	if s == nil {
+		return nil
+	}
+	return &event.Segment{
+		CountryID:     s.CountryID,
+		Languages:     s.Languages,
+		Statuses:      s.Statuses,
+		CreatedAfter:  s.CreatedAfter,
+		CreatedBefore: s.CreatedBefore,
+		PushEnabled:   s.PushEnabled,
+	}
+}
@@ -17,15 +17,17 @@ import (
 //	@Tags		Events
 //	@Accept		application/json
 //	@Produce	application/json
-//	@Param		id		query		string								false	"apply filter with id"
-//	@Param		status	query		string								false	"apply filter with status"
-//	@Param		topic	query		string								false	"apply filter with topic"
-//	@Param		limit	query		string								false	"apply filter with limit, 10 settled by default"
-//	@Param		offset	query		string								false	"apply filter with offset, 0 settled by default"
-//	@Success	200		{object}	resp.Response{payload=[]eventModel}	"Success"
-//	@Failure	401		{object}	resp.Response						"Invalid authorization data"
-//	@Failure	404		{object}	resp.Response						"List not found"
-//	@Failure	500		{object}	resp.Response						"Internal Error"
+//	@Param		id			query		string								false	"apply filter with id"
+//	@Param		status		query		string								false	"apply filter with status"
+//	@Param		topic		query		string								false	"apply filter with topic"
+//	@Param		countryID	query		string								false	"apply filter with country ID"
+//	@Param		limit		query		string								false	"apply filter with limit, 10 settled by default"
+//	@Param		offset		query		string								false	"apply filter with offset, 0 settled by default"
+//	@Success	200			{object}	resp.Response{payload=[]eventModel}	"Success"
+//	@Failure	401			{object}	resp.Response						"Invalid authorization data"
+//	@Failure	403			{object}	resp.Response						"Permission denied"
+//	@Failure	404			{object}	resp.Response						"List not found"
+//	@Failure	500			{object}	resp.Response						"Internal Error"
 //	@Router		/notifications-internal/v1/events [get]
 func (h *handler) Get(c *gin.Context) {
 	var (
@@ -33,6 +35,7 @@ func (h *handler) Get(c *gin.Context) {
 		id       = strset.ToInt(c.Query(_id))
 		status   = c.Query(_status)
 		topic    = c.Query(_topic)
+		country  = strset.ToInt(c.Query(_country))
 		limit    = strset.ToInt(c.Query(_limit))
 		offset   = strset.ToInt(c.Query(_offset))
 		response resp.Response
@@ -41,11 +44,12 @@ func (h *handler) Get(c *gin.Context) {
 	defer resp.JSON(c.Writer, code.Success, &response)
 
 	serviceResponse, err := h.service.GetEvents(ctx, event.Filter{
-		ID:     uint(id),
-		Topic:  topic,
-		Status: status,
-		Limit:  uint(limit),
-		Offset: uint(offset),
+		ID:        uint(id),
+		Topic:     topic,
+		Status:    status,
+		Limit:     uint(limit),
+		Offset:    uint(offset),
+		CountryID: int8(country),
 	})
 	if err != nil {
 		response = resp.RespondErr(err)
@@ -66,6 +70,7 @@ func (h *handler) Get(c *gin.Context) {
 //	@Success	200		{object}	resp.Response{payload=eventModel}	"Success"
 //	@Failure	400		{object}	resp.Response						"Bad request"
 //	@Failure	401		{object}	resp.Response						"Invalid authorization data"
+//	@Failure	403		{object}	resp.Response						"Permission denied"
 //	@Failure	500		{object}	resp.Response						"Internal Error"
 //	@Router		/notifications-internal/v1/events [post]
 func (h *handler) Create(c *gin.Context) {
@@ -96,6 +101,11 @@ func (h *handler) Create(c *gin.Context) {
 		Category:    r.Category,
 		Link:        r.Link,
 		ScheduledAt: r.ScheduledAt,
+		CountryID:   r.CountryID,
+		Segment:     r.Segment.toService(),
+		ABTest:      r.ABTest.toService(),
+		Recurrence:  r.Recurrence.toService(),
+		Delivery:    r.Delivery,
 		ExtraData:   r.ExtraData,
 		Title:       r.Title,
 		Body:        r.Body,
@@ -121,6 +131,7 @@ func (h *handler) Create(c *gin.Context) {
 //	@Success	200		{object}	resp.Response{payload=eventModel}	"Success"
 //	@Failure	400		{object}	resp.Response						"Bad request"
 //	@Failure	401		{object}	resp.Response						"Invalid authorization data"
+//	@Failure	403		{object}	resp.Response						"Permission denied"
 //	@Failure	404		{object}	resp.Response						"Not found"
 //	@Failure	500		{object}	resp.Response						"Internal Error"
 //	@Router		/notifications-internal/v1/events/{id} [put]
@@ -154,6 +165,10 @@ func (h *handler) Update(c *gin.Context) {
 		Category:    r.Category,
 		Link:        r.Link,
 		ScheduledAt: r.ScheduledAt,
+		Segment:     r.Segment.toService(),
+		ABTest:      r.ABTest.toService(),
+		Recurrence:  r.Recurrence.toService(),
+		Delivery:    r.Delivery,
 		ExtraData:   r.ExtraData,
 		Title:       r.Title,
 		Body:        r.Body,
@@ -177,6 +192,7 @@ func (h *handler) Update(c *gin.Context) {
 //	@Produce	application/json
 //	@Success	200	{object}	resp.Response	"Success"
 //	@Failure	401	{object}	resp.Response	"Invalid authorization data"
+//	@Failure	403	{object}	resp.Response	"Permission denied"
 //	@Failure	404	{object}	resp.Response	"Not found"
 //	@Failure	500	{object}	resp.Response	"Internal Error"
 //	@Router		/notifications-internal/v1/events/{id} [delete]
@@ -203,3 +219,26 @@ func (h *handler) Delete(c *gin.Context) {
 
 	response = resp.Success
 }
+
+func (t *abTest) toService() *event.ABTest {
+	if t == nil {
+		return nil
+	}
+
+	var test = &event.ABTest{
+		Variants:    make([]event.Variant, 0, len(t.Variants)),
+		WinnerAfter: t.WinnerAfter,
+	}
+	for _, v := range t.Variants {
+		test.Variants = append(test.Variants, event.Variant(v))
+	}
+
+	return test
+}
+
+func (r *recurrence) toService() *event.Recurrence {
+	if r == nil {
+		return nil
+	}
+	return &event.Recurrence{Cron: r.Cron, EndAt: r.EndAt, MaxOccurrences: r.MaxOccurrences}
+}
@@ -23,6 +23,7 @@ import (
 //	@Success		200		{object}	resp.Response{payload=eventModel}	"Success"
 //	@Failure		400		{object}	resp.Response						"Bad request"
 //	@Failure		401		{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response						"Permission denied"
 //	@Failure		404		{object}	resp.Response						"Not found"
 //	@Failure		500		{object}	resp.Response						"Internal Error"
 //	@Router			/notifications-internal/v1/events/{id}/image/{language} [post]
@@ -71,6 +72,7 @@ func (h *handler) UploadImage(c *gin.Context) {
 //	@Success		200	{object}	resp.Response{payload=eventModel}	"Success"
 //	@Failure		400	{object}	resp.Response						"Bad request"
 //	@Failure		401	{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403	{object}	resp.Response						"Permission denied"
 //	@Failure		404	{object}	resp.Response						"Not found"
 //	@Failure		500	{object}	resp.Response						"Internal Error"
 //	@Router			/notifications-internal/v1/events/{id}/image/{language} [delete]
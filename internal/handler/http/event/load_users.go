@@ -14,17 +14,21 @@ import (
 
 // LoadUsers
 //
-//	@Summary	Subscribe list of users to event
-//	@Tags		Events
-//	@Accept		multipart/form-data
-//	@Produce	application/json
-//	@Param		users	formData	file								true	"CSV file with `userID` header and data. Do not provide other headers"
-//	@Success	202		{object}	resp.Response{payload=eventModel}	"Accepted"
-//	@Failure	400		{object}	resp.Response						"Bad request"
-//	@Failure	401		{object}	resp.Response						"Invalid authorization data"
-//	@Failure	404		{object}	resp.Response						"Not found"
-//	@Failure	500		{object}	resp.Response						"Internal Error"
-//	@Router		/notifications-internal/v1/events/{id}/load-users [post]
+//	@Summary		Subscribe list of users to event
+//	@Description	The users are referred by the `userID`, `personExternalRef` or `phone` column of the file header, the other columns are ignored.
+//	@Description	The phones are normalized to the international format, the national numbers are completed with the calling code of the event country.
+//	@Description	The counts are saved to the event `extraData`, the rejected rows (invalid, not_found, deleted, no_token, other_country, opted_out) are in the report.
+//	@Tags			Events
+//	@Accept			multipart/form-data
+//	@Produce		application/json
+//	@Param			users	formData	file								true	"CSV or XLSX file, the first sheet of XLSX is read"
+//	@Success		202		{object}	resp.Response{payload=eventModel}	"Accepted"
+//	@Failure		400		{object}	resp.Response						"Bad request"
+//	@Failure		401		{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response						"Permission denied"
+//	@Failure		404		{object}	resp.Response						"Not found"
+//	@Failure		500		{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/load-users [post]
 func (h *handler) LoadUsers(c *gin.Context) {
 	var (
 		ctx      = c.Request.Context()
@@ -62,15 +66,17 @@ func (h *handler) LoadUsers(c *gin.Context) {
 
 // LoadAllUsers
 //
-//	@Summary	Subscribe all users to the event
-//	@Tags		Events
-//	@Produce	application/json
-//	@Success	202	{object}	resp.Response{payload=eventModel}	"Accepted"
-//	@Failure	400	{object}	resp.Response						"Bad request"
-//	@Failure	401	{object}	resp.Response						"Invalid authorization data"
-//	@Failure	404	{object}	resp.Response						"Not found"
-//	@Failure	500	{object}	resp.Response						"Internal Error"
-//	@Router		/notifications-internal/v1/events/{id}/load-all-users [post]
+//	@Summary		Subscribe all users to the event
+//	@Description	Subscribes the users of the event segment if the event has one, the users of the event country otherwise.
+//	@Tags			Events
+//	@Produce		application/json
+//	@Success		202	{object}	resp.Response{payload=eventModel}	"Accepted"
+//	@Failure		400	{object}	resp.Response						"Bad request"
+//	@Failure		401	{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403	{object}	resp.Response						"Permission denied"
+//	@Failure		404	{object}	resp.Response						"Not found"
+//	@Failure		500	{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/load-all-users [post]
 func (h *handler) LoadAllUsers(c *gin.Context) {
 	var (
 		ctx      = c.Request.Context()
@@ -95,3 +101,34 @@ func (h *handler) LoadAllUsers(c *gin.Context) {
 	response = resp.Accepted
 	response.Payload = serviceResponse
 }
+
+// GetRejectedReport
+//
+//	@Summary	Download report of rejected users
+//	@Tags		Events
+//	@Produce	text/csv
+//	@Param		id	path		string			true	"Event ID"
+//	@Success	200	{file}		file			"CSV with line, value and reason columns"
+//	@Failure	401	{object}	resp.Response	"Invalid authorization data"
+//	@Failure	403	{object}	resp.Response	"Permission denied"
+//	@Failure	404	{object}	resp.Response	"Not found"
+//	@Failure	500	{object}	resp.Response	"Internal Error"
+//	@Router		/notifications-internal/v1/events/{id}/rejected-users [get]
+func (h *handler) GetRejectedReport(c *gin.Context) {
+	var (
+		ctx = c.Request.Context()
+		id  = strset.ToInt(c.Param(_id))
+	)
+
+	report, err := h.service.GetRejectedReport(ctx, id)
+	if err != nil {
+		response := resp.RespondErr(err)
+		resp.JSON(c.Writer, code.Success, &response)
+		return
+	}
+	defer func() { _ = report.Close() }()
+
+	c.DataFromReader(http.StatusOK, -1, "text/csv", report, map[string]string{
+		"Content-Disposition": `attachment; filename="` + strset.IntToStr(id) + `_rejected.csv"`,
+	})
+}
@@ -18,32 +18,134 @@ const (
 	_users    = "users"
 	_language = "language"
 	_userID   = "userID"
+	_country  = "countryID"
+	_runID    = "runID"
 )
 
 type request struct {
-	Status      string            `json:"status"`
+	// Status is draft or pending_approval to submit the event for the approval right away
+	Status      string            `json:"status" example:"draft"`
 	Topic       string            `json:"topic"`
 	Category    string            `json:"category"`
 	Link        string            `json:"link"`
 	ScheduledAt string            `json:"scheduledAt"`
+	CountryID   int8              `json:"countryID"`
+	Segment     *segment          `json:"segment"`
+	ABTest      *abTest           `json:"abTest"`
+	Recurrence  *recurrence       `json:"recurrence"`
+	Delivery    string            `json:"delivery" enums:"topic,direct" example:"topic"`
 	Title       language.Language `json:"title"`
 	Body        language.Language `json:"body"`
 	ExtraData   map[string]string `json:"extraData"`
 }
 
+// segment of the users, the rules are joined with AND, the empty rules are not applied
+type segment struct {
+	CountryID     int8       `json:"countryID,omitempty" example:"1"`
+	Languages     []string   `json:"languages,omitempty" example:"uz"`
+	Statuses      []string   `json:"statuses,omitempty" example:"active"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty" example:"2025-01-01T00:00:00Z"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+	PushEnabled   *bool      `json:"pushEnabled,omitempty" example:"true"`
+}
+
+// abTest splits the users between the variants by the percents, the rest are the holdout which gets the winner
+type abTest struct {
+	Variants    []variant `json:"variants"`
+	WinnerAfter int       `json:"winnerAfter,omitempty" example:"120"`
+}
+
+type variant struct {
+	Key     string            `json:"key" example:"a"`
+	Percent int               `json:"percent" example:"10"`
+	Title   language.Language `json:"title"`
+	Body    language.Language `json:"body"`
+	Image   language.Language `json:"image"`
+}
+
+// recurrence runs the event at the occurrences of the cron expression from the scheduled time
+// in the timezone of the country of the event, UTC if it targets all the countries
+type recurrence struct {
+	Cron           string     `json:"cron" example:"0 10 * * 1"`
+	EndAt          *time.Time `json:"endAt,omitempty" example:"2026-01-01T00:00:00Z"`
+	MaxOccurrences int        `json:"maxOccurrences,omitempty" example:"10"`
+}
+
+type winnerRequest struct {
+	Variant string `json:"variant" example:"a"`
+}
+
 var _ eventModel
 
 type eventModel struct {
 	ID          int               `json:"id"`
 	Topic       string            `json:"topic"`
-	Status      string            `json:"status"`
+	Status      string            `json:"status" example:"scheduled"`
 	Title       language.Language `json:"title"`
 	Body        language.Language `json:"body"`
 	Image       language.Language `json:"image"`
 	Category    string            `json:"category"`
 	Link        string            `json:"link"`
+	CountryID   int8              `json:"countryID"`
+	Segment     *segment          `json:"segment,omitempty"`
+	ABTest      *abTest           `json:"abTest,omitempty"`
+	Recurrence  *recurrence       `json:"recurrence,omitempty"`
+	Delivery    string            `json:"delivery" example:"topic"`
+	SubmittedBy int               `json:"submittedBy,omitempty"`
+	ApprovedBy  int               `json:"approvedBy,omitempty"`
 	ExtraData   map[string]string `json:"extraData"`
 	CreatedAt   time.Time         `json:"createdAt"`
 	UpdatedAt   time.Time         `json:"updatedAt"`
 	ScheduledAt time.Time         `json:"scheduledAt"`
 }
+
+var _ runModel
+
+type runModel struct {
+	ID           int       `json:"id"`
+	EventID      int       `json:"eventID"`
+	Number       int       `json:"number"`
+	Status       string    `json:"status" example:"sent"`
+	SuccessCount int       `json:"successCount"`
+	FailedCount  int       `json:"failedCount"`
+	Error        string    `json:"error,omitempty"`
+	ScheduledAt  time.Time `json:"scheduledAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+var _ deliveryModel
+
+type deliveryModel struct {
+	RunID        int       `json:"runID"`
+	UserID       int       `json:"userID"`
+	Status       string    `json:"status" example:"failed"`
+	FcmMessageID string    `json:"fcmMessageID,omitempty"`
+	ErrorCode    string    `json:"errorCode,omitempty" example:"UNREGISTERED"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+var _ progressModel
+
+type progressModel struct {
+	RunID        int        `json:"runID"`
+	EventID      int        `json:"eventID"`
+	Status       string     `json:"status" enums:"running,sent,failed"`
+	Stage        string     `json:"stage" enums:"users,send,finish"`
+	Total        int        `json:"total"`
+	Processed    int        `json:"processed"`
+	Percent      float64    `json:"percent" example:"42.5"`
+	SuccessCount int        `json:"successCount"`
+	FailedCount  int        `json:"failedCount"`
+	Attempts     int        `json:"attempts"`
+	Error        string     `json:"error,omitempty"`
+	StartedAt    time.Time  `json:"startedAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	ETA          *time.Time `json:"eta,omitempty"`
+}
+
+var _ estimateModel
+
+type estimateModel struct {
+	Total     int `json:"total"`
+	Reachable int `json:"reachable"`
+}
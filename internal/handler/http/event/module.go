@@ -13,12 +13,17 @@ var Module = fx.Provide(New)
 type Handler interface {
 	writer
 	reader
+	reviewer
 	runner
 	imageManager
 }
 
 type reader interface {
 	Get(*gin.Context)
+	EstimateAudience(*gin.Context)
+	GetRuns(*gin.Context)
+	GetDeliveries(*gin.Context)
+	GetProgress(*gin.Context)
 }
 
 type writer interface {
@@ -27,10 +32,19 @@ type writer interface {
 	Delete(*gin.Context)
 }
 
+type reviewer interface {
+	Submit(*gin.Context)
+	Approve(*gin.Context)
+	Reject(*gin.Context)
+	Cancel(*gin.Context)
+}
+
 type runner interface {
 	LoadUsers(*gin.Context)
 	LoadAllUsers(*gin.Context)
+	GetRejectedReport(*gin.Context)
 	Run(*gin.Context)
+	SendWinner(*gin.Context)
 }
 
 type imageManager interface {
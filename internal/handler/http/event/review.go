@@ -0,0 +1,110 @@
+package event
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/resp/code"
+	"notifications/internal/service/admin"
+	"notifications/internal/service/event"
+	"notifications/pkg/util/strset"
+)
+
+// Submit
+//
+//	@Summary		Submit event for approval
+//	@Description	Moves the draft to pending_approval, it must be approved by another admin to be scheduled.
+//	@Tags			Events
+//	@Produce		application/json
+//	@Param			id	path		string								true	"Event ID"
+//	@Success		200	{object}	resp.Response{payload=eventModel}	"Success"
+//	@Failure		400	{object}	resp.Response						"Bad request"
+//	@Failure		401	{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403	{object}	resp.Response						"Permission denied"
+//	@Failure		404	{object}	resp.Response						"Not found"
+//	@Failure		500	{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/submit [post]
+func (h *handler) Submit(c *gin.Context) {
+	h.review(c, h.service.Submit)
+}
+
+// Approve
+//
+//	@Summary		Approve event
+//	@Description	Moves the submitted event to scheduled, the admin who submitted the event cannot approve it.
+//	@Tags			Events
+//	@Produce		application/json
+//	@Param			id	path		string								true	"Event ID"
+//	@Success		200	{object}	resp.Response{payload=eventModel}	"Success"
+//	@Failure		400	{object}	resp.Response						"Bad request"
+//	@Failure		401	{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403	{object}	resp.Response						"Permission denied"
+//	@Failure		404	{object}	resp.Response						"Not found"
+//	@Failure		500	{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/approve [post]
+func (h *handler) Approve(c *gin.Context) {
+	h.review(c, h.service.Approve)
+}
+
+// Reject
+//
+//	@Summary		Reject event
+//	@Description	Gets the submitted or scheduled event back to draft.
+//	@Tags			Events
+//	@Produce		application/json
+//	@Param			id	path		string								true	"Event ID"
+//	@Success		200	{object}	resp.Response{payload=eventModel}	"Success"
+//	@Failure		400	{object}	resp.Response						"Bad request"
+//	@Failure		401	{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403	{object}	resp.Response						"Permission denied"
+//	@Failure		404	{object}	resp.Response						"Not found"
+//	@Failure		500	{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/reject [post]
+func (h *handler) Reject(c *gin.Context) {
+	h.review(c, h.service.Reject)
+}
+
+// Cancel
+//
+//	@Summary		Cancel event
+//	@Description	Cancels the event which is pending approval, scheduled or ready, the loaded users are unsubscribed.
+//	@Tags			Events
+//	@Produce		application/json
+//	@Param			id	path		string								true	"Event ID"
+//	@Success		200	{object}	resp.Response{payload=eventModel}	"Success"
+//	@Failure		400	{object}	resp.Response						"Bad request"
+//	@Failure		401	{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403	{object}	resp.Response						"Permission denied"
+//	@Failure		404	{object}	resp.Response						"Not found"
+//	@Failure		500	{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/cancel [post]
+func (h *handler) Cancel(c *gin.Context) {
+	h.review(c, h.service.Cancel)
+}
+
+func (h *handler) review(c *gin.Context, action func(context.Context, admin.Admin, int) (*event.Event, error)) {
+	var (
+		ctx      = c.Request.Context()
+		id       = strset.ToInt(c.Param(_id))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	adminUser, ok := ctx.Value(admin.CtxKey).(admin.Admin)
+	if !ok {
+		response = resp.RespondErr(resp.ErrUnauthorized)
+		return
+	}
+
+	e, err := action(ctx, adminUser, id)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = e
+}
@@ -6,6 +6,8 @@ import (
 	"notifications/internal/api/resp"
 	"notifications/internal/api/resp/code"
 	"notifications/internal/service/admin"
+	"notifications/internal/service/event"
+	"notifications/pkg/util/serializer"
 	"notifications/pkg/util/strset"
 )
 
@@ -17,6 +19,7 @@ import (
 //	@Success	200	{object}	resp.Response{payload=eventModel}	"Success"
 //	@Failure	400	{object}	resp.Response						"Bad request"
 //	@Failure	401	{object}	resp.Response						"Invalid authorization data"
+//	@Failure	403	{object}	resp.Response						"Permission denied"
 //	@Failure	404	{object}	resp.Response						"Not found"
 //	@Failure	500	{object}	resp.Response						"Internal Error"
 //	@Router		/notifications-internal/v1/events/{id}/run [post]
@@ -44,3 +47,163 @@ func (h *handler) Run(c *gin.Context) {
 	response = resp.Success
 	response.Payload = serviceResponse
 }
+
+// SendWinner
+//
+//	@Summary		Send winner of A/B test
+//	@Description	Sends the variant to the holdout of the sent event, the empty variant picks the one with the best click rate.
+//	@Description	The winner is sent automatically after `winnerAfter` minutes if it's set.
+//	@Tags			Events
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			id		path		string								true	"Event ID"
+//	@Param			data	body		winnerRequest						false	"Request"
+//	@Success		200		{object}	resp.Response{payload=eventModel}	"Success"
+//	@Failure		400		{object}	resp.Response						"Bad request"
+//	@Failure		401		{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response						"Permission denied"
+//	@Failure		404		{object}	resp.Response						"Not found"
+//	@Failure		500		{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/winner [post]
+func (h *handler) SendWinner(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		id       = strset.ToInt(c.Param(_id))
+		response resp.Response
+		r        winnerRequest
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	adminUser, ok := ctx.Value(admin.CtxKey).(admin.Admin)
+	if !ok {
+		response = resp.RespondErr(resp.ErrUnauthorized)
+		return
+	}
+
+	if c.Request.ContentLength != 0 {
+		err := serializer.BodyToJSON(c.Request, &r)
+		if err != nil {
+			err = resp.Wrap(resp.ErrBadRequest, err.Error())
+			response = resp.RespondErr(err)
+			return
+		}
+	}
+
+	serviceResponse, err := h.service.SendWinner(ctx, adminUser, id, event.WinnerRequest{Variant: r.Variant})
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = serviceResponse
+}
+
+// GetRuns
+//
+//	@Summary		Get runs of event
+//	@Description	Returns the runs of the event starting from the last one, the recurring events have one per occurrence.
+//	@Description	The funnel of the run is returned by the stats of the event with `runID`.
+//	@Tags			Events
+//	@Produce		application/json
+//	@Param			id		path		string								true	"Event ID"
+//	@Param			limit	query		string								false	"apply filter with limit, 20 settled by default"
+//	@Param			offset	query		string								false	"apply filter with offset, 0 settled by default"
+//	@Success		200		{object}	resp.Response{payload=[]runModel}	"Success"
+//	@Failure		401		{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response						"Permission denied"
+//	@Failure		500		{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/runs [get]
+func (h *handler) GetRuns(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		id       = strset.ToInt(c.Param(_id))
+		limit    = strset.ToInt(c.Query(_limit))
+		offset   = strset.ToInt(c.Query(_offset))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	runs, err := h.service.GetRuns(ctx, id, uint(max(limit, 0)), uint(max(offset, 0)))
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = runs
+}
+
+// GetDeliveries
+//
+//	@Summary		Get deliveries of run
+//	@Description	Returns the results of the users of the run of the event sent directly, the invalid tokens are removed.
+//	@Tags			Events
+//	@Produce		application/json
+//	@Param			id		path		string									true	"Event ID"
+//	@Param			runID	path		string									true	"Run ID"
+//	@Param			limit	query		string									false	"apply filter with limit, 20 settled by default"
+//	@Param			offset	query		string									false	"apply filter with offset, 0 settled by default"
+//	@Success		200		{object}	resp.Response{payload=[]deliveryModel}	"Success"
+//	@Failure		401		{object}	resp.Response							"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response							"Permission denied"
+//	@Failure		500		{object}	resp.Response							"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/runs/{runID}/deliveries [get]
+func (h *handler) GetDeliveries(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		id       = strset.ToInt(c.Param(_id))
+		runID    = strset.ToInt(c.Param(_runID))
+		limit    = strset.ToInt(c.Query(_limit))
+		offset   = strset.ToInt(c.Query(_offset))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	deliveries, err := h.service.GetDeliveries(ctx, id, runID, uint(max(limit, 0)), uint(max(offset, 0)))
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = deliveries
+}
+
+// GetProgress
+//
+//	@Summary		Get progress of run
+//	@Description	Returns the progress of the run with the percent of the processed users and the estimated time of the end,
+//	@Description	the run of the crashed pod is resumed from the last checkpoint.
+//	@Tags			Events
+//	@Produce		application/json
+//	@Param			id		path		string									true	"Event ID"
+//	@Param			runID	path		string									true	"Run ID"
+//	@Success		200		{object}	resp.Response{payload=progressModel}	"Success"
+//	@Failure		401		{object}	resp.Response							"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response							"Permission denied"
+//	@Failure		404		{object}	resp.Response							"Not found"
+//	@Failure		500		{object}	resp.Response							"Internal Error"
+//	@Router			/notifications-internal/v1/events/{id}/runs/{runID}/progress [get]
+func (h *handler) GetProgress(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		id       = strset.ToInt(c.Param(_id))
+		runID    = strset.ToInt(c.Param(_runID))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	progress, err := h.service.GetProgress(ctx, id, runID)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = progress
+}
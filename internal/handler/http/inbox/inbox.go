@@ -0,0 +1,201 @@
+package inbox
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/resp/code"
+	"notifications/pkg/util/strset"
+)
+
+// Get
+// @Description	Returns the inbox of the user (stateful pushes and events) from the newest to the oldest.
+// @Description	Pass `nextCursor` of the response as `cursor` to get the next page, empty `nextCursor` means there are no more items.
+// @Tags			External
+// @Produce		application/json
+// @Param			X-UserId		header		string								true	"Provide user ID created on the server side"
+// @Param			X-RequestId		header		string								true	"Provide unique request ID to build hash and track the request"
+// @Param			X-Date			header		string								true	"Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash"
+// @Param			X-UserAction	header		string								true	"Provide user action (inbox) to read the inbox"
+// @Param			X-RequestDigest	header		string								true	"Provide hash sum built with HMAC-SHA256 from the `X-Date:X-RequestId` using the secret key created on the server side"
+// @Param			userID			path		int									true	"User ID"
+// @Param			cursor			query		string								false	"Cursor of the next page"
+// @Param			limit			query		int									false	"Page size, 20 by default, 100 at most"
+// @Success		200				{object}	resp.Response{payload=pageModel}	"Success"
+// @Failure		400				{object}	resp.Response						"Bad request"
+// @Failure		401				{object}	resp.Response						"Invalid authorization data"
+// @Failure		403				{object}	resp.Response						"Permission denied"
+// @Failure		500				{object}	resp.Response						"Internal Error"
+// @Security		SignatureAuth
+// @Router			/notifications-external/v1/inbox/{userID} [get]
+func (h *handler) Get(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		userID   = strset.ToInt(c.Param(_userID))
+		cursor   = c.Query(_cursor)
+		limit    = strset.ToInt(c.Query(_limit))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	page, err := h.service.GetInbox(ctx, userID, cursor, limit)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = page
+}
+
+// CountUnread
+// @Description	Returns the number of unread inbox items of the user.
+// @Tags			External
+// @Produce		application/json
+// @Param			X-UserId		header		string								true	"Provide user ID created on the server side"
+// @Param			X-RequestId		header		string								true	"Provide unique request ID to build hash and track the request"
+// @Param			X-Date			header		string								true	"Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash"
+// @Param			X-UserAction	header		string								true	"Provide user action (inbox) to read the inbox"
+// @Param			X-RequestDigest	header		string								true	"Provide hash sum built with HMAC-SHA256 from the `X-Date:X-RequestId` using the secret key created on the server side"
+// @Param			userID			path		int									true	"User ID"
+// @Success		200				{object}	resp.Response{payload=countModel}	"Success"
+// @Failure		400				{object}	resp.Response						"Bad request"
+// @Failure		401				{object}	resp.Response						"Invalid authorization data"
+// @Failure		403				{object}	resp.Response						"Permission denied"
+// @Failure		500				{object}	resp.Response						"Internal Error"
+// @Security		SignatureAuth
+// @Router			/notifications-external/v1/inbox/{userID}/unread-count [get]
+func (h *handler) CountUnread(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		userID   = strset.ToInt(c.Param(_userID))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	count, err := h.service.CountUnread(ctx, userID)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = countModel{Unread: count}
+}
+
+// MarkRead
+// @Description	Marks the inbox item as read.
+// @Tags			External
+// @Produce		application/json
+// @Param			X-UserId		header		string			true	"Provide user ID created on the server side"
+// @Param			X-RequestId		header		string			true	"Provide unique request ID to build hash and track the request"
+// @Param			X-Date			header		string			true	"Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash"
+// @Param			X-UserAction	header		string			true	"Provide user action (inbox) to update the inbox"
+// @Param			X-RequestDigest	header		string			true	"Provide hash sum built with HMAC-SHA256 from the `X-Date:X-RequestId` using the secret key created on the server side"
+// @Param			userID			path		int				true	"User ID"
+// @Param			source			path		string			true	"Item source (push, event)"
+// @Param			id				path		int				true	"Item ID"
+// @Success		200				{object}	resp.Response	"Success"
+// @Failure		400				{object}	resp.Response	"Bad request"
+// @Failure		401				{object}	resp.Response	"Invalid authorization data"
+// @Failure		403				{object}	resp.Response	"Permission denied"
+// @Failure		404				{object}	resp.Response	"Not found"
+// @Failure		500				{object}	resp.Response	"Internal Error"
+// @Security		SignatureAuth
+// @Router			/notifications-external/v1/inbox/{userID}/items/{source}/{id}/read [put]
+func (h *handler) MarkRead(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		userID   = strset.ToInt(c.Param(_userID))
+		source   = c.Param(_source)
+		id       = strset.ToInt(c.Param(_id))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	err := h.service.MarkRead(ctx, userID, source, id)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+}
+
+// MarkAllRead
+// @Description	Marks all the inbox items of the user as read.
+// @Tags			External
+// @Produce		application/json
+// @Param			X-UserId		header		string			true	"Provide user ID created on the server side"
+// @Param			X-RequestId		header		string			true	"Provide unique request ID to build hash and track the request"
+// @Param			X-Date			header		string			true	"Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash"
+// @Param			X-UserAction	header		string			true	"Provide user action (inbox) to update the inbox"
+// @Param			X-RequestDigest	header		string			true	"Provide hash sum built with HMAC-SHA256 from the `X-Date:X-RequestId` using the secret key created on the server side"
+// @Param			userID			path		int				true	"User ID"
+// @Success		200				{object}	resp.Response	"Success"
+// @Failure		400				{object}	resp.Response	"Bad request"
+// @Failure		401				{object}	resp.Response	"Invalid authorization data"
+// @Failure		403				{object}	resp.Response	"Permission denied"
+// @Failure		500				{object}	resp.Response	"Internal Error"
+// @Security		SignatureAuth
+// @Router			/notifications-external/v1/inbox/{userID}/read-all [put]
+func (h *handler) MarkAllRead(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		userID   = strset.ToInt(c.Param(_userID))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	err := h.service.MarkAllRead(ctx, userID)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+}
+
+// Delete
+// @Description	Deletes the inbox item of the user.
+// @Tags			External
+// @Produce		application/json
+// @Param			X-UserId		header		string			true	"Provide user ID created on the server side"
+// @Param			X-RequestId		header		string			true	"Provide unique request ID to build hash and track the request"
+// @Param			X-Date			header		string			true	"Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash"
+// @Param			X-UserAction	header		string			true	"Provide user action (inbox) to update the inbox"
+// @Param			X-RequestDigest	header		string			true	"Provide hash sum built with HMAC-SHA256 from the `X-Date:X-RequestId` using the secret key created on the server side"
+// @Param			userID			path		int				true	"User ID"
+// @Param			source			path		string			true	"Item source (push, event)"
+// @Param			id				path		int				true	"Item ID"
+// @Success		200				{object}	resp.Response	"Success"
+// @Failure		400				{object}	resp.Response	"Bad request"
+// @Failure		401				{object}	resp.Response	"Invalid authorization data"
+// @Failure		403				{object}	resp.Response	"Permission denied"
+// @Failure		404				{object}	resp.Response	"Not found"
+// @Failure		500				{object}	resp.Response	"Internal Error"
+// @Security		SignatureAuth
+// @Router			/notifications-external/v1/inbox/{userID}/items/{source}/{id} [delete]
+func (h *handler) Delete(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		userID   = strset.ToInt(c.Param(_userID))
+		source   = c.Param(_source)
+		id       = strset.ToInt(c.Param(_id))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	err := h.service.Delete(ctx, userID, source, id)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+}
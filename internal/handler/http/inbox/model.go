@@ -0,0 +1,39 @@
+package inbox
+
+import (
+	"time"
+
+	"notifications/internal/lib/language"
+)
+
+// Route keys
+const (
+	_userID = "userID"
+	_source = "source"
+	_id     = "id"
+	_cursor = "cursor"
+	_limit  = "limit"
+)
+
+var _ pageModel
+
+type pageModel struct {
+	Items      []itemModel `json:"items"`
+	NextCursor string      `json:"nextCursor"`
+}
+
+type itemModel struct {
+	Source    string            `json:"source" example:"push, event"`
+	Type      string            `json:"type"`
+	ID        int               `json:"id"`
+	Title     language.Language `json:"title"`
+	Body      language.Language `json:"body"`
+	Image     language.Language `json:"image"`
+	ExtraData map[string]string `json:"extraData"`
+	CreatedAt time.Time         `json:"createdAt"`
+	IsRead    bool              `json:"isRead"`
+}
+
+type countModel struct {
+	Unread int `json:"unread"`
+}
@@ -0,0 +1,38 @@
+package inbox
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	"notifications/internal/service/inbox"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Handler interface {
+	Get(*gin.Context)
+	CountUnread(*gin.Context)
+	MarkRead(*gin.Context)
+	MarkAllRead(*gin.Context)
+	Delete(*gin.Context)
+}
+
+type Params struct {
+	fx.In
+
+	Logger  logger.Logger
+	Service inbox.Service
+}
+
+type handler struct {
+	logger  logger.Logger
+	service inbox.Service
+}
+
+func New(p Params) Handler {
+	return &handler{
+		logger:  p.Logger,
+		service: p.Service,
+	}
+}
@@ -0,0 +1,41 @@
+package message
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/resp/code"
+)
+
+// GetByRequestID
+//
+//	@Summary		Get message
+//	@Description	Returns the message sent by `notifications.message.send` with the outcome of every channel of its policy.
+//	@Tags			Messages
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			id	path		string								true	"ID of the request given by the caller"
+//	@Success		200	{object}	resp.Response{payload=messageModel}	"Success"
+//	@Failure		401	{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403	{object}	resp.Response						"Permission denied"
+//	@Failure		404	{object}	resp.Response						"Not found"
+//	@Failure		500	{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/messages/{id} [get]
+func (h *handler) GetByRequestID(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		id       = c.Param(_id)
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	message, err := h.service.GetByRequestID(ctx, id)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = message
+}
@@ -0,0 +1,35 @@
+package message
+
+import "time"
+
+// Route keys
+const _id = "id"
+
+var _ messageModel
+
+type messageModel struct {
+	ID        int            `json:"id"`
+	RequestID string         `json:"requestID"`
+	UserID    int            `json:"userID,omitempty"`
+	Phone     string         `json:"phone,omitempty"`
+	Policy    []stepModel    `json:"policy"`
+	Attempts  []attemptModel `json:"attempts"`
+	Status    string         `json:"status" enums:"pending,delivered,failed"`
+	Channel   string         `json:"channel,omitempty" enums:"push,sms,email,telegram"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+type stepModel struct {
+	Channel string `json:"channel" enums:"push,sms,email,telegram"`
+	Timeout int    `json:"timeout,omitempty" example:"30"`
+}
+
+type attemptModel struct {
+	Channel    string    `json:"channel" enums:"push,sms,email,telegram"`
+	Status     string    `json:"status" enums:"sent,failed,skipped"`
+	MessageID  string    `json:"messageID,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
@@ -0,0 +1,34 @@
+package message
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	"notifications/internal/service/message"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Handler interface {
+	GetByRequestID(*gin.Context)
+}
+
+type Params struct {
+	fx.In
+
+	Logger  logger.Logger
+	Service message.Service
+}
+
+type handler struct {
+	logger  logger.Logger
+	service message.Service
+}
+
+func New(p Params) Handler {
+	return &handler{
+		logger:  p.Logger,
+		service: p.Service,
+	}
+}
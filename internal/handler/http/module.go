@@ -3,11 +3,25 @@ package http
 import (
 	"go.uber.org/fx"
 
+	"notifications/internal/handler/http/analytics"
+	"notifications/internal/handler/http/apiclient"
+	"notifications/internal/handler/http/dlq"
 	"notifications/internal/handler/http/event"
+	"notifications/internal/handler/http/inbox"
+	"notifications/internal/handler/http/message"
+	"notifications/internal/handler/http/preference"
 	"notifications/internal/handler/http/push"
+	"notifications/internal/handler/http/template"
 )
 
 var Module = fx.Options(
 	push.Module,
 	event.Module,
+	apiclient.Module,
+	inbox.Module,
+	dlq.Module,
+	template.Module,
+	preference.Module,
+	analytics.Module,
+	message.Module,
 )
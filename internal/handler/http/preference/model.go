@@ -0,0 +1,18 @@
+package preference
+
+import "time"
+
+// Route keys
+const (
+	_userID = "userID"
+)
+
+var _ preferenceModel
+
+type preferenceModel struct {
+	Category     string     `json:"category" example:"otp, transactional, marketing"`
+	Channel      string     `json:"channel" example:"push, sms, email"`
+	Enabled      bool       `json:"enabled"`
+	Suppressible bool       `json:"suppressible"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,47 @@
+package preference
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/resp/code"
+	"notifications/pkg/util/strset"
+)
+
+// Get
+// @Description	Returns the notification preferences of the user for every category and channel, the ones never changed are enabled.
+// @Description	Non-suppressible categories (otp) are always enabled, they are changed with `preferences` of the user settings update.
+// @Tags			External
+// @Produce		application/json
+// @Param			X-UserId		header		string										true	"Provide user ID created on the server side"
+// @Param			X-RequestId		header		string										true	"Provide unique request ID to build hash and track the request"
+// @Param			X-Date			header		string										true	"Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash"
+// @Param			X-UserAction	header		string										true	"Provide user action (preferences) to read the preferences"
+// @Param			X-RequestDigest	header		string										true	"Provide hash sum built with HMAC-SHA256 from the `X-Date:X-RequestId` using the secret key created on the server side"
+// @Param			userID			path		int											true	"User ID"
+// @Success		200				{object}	resp.Response{payload=[]preferenceModel}	"Success"
+// @Failure		400				{object}	resp.Response								"Bad request"
+// @Failure		401				{object}	resp.Response								"Invalid authorization data"
+// @Failure		403				{object}	resp.Response								"Permission denied"
+// @Failure		404				{object}	resp.Response								"User not found"
+// @Failure		500				{object}	resp.Response								"Internal Error"
+// @Security		SignatureAuth
+// @Router			/notifications-external/v1/preferences/{userID} [get]
+func (h *handler) Get(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		userID   = strset.ToInt(c.Param(_userID))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	preferences, err := h.service.GetPreferences(ctx, userID)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = preferences
+}
@@ -0,0 +1,54 @@
+package push
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/resp/code"
+)
+
+// GetDelivery
+// @Description	Returns the delivery status of the push sent by the api client, `id` is the `X-RequestId` of the send request.
+// @Description	Statuses:
+// @Description	- `queued` - the push is accepted and is being sent
+// @Description	- `sent` - the push is sent to FCM, `messageID` is the FCM message ID
+// @Description	- `failed` - FCM returned an error, see `errorCode` and `errorMessage`
+// @Description	- `token_invalid` - the device token of the user is not valid anymore
+// @Description	- `suppressed_inactive` - the user is inactive, the push is saved in the feed only
+// @Description	- `suppressed_disabled` - the user disabled pushes, the push is saved in the feed only
+// @Description	- `suppressed_opted_out` - the user opted out of the push category, the push is neither sent nor saved
+// @Description	- `deferred_quiet_hours` - the push landed inside the quiet hours of the user, it will be sent at their end
+// @Tags			External
+// @Produce		application/json
+// @Param			X-UserId		header		string								true	"Provide user ID created on the server side"
+// @Param			X-RequestId		header		string								true	"Provide unique request ID to build hash and track the request"
+// @Param			X-Date			header		string								true	"Provide current date in RFC1123 format (e.g., Mon, 02 Jan 2006 15:04:05 MST) to build hash"
+// @Param			X-UserAction	header		string								true	"Provide user action (push) to get the push status"
+// @Param			X-RequestDigest	header		string								true	"Provide hash sum built with HMAC-SHA256 from the `X-Date:X-RequestId` using the secret key created on the server side"
+// @Param			id				path		string								true	"X-RequestId of the send request"
+// @Success		200				{object}	resp.Response{payload=deliveryModel}	"Success"
+// @Failure		401				{object}	resp.Response						"Invalid authorization data"
+// @Failure		403				{object}	resp.Response						"Permission denied"
+// @Failure		404				{object}	resp.Response						"Not found"
+// @Failure		500				{object}	resp.Response						"Internal Error"
+// @Security		SignatureAuth
+// @Router			/notifications-external/v1/push/{id} [get]
+func (h *handler) GetDelivery(c *gin.Context) {
+	var (
+		ctx          = c.Request.Context()
+		apiClient, _ = ctx.Value(_apiClient).(string)
+		requestID    = c.Param(_id)
+		response     resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	delivery, err := h.service.GetDelivery(ctx, apiClient, requestID)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = delivery
+}
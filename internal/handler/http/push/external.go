@@ -16,6 +16,12 @@ import (
 // @Description	- If `showInFeed` is true, the push will be shown in the feed; otherwise, it will be hidden.
 // @Description	- If the users status is inactive or their push setting is disabled, the push will be saved in the feed but not sent to the device.
 // @Description	In that case, the payload will be `inactive_user#fake_message_id` or `disabled_push#fake_message_id`.
+// @Description	- If the user opted out of the push category, the push is neither saved nor sent, the payload will be `opted_out#fake_message_id`.
+// @Description	- `push` type pushes landing inside the quiet hours of the user are sent at their end, the payload will be `quiet_hours#fake_message_id`.
+// @Description	`otp` pushes are sent right away.
+// @Description	- The request consumes the rate limit and daily/monthly quotas of the api client, remaining values are returned in
+// @Description	`X-RateLimit-Remaining`, `X-Quota-Daily-Remaining` and `X-Quota-Monthly-Remaining` headers.
+// @Description	- The delivery status of the push can be checked by `X-RequestId` with `GET /notifications-external/v1/push/{id}`.
 // @Tags			External
 // @Accept			application/json
 // @Produce		application/json
@@ -29,6 +35,7 @@ import (
 // @Failure		400				{object}	resp.Response	"Bad request"
 // @Failure		401				{object}	resp.Response	"Invalid authorization data"
 // @Failure		404				{object}	resp.Response	"Not found"
+// @Failure		429				{object}	resp.Response	"Rate limit or quota exceeded, see `Retry-After` header"
 // @Failure		500				{object}	resp.Response	"Internal Error"
 // @Security		SignatureAuth
 // @Router			/notifications-external/v1/push [post]
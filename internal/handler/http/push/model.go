@@ -1,6 +1,8 @@
 package push
 
 import (
+	"time"
+
 	"notifications/internal/lib/language"
 )
 
@@ -9,6 +11,9 @@ const (
 	_apiClient = "apiClient"
 )
 
+// Route keys
+const _id = "id"
+
 type externalRequest struct {
 	Phone             string            `json:"phone" example:"+992111111111" validate:"required"`
 	PersonExternalRef string            `json:"personExternalRef" example:"123456"`
@@ -17,3 +22,15 @@ type externalRequest struct {
 	Body              language.Language `json:"body" validate:"required"`
 	ShowInFeed        bool              `json:"showInFeed" validate:"required"`
 }
+
+var _ deliveryModel
+
+type deliveryModel struct {
+	RequestID    string    `json:"requestID"`
+	Status       string    `json:"status" example:"queued, sent, failed, token_invalid, suppressed_inactive, suppressed_disabled, suppressed_opted_out, deferred_quiet_hours"`
+	MessageID    string    `json:"messageID"`
+	ErrorCode    string    `json:"errorCode" example:"UNREGISTERED"`
+	ErrorMessage string    `json:"errorMessage"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
@@ -12,6 +12,7 @@ var Module = fx.Provide(New)
 
 type Handler interface {
 	Send(*gin.Context)
+	GetDelivery(*gin.Context)
 }
 
 type Params struct {
@@ -0,0 +1,51 @@
+package template
+
+import (
+	"time"
+
+	"notifications/internal/lib/language"
+)
+
+// Route keys
+const (
+	_key    = "key"
+	_limit  = "limit"
+	_offset = "offset"
+)
+
+type request struct {
+	Key       string            `json:"key" example:"payment.received"`
+	Title     language.Language `json:"title"`
+	Body      language.Language `json:"body"`
+	Variables []variable        `json:"variables"`
+}
+
+type variable struct {
+	Name string `json:"name" example:"amount"`
+	Type string `json:"type" example:"number" enums:"string,number,integer,date"`
+}
+
+type previewRequest struct {
+	Language  string         `json:"language" example:"ru"`
+	Variables map[string]any `json:"variables"`
+}
+
+var (
+	_ templateModel
+	_ contentModel
+)
+
+type templateModel struct {
+	Key       string            `json:"key" example:"payment.received"`
+	Title     language.Language `json:"title"`
+	Body      language.Language `json:"body"`
+	Variables []variable        `json:"variables"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+type contentModel struct {
+	Language string `json:"language" example:"ru"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+}
@@ -0,0 +1,47 @@
+package template
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	"notifications/internal/service/template"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Handler interface {
+	reader
+	writer
+	Preview(*gin.Context)
+}
+
+type reader interface {
+	Get(*gin.Context)
+	GetByKey(*gin.Context)
+}
+
+type writer interface {
+	Create(*gin.Context)
+	Update(*gin.Context)
+	Delete(*gin.Context)
+}
+
+type Params struct {
+	fx.In
+
+	Logger  logger.Logger
+	Service template.Service
+}
+
+type handler struct {
+	logger  logger.Logger
+	service template.Service
+}
+
+func New(p Params) Handler {
+	return &handler{
+		logger:  p.Logger,
+		service: p.Service,
+	}
+}
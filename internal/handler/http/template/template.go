@@ -0,0 +1,266 @@
+package template
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/resp/code"
+	"notifications/internal/service/admin"
+	"notifications/internal/service/template"
+	"notifications/pkg/util/serializer"
+	"notifications/pkg/util/strset"
+)
+
+// Get
+//
+//	@Summary	Get list of templates
+//	@Tags		Templates
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Param		limit	query		string									false	"apply filter with limit, 20 settled by default"
+//	@Param		offset	query		string									false	"apply filter with offset, 0 settled by default"
+//	@Success	200		{object}	resp.Response{payload=[]templateModel}	"Success"
+//	@Failure	401		{object}	resp.Response							"Invalid authorization data"
+//	@Failure	403		{object}	resp.Response							"Permission denied"
+//	@Failure	404		{object}	resp.Response							"List not found"
+//	@Failure	500		{object}	resp.Response							"Internal Error"
+//	@Router		/notifications-internal/v1/templates [get]
+func (h *handler) Get(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		limit    = strset.ToInt(c.Query(_limit))
+		offset   = strset.ToInt(c.Query(_offset))
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	templates, err := h.service.GetTemplates(ctx, uint(max(limit, 0)), uint(max(offset, 0)))
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = templates
+}
+
+// GetByKey
+//
+//	@Summary	Get template
+//	@Tags		Templates
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Param		key	path		string								true	"Template key"
+//	@Success	200	{object}	resp.Response{payload=templateModel}	"Success"
+//	@Failure	401	{object}	resp.Response						"Invalid authorization data"
+//	@Failure	403	{object}	resp.Response						"Permission denied"
+//	@Failure	404	{object}	resp.Response						"Not found"
+//	@Failure	500	{object}	resp.Response						"Internal Error"
+//	@Router		/notifications-internal/v1/templates/{key} [get]
+func (h *handler) GetByKey(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		key      = c.Param(_key)
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	t, err := h.service.GetTemplate(ctx, key)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = t
+}
+
+// Create
+//
+//	@Summary		Create template
+//	@Description	Texts are Go templates, variables are referred as `{{.amount}}` and must be declared with the type: string, number, integer or date.
+//	@Tags			Templates
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			data	body		request								true	"Request"
+//	@Success		200		{object}	resp.Response{payload=templateModel}	"Success"
+//	@Failure		400		{object}	resp.Response						"Bad request"
+//	@Failure		401		{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response						"Permission denied"
+//	@Failure		500		{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/templates [post]
+func (h *handler) Create(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		response resp.Response
+		r        request
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	adminUser, ok := ctx.Value(admin.CtxKey).(admin.Admin)
+	if !ok {
+		response = resp.RespondErr(resp.ErrUnauthorized)
+		return
+	}
+
+	err := serializer.BodyToJSON(c.Request, &r)
+	if err != nil {
+		err = resp.Wrap(resp.ErrBadRequest, err.Error())
+		response = resp.RespondErr(err)
+		return
+	}
+
+	t, err := h.service.Create(ctx, adminUser, r.toService(r.Key))
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = t
+}
+
+// Update
+//
+//	@Summary	Update template
+//	@Tags		Templates
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Param		key		path		string								true	"Template key"
+//	@Param		data	body		request								true	"Request"
+//	@Success	200		{object}	resp.Response{payload=templateModel}	"Success"
+//	@Failure	400		{object}	resp.Response						"Bad request"
+//	@Failure	401		{object}	resp.Response						"Invalid authorization data"
+//	@Failure	403		{object}	resp.Response						"Permission denied"
+//	@Failure	404		{object}	resp.Response						"Not found"
+//	@Failure	500		{object}	resp.Response						"Internal Error"
+//	@Router		/notifications-internal/v1/templates/{key} [put]
+func (h *handler) Update(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		key      = c.Param(_key)
+		response resp.Response
+		r        request
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	adminUser, ok := ctx.Value(admin.CtxKey).(admin.Admin)
+	if !ok {
+		response = resp.RespondErr(resp.ErrUnauthorized)
+		return
+	}
+
+	err := serializer.BodyToJSON(c.Request, &r)
+	if err != nil {
+		err = resp.Wrap(resp.ErrBadRequest, err.Error())
+		response = resp.RespondErr(err)
+		return
+	}
+
+	t, err := h.service.Update(ctx, adminUser, r.toService(key))
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = t
+}
+
+// Delete
+//
+//	@Summary	Delete template
+//	@Tags		Templates
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Param		key	path		string			true	"Template key"
+//	@Success	200	{object}	resp.Response	"Success"
+//	@Failure	401	{object}	resp.Response	"Invalid authorization data"
+//	@Failure	403	{object}	resp.Response	"Permission denied"
+//	@Failure	404	{object}	resp.Response	"Not found"
+//	@Failure	500	{object}	resp.Response	"Internal Error"
+//	@Router		/notifications-internal/v1/templates/{key} [delete]
+func (h *handler) Delete(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		key      = c.Param(_key)
+		response resp.Response
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	adminUser, ok := ctx.Value(admin.CtxKey).(admin.Admin)
+	if !ok {
+		response = resp.RespondErr(resp.ErrUnauthorized)
+		return
+	}
+
+	err := h.service.Delete(ctx, adminUser, key)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+}
+
+// Preview
+//
+//	@Summary		Preview template
+//	@Description	Renders the template with the variables in the language, the same way it's rendered for the recipients.
+//	@Tags			Templates
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			key		path		string								true	"Template key"
+//	@Param			data	body		previewRequest						true	"Request"
+//	@Success		200		{object}	resp.Response{payload=contentModel}	"Success"
+//	@Failure		400		{object}	resp.Response						"Bad request"
+//	@Failure		401		{object}	resp.Response						"Invalid authorization data"
+//	@Failure		403		{object}	resp.Response						"Permission denied"
+//	@Failure		500		{object}	resp.Response						"Internal Error"
+//	@Router			/notifications-internal/v1/templates/{key}/preview [post]
+func (h *handler) Preview(c *gin.Context) {
+	var (
+		ctx      = c.Request.Context()
+		key      = c.Param(_key)
+		response resp.Response
+		r        previewRequest
+	)
+
+	defer resp.JSON(c.Writer, code.Success, &response)
+
+	err := serializer.BodyToJSON(c.Request, &r)
+	if err != nil {
+		err = resp.Wrap(resp.ErrBadRequest, err.Error())
+		response = resp.RespondErr(err)
+		return
+	}
+
+	content, err := h.service.Render(ctx, key, template.Recipient{Language: r.Language}, r.Variables)
+	if err != nil {
+		response = resp.RespondErr(err)
+		return
+	}
+
+	response = resp.Success
+	response.Payload = content
+}
+
+func (r *request) toService(key string) *template.Request {
+	var request = &template.Request{
+		Key:       key,
+		Title:     r.Title,
+		Body:      r.Body,
+		Variables: make([]template.Variable, 0, len(r.Variables)),
+	}
+
+	for _, v := range r.Variables {
+		request.Variables = append(request.Variables, template.Variable{Name: v.Name, Type: v.Type})
+	}
+
+	return request
+}
@@ -1,33 +1,52 @@
 package country
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
-)
+	_ "time/tzdata"
 
-const (
-	TjID     = 1
-	TjName   = "Tajikistan"
-	TjPrefix = "tj"
-)
+	"go.uber.org/fx"
 
-const (
-	_utc5         = "+5"
-	_utc5Duration = 5 * time.Hour
+	"notifications/internal/lib/language"
+	"notifications/internal/lib/quiethours"
+	"notifications/pkg/lib/config"
 )
 
-type Country struct {
-	Shard     string
-	Name      string
-	UTC       string
-	ToUTC     time.Duration
-	FromUTC   time.Duration
-	CountryID int8
+var Module = fx.Provide(New)
+
+var ErrUnknown = errors.New("unknown country")
+
+// _langPattern is the ISO 639 code of the language
+var _langPattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// Registry keeps the countries of the markets configured under `countries` keyed by the shard prefix
+type Registry interface {
+	// ByID returns the country, ErrUnknown if the country is not configured
+	ByID(id int8) (Country, error)
+	// GetAll returns the countries ordered by ID
+	GetAll() []Country
 }
 
-var Countries = [3]Country{{CountryID: TjID, Shard: TjPrefix, Name: TjName, UTC: _utc5, ToUTC: -_utc5Duration, FromUTC: _utc5Duration}}
+type Country struct {
+	ID         int8
+	Shard      string
+	Name       string
+	Timezone   string
+	Language   string
+	Languages  []string
+	QuietHours quiethours.Window
+	// PhoneCode is the calling code of the country and PhoneLength is the length of the national numbers
+	PhoneCode   string
+	PhoneLength int
+	location    *time.Location
+}
 
 func (c Country) GetID() int8 {
-	return c.CountryID
+	return c.ID
 }
 
 func (c Country) Prefix() string {
@@ -38,19 +57,175 @@ func (c Country) Postfix() string {
 	return "_" + c.Shard
 }
 
-func ByID(id int8) Country {
-	for _, country := range Countries {
-		if id == country.CountryID {
-			return country
+// Local returns the time in the timezone of the country
+func (c Country) Local(t time.Time) time.Time {
+	return t.In(c.location)
+}
+
+// Location returns the timezone of the country
+func (c Country) Location() *time.Location {
+	return c.location
+}
+
+// Supports reports if the language is supported in the country
+func (c Country) Supports(lang string) bool {
+	return slices.Contains(c.Languages, lang)
+}
+
+// ResolveLanguage returns the language if it's supported in the country, otherwise the default one
+func (c Country) ResolveLanguage(lang string) string {
+	if c.Supports(lang) {
+		return lang
+	}
+	return c.Language
+}
+
+// NormalizePhone returns the phone in the international format `+992XXXXXXXXX`, the formatting is removed,
+// `00` is read as `+` and the national numbers are completed with the calling code of the country
+func (c Country) NormalizePhone(phone string) (string, bool) {
+	var (
+		digits        strings.Builder
+		international bool
+	)
+
+	phone = strings.TrimSpace(phone)
+	if strings.HasPrefix(phone, "+") {
+		international = true
+	}
+
+	for _, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune(" -().+", r):
+		default:
+			return "", false
+		}
+	}
+
+	var number = digits.String()
+	if strings.HasPrefix(number, "00") && !international {
+		number, international = number[2:], true
+	}
+
+	switch {
+	case international:
+	case c.PhoneLength != 0 && len(number) == c.PhoneLength:
+		number = c.PhoneCode + number
+	case c.PhoneCode != "" && strings.HasPrefix(number, c.PhoneCode) && len(number) == len(c.PhoneCode)+c.PhoneLength:
+	default:
+		return "", false
+	}
+
+	// E.164 numbers have up to 15 digits
+	if len(number) < 8 || len(number) > 15 {
+		return "", false
+	}
+
+	return "+" + number, true
+}
+
+type Params struct {
+	fx.In
+
+	Config config.Config
+}
+
+type registry struct {
+	countries map[int8]Country
+	all       []Country
+}
+
+func New(p Params) (Registry, error) {
+	shards, _ := p.Config.Get("countries").(map[string]any)
+	if len(shards) == 0 {
+		return nil, errors.New("countries are not configured")
+	}
+
+	var r = &registry{
+		countries: make(map[int8]Country, len(shards)),
+		all:       make([]Country, 0, len(shards)),
+	}
+
+	for shard := range shards {
+		c, err := load(p.Config, shard)
+		if err != nil {
+			return nil, fmt.Errorf("country %s: %w", shard, err)
 		}
+
+		if _, ok := r.countries[c.ID]; ok {
+			return nil, fmt.Errorf("country %s: id %d is duplicated", shard, c.ID)
+		}
+
+		r.countries[c.ID] = c
+		r.all = append(r.all, c)
+	}
+
+	slices.SortFunc(r.all, func(a, b Country) int { return int(a.ID) - int(b.ID) })
+
+	return r, nil
+}
+
+func (r *registry) ByID(id int8) (Country, error) {
+	c, ok := r.countries[id]
+	if !ok {
+		return Country{}, fmt.Errorf("%w: %d", ErrUnknown, id)
 	}
-	return Countries[0]
+	return c, nil
 }
 
-func ConvertTimeToUTC(t time.Time, countryID int8) time.Time {
-	return t.Add(ByID(countryID).ToUTC)
+func (r *registry) GetAll() []Country {
+	return slices.Clone(r.all)
 }
 
-func ConvertTimeFromUTC(t time.Time, countryID int8) time.Time {
-	return t.Add(ByID(countryID).FromUTC)
+func load(cfg config.Config, shard string) (Country, error) {
+	var (
+		key = "countries." + shard + "."
+		c   = Country{
+			ID:        int8(cfg.GetInt(key + "id")),
+			Shard:     strings.ToLower(shard),
+			Name:      cfg.GetString(key + "name"),
+			Timezone:  cfg.GetString(key + "timezone"),
+			Language:  cfg.GetString(key + "language"),
+			Languages: cfg.GetStringSlice(key + "languages"),
+
+			PhoneCode:   cfg.GetString(key + "phone.code"),
+			PhoneLength: cfg.GetInt(key + "phone.length"),
+		}
+		err error
+	)
+
+	if c.ID <= 0 {
+		return Country{}, errors.New("id must be positive")
+	}
+
+	c.location, err = time.LoadLocation(c.Timezone)
+	if err != nil || c.Timezone == "" {
+		return Country{}, fmt.Errorf("timezone %q is not a valid IANA timezone", c.Timezone)
+	}
+
+	if len(c.Languages) == 0 {
+		c.Languages = language.GetAll()
+	}
+	for _, lang := range c.Languages {
+		if !_langPattern.MatchString(lang) {
+			return Country{}, fmt.Errorf("language %q is not a valid language code", lang)
+		}
+	}
+	if !c.Supports(c.Language) {
+		return Country{}, fmt.Errorf("default language %q is not one of the languages of the country", c.Language)
+	}
+
+	// the languages of the new markets are supported once they are configured
+	language.Register(c.Languages...)
+
+	var start, end = cfg.GetString(key + "quietHours.start"), cfg.GetString(key + "quietHours.end")
+	if start != "" || end != "" {
+		c.QuietHours, err = quiethours.Parse(start, end)
+		if err != nil {
+			return Country{}, err
+		}
+	}
+
+	return c, nil
 }
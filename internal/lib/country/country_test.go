@@ -0,0 +1,39 @@
+package country
+
+import "testing"
+
+func Test_NormalizePhone(t *testing.T) {
+	var tj = Country{PhoneCode: "992", PhoneLength: 9}
+
+	tests := []struct {
+		name    string
+		country Country
+		phone   string
+		want    string
+		ok      bool
+	}{
+		{name: "international", country: tj, phone: "+992900123456", want: "+992900123456", ok: true},
+		{name: "formatted", country: tj, phone: " +992 (90) 012-34-56 ", want: "+992900123456", ok: true},
+		{name: "double zero prefix", country: tj, phone: "00992900123456", want: "+992900123456", ok: true},
+		{name: "national", country: tj, phone: "900123456", want: "+992900123456", ok: true},
+		{name: "calling code without plus", country: tj, phone: "992900123456", want: "+992900123456", ok: true},
+		{name: "international of another country", country: tj, phone: "+79161234567", want: "+79161234567", ok: true},
+		{name: "national of wrong length", country: tj, phone: "90012345", ok: false},
+		{name: "another country without plus", country: tj, phone: "79161234567", ok: false},
+		{name: "letters", country: tj, phone: "+99290012345a", ok: false},
+		{name: "too short", country: tj, phone: "+1234567", ok: false},
+		{name: "too long", country: tj, phone: "+1234567890123456", ok: false},
+		{name: "empty", country: tj, phone: "", ok: false},
+		{name: "country without phone format", country: Country{}, phone: "900123456", ok: false},
+		{name: "international without phone format", country: Country{}, phone: "+992900123456", want: "+992900123456", ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.country.NormalizePhone(tt.phone)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("NormalizePhone(%q) = %q, %v, want %q, %v", tt.phone, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package language
 
-import "notifications/pkg/util/strset"
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/bytedance/sonic"
+
+	"notifications/pkg/util/strset"
+)
 
 const (
 	RU = "ru"
@@ -9,82 +17,150 @@ const (
 	EN = "en"
 )
 
-type Language struct {
-	RU string `json:"ru,omitempty"`
-	TJ string `json:"tg,omitempty"`
-	UZ string `json:"uz,omitempty"`
-	EN string `json:"en,omitempty"`
-}
+var (
+	mu        sync.RWMutex
+	supported = []string{RU, EN, TJ, UZ}
+)
+
+// Language keeps the texts by the language code, only the supported languages with non-empty texts are kept
+type Language map[string]string
 
 type Fields struct {
 	Key string
 	Val string
 }
 
+// Register adds the languages to the supported ones, it's called with the languages of the configured countries,
+// so a new market only needs the config
+func Register(langs ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, lang := range langs {
+		if lang != "" && !slices.Contains(supported, lang) {
+			supported = append(supported, lang)
+		}
+	}
+}
+
+// GetAll returns the supported languages
 func GetAll() []string {
-	return []string{RU, EN, TJ, UZ}
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return slices.Clone(supported)
+}
+
+// IsSupported reports if the language is registered
+func IsSupported(lang string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return slices.Contains(supported, lang)
+}
+
+// MarshalJSON encodes the empty texts as the empty object like the texts of all the languages are empty
+func (l Language) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return []byte("{}"), nil
+	}
+	return sonic.Marshal(map[string]string(l))
+}
+
+func (l *Language) UnmarshalJSON(data []byte) error {
+	var texts map[string]string
+	if err := sonic.Unmarshal(data, &texts); err != nil {
+		return err
+	}
+
+	*l = make(Language, len(texts))
+	for lang, text := range texts {
+		if text != "" && IsSupported(lang) {
+			(*l)[lang] = text
+		}
+	}
+
+	return nil
 }
 
 func (l *Language) ValidAny() bool {
-	return !strset.IsEmpty(l.RU) || !strset.IsEmpty(l.TJ) || !strset.IsEmpty(l.UZ) || !strset.IsEmpty(l.EN)
+	for _, text := range *l {
+		if !strset.IsEmpty(text) {
+			return true
+		}
+	}
+	return false
 }
 
 func (l *Language) Get(lang string) string {
-	switch lang {
-	case RU:
-		return l.RU
-	case TJ:
-		return l.TJ
-	case UZ:
-		return l.UZ
-	case EN:
-		return l.EN
-	default:
-		return ""
-	}
+	return (*l)[lang]
 }
 
+// GetAll returns the texts in the order of the supported languages
 func (l *Language) GetAll() []string {
-	return []string{l.RU, l.TJ, l.UZ, l.EN}
+	var (
+		langs = GetAll()
+		texts = make([]string, 0, len(langs))
+	)
+	for _, lang := range langs {
+		texts = append(texts, (*l)[lang])
+	}
+	return texts
 }
 
 func (l *Language) GetAllWithLang() []Fields {
-	return []Fields{
-		{Key: RU, Val: l.RU},
-		{Key: TJ, Val: l.TJ},
-		{Key: UZ, Val: l.UZ},
-		{Key: EN, Val: l.EN},
+	var (
+		langs  = GetAll()
+		fields = make([]Fields, 0, len(langs))
+	)
+	for _, lang := range langs {
+		fields = append(fields, Fields{Key: lang, Val: (*l)[lang]})
 	}
+	return fields
 }
 
+// Set sets the text of the supported language, the texts are copied first since the copies of the struct
+// holding the texts share the map
 func (l *Language) Set(lang, value string) {
-	switch lang {
-	case RU:
-		l.RU = value
-	case TJ:
-		l.TJ = value
-	case UZ:
-		l.UZ = value
-	case EN:
-		l.EN = value
+	if !IsSupported(lang) {
+		return
 	}
+
+	*l = maps.Clone(*l)
+	if *l == nil {
+		*l = make(Language, 1)
+	}
+
+	if value == "" {
+		delete(*l, lang)
+		return
+	}
+	(*l)[lang] = value
 }
 
 func (l *Language) SetAll(value string) {
-	l.RU = value
-	l.TJ = value
-	l.UZ = value
-	l.EN = value
+	var langs = GetAll()
+
+	*l = make(Language, len(langs))
+	if value == "" {
+		return
+	}
+	for _, lang := range langs {
+		(*l)[lang] = value
+	}
 }
 
+// Valid fills the empty texts of the supported languages with the russian one
 func (l *Language) Valid() {
-	if strset.IsEmpty(l.TJ) {
-		l.TJ = l.RU
+	var ru = l.Get(RU)
+	if strset.IsEmpty(ru) {
+		return
 	}
-	if strset.IsEmpty(l.UZ) {
-		l.UZ = l.RU
-	}
-	if strset.IsEmpty(l.EN) {
-		l.EN = l.RU
+
+	*l = maps.Clone(*l)
+	for _, lang := range GetAll() {
+		if strset.IsEmpty((*l)[lang]) {
+			(*l)[lang] = ru
+		}
 	}
 }
@@ -0,0 +1,78 @@
+package quiethours
+
+import (
+	"errors"
+	"time"
+)
+
+const Layout = "15:04"
+
+// Window is the time of day in local time when non-urgent notifications are held back,
+// it wraps midnight if the start is after the end, e.g. 22:00-08:00
+type Window struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Parse parses the start and end of the window in the "15:04" layout
+func Parse(start, end string) (Window, error) {
+	startTime, err := time.Parse(Layout, start)
+	if err != nil {
+		return Window{}, errors.New("quiet hours start is not valid, expected " + Layout)
+	}
+
+	endTime, err := time.Parse(Layout, end)
+	if err != nil {
+		return Window{}, errors.New("quiet hours end is not valid, expected " + Layout)
+	}
+
+	var window = Window{Start: sinceMidnight(startTime), End: sinceMidnight(endTime)}
+	if window.IsZero() {
+		return Window{}, errors.New("quiet hours start and end cannot be equal")
+	}
+
+	return window, nil
+}
+
+// IsZero reports if the window is empty, e.g. not configured
+func (w Window) IsZero() bool {
+	return w.Start == w.End
+}
+
+// Contains reports if the local time is inside the window
+func (w Window) Contains(local time.Time) bool {
+	if w.IsZero() {
+		return false
+	}
+
+	var clock = sinceMidnight(local)
+	if w.Start < w.End {
+		return clock >= w.Start && clock < w.End
+	}
+	return clock >= w.Start || clock < w.End
+}
+
+// Until returns the nearest end of the window after the local time
+func (w Window) Until(local time.Time) time.Time {
+	var end = at(local, 0, w.End)
+	if !end.After(local) {
+		end = at(local, 1, w.End)
+	}
+	return end
+}
+
+func (w Window) String() string {
+	var zero = time.Time{}
+	return zero.Add(w.Start).Format(Layout) + "-" + zero.Add(w.End).Format(Layout)
+}
+
+// at returns the wall clock time of the day in the location of t, days later, so it's right on DST changes
+func at(t time.Time, days int, clock time.Duration) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day+days, int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, t.Location())
+}
+
+// sinceMidnight returns the wall clock time of the day
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
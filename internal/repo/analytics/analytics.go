@@ -0,0 +1,85 @@
+package analytics
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+func (r *repo) Track(ctx context.Context, interaction Interaction, actions []string) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	_, err := r.db.Exec(ctx, `
+			WITH inserted AS (
+				INSERT INTO notification_interactions (source, source_id, user_id, action, language, variant) 
+				SELECT $1::text, $2::bigint, $3::bigint, action, $5::text, $6::text FROM unnest($4::text[]) AS action 
+				ON CONFLICT (source, source_id, user_id, action) DO NOTHING 
+				RETURNING action)
+			INSERT INTO notification_stats (source, source_id, language, variant, action, count) 
+			SELECT $1::text, $2::bigint, $5::text, $6::text, action, count(*) FROM inserted GROUP BY action 
+			ON CONFLICT (source, source_id, language, variant, action) 
+			DO UPDATE SET count = notification_stats.count + EXCLUDED.count, updated_at = now()`,
+		interaction.Source,
+		interaction.SourceID,
+		interaction.UserID,
+		actions,
+		interaction.Language,
+		interaction.Variant)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *repo) SetTargeted(ctx context.Context, source string, sourceID int, stats []Stat) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	var batch = new(pgx.Batch)
+	for _, stat := range stats {
+		batch.Queue(`
+			INSERT INTO notification_stats (source, source_id, language, variant, action, count) 
+			VALUES ($1, $2, $3, $4, $5, $6) 
+			ON CONFLICT (source, source_id, language, variant, action) DO UPDATE SET count = EXCLUDED.count, updated_at = now()`,
+			source, sourceID, stat.Language, stat.Variant, ActionTargeted, stat.Count)
+	}
+
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+func (r *repo) GetStats(ctx context.Context, source string, sourceID int) ([]Stat, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	rows, err := r.db.Query(ctx, `
+			SELECT language, variant, action, count, updated_at 
+			FROM notification_stats 
+			WHERE source = $1 AND source_id = $2 ORDER BY language, variant, action`, source, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats = make([]Stat, 0)
+	for rows.Next() {
+		var s Stat
+		err = rows.Scan(&s.Language, &s.Variant, &s.Action, &s.Count, &s.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(stats) == 0 {
+		return nil, repomodel.ErrNotFound
+	}
+
+	return stats, nil
+}
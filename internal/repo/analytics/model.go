@@ -0,0 +1,38 @@
+package analytics
+
+import "time"
+
+// Sources of the notifications
+const (
+	SourceEvent = "event"
+	SourcePush  = "push"
+	// SourceRun is the run of the event, the actions are counted for the event as well
+	SourceRun = "event_run"
+)
+
+// Actions, the targeted users are counted when the notification is sent and the rest are reported by the app
+const (
+	ActionTargeted  = "targeted"
+	ActionDelivered = "delivered"
+	ActionOpened    = "opened"
+	ActionClicked   = "clicked"
+)
+
+// Interaction of the user with the notification, it is unique per user and action
+type Interaction struct {
+	Source   string
+	SourceID int
+	UserID   int
+	Language string
+	// Variant is the key of the A/B test variant the user got, empty if the event has no A/B test
+	Variant string
+}
+
+// Stat is the counter of the action with the notification in the language and the variant
+type Stat struct {
+	Language  string
+	Variant   string
+	Action    string
+	Count     int
+	UpdatedAt time.Time
+}
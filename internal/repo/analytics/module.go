@@ -0,0 +1,45 @@
+package analytics
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/db"
+)
+
+var Module = fx.Provide(New)
+
+type Repo interface {
+	writer
+	reader
+}
+
+type writer interface {
+	// Track saves the actions of the user with the notification, the actions saved before are skipped
+	// so the counters of the stats are incremented once per user
+	Track(ctx context.Context, interaction Interaction, actions []string) error
+	// SetTargeted replaces the counters of the targeted users of the notification by language and variant,
+	// the actions of the stats are ignored
+	SetTargeted(ctx context.Context, source string, sourceID int, stats []Stat) error
+}
+
+type reader interface {
+	GetStats(ctx context.Context, source string, sourceID int) ([]Stat, error)
+}
+
+type Params struct {
+	fx.In
+
+	DB db.QueryExecutor
+}
+
+type repo struct {
+	db db.QueryExecutor
+}
+
+func New(p Params) Repo {
+	return &repo{
+		db: p.DB,
+	}
+}
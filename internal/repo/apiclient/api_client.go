@@ -18,7 +18,10 @@ func (r *repo) GetByUserID(ctx context.Context, userID string) (APIClient, error
 	})
 
 	var client APIClient
-	err := r.db.QueryRow(ctx, `SELECT client, api_key, permissions FROM api_clients WHERE client = $1`, userID).Scan(&client.Client, &client.APIKey, &client.Permissions)
+	err := r.db.QueryRow(ctx, `
+		SELECT client, api_key, permissions, COALESCE(rate_limit, 0), COALESCE(daily_quota, 0), COALESCE(monthly_quota, 0)
+		FROM api_clients WHERE client = $1`, userID).
+		Scan(&client.Client, &client.APIKey, &client.Permissions, &client.RateLimit, &client.DailyQuota, &client.MonthlyQuota)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return APIClient{}, repomodel.ErrNotFound
@@ -4,4 +4,8 @@ type APIClient struct {
 	Client      string
 	APIKey      string
 	Permissions []string
+	// RateLimit is the number of requests per second, quotas are counted per UTC day and month. 0 means unlimited
+	RateLimit    int64
+	DailyQuota   int64
+	MonthlyQuota int64
 }
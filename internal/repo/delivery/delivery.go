@@ -0,0 +1,76 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+func (r *repo) Insert(ctx context.Context, delivery *Delivery) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	_, err := r.db.Exec(ctx, `
+				INSERT INTO push_deliveries (id, push_id, user_id, request_id, api_client, status) 
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+		delivery.ID,
+		delivery.PushID,
+		delivery.UserID,
+		delivery.RequestID,
+		delivery.APIClient,
+		delivery.Status)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *repo) Update(ctx context.Context, delivery *Delivery) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	res, err := r.db.Exec(ctx, `
+				UPDATE push_deliveries SET 
+					push_id = $1,
+					status = $2,
+					fcm_message_id = $3,
+					error_code = $4,
+					error_message = $5,
+					updated_at = now()
+				WHERE id = $6`,
+		delivery.PushID,
+		delivery.Status,
+		delivery.FcmMessageID,
+		delivery.ErrorCode,
+		delivery.ErrorMessage,
+		delivery.ID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return repomodel.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) GetByRequestID(ctx context.Context, apiClient, requestID string) (*Delivery, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	var delivery = new(Delivery)
+	err := r.db.QueryRow(ctx, `SELECT `+_cols+` FROM push_deliveries WHERE api_client = $1 AND request_id = $2`, apiClient, requestID).
+		Scan(fields(delivery)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repomodel.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
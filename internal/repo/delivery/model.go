@@ -0,0 +1,46 @@
+package delivery
+
+import "time"
+
+type Delivery struct {
+	ID           int
+	PushID       int
+	UserID       int
+	RequestID    string
+	APIClient    string
+	Status       string
+	FcmMessageID string
+	ErrorCode    string
+	ErrorMessage string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+const _cols = `
+			id,
+			push_id,
+			user_id,
+			request_id,
+			api_client,
+			status,
+			fcm_message_id,
+			error_code,
+			error_message,
+			created_at,
+			updated_at`
+
+func fields(d *Delivery) []any {
+	return []any{
+		&d.ID,
+		&d.PushID,
+		&d.UserID,
+		&d.RequestID,
+		&d.APIClient,
+		&d.Status,
+		&d.FcmMessageID,
+		&d.ErrorCode,
+		&d.ErrorMessage,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	}
+}
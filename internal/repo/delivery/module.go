@@ -0,0 +1,33 @@
+package delivery
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/db"
+)
+
+var Module = fx.Provide(New)
+
+type Repo interface {
+	Insert(context.Context, *Delivery) error
+	Update(context.Context, *Delivery) error
+	GetByRequestID(ctx context.Context, apiClient, requestID string) (*Delivery, error)
+}
+
+type Params struct {
+	fx.In
+
+	DB db.QueryExecutor
+}
+
+type repo struct {
+	db db.QueryExecutor
+}
+
+func New(p Params) Repo {
+	return &repo{
+		db: p.DB,
+	}
+}
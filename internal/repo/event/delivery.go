@@ -0,0 +1,101 @@
+package event
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+const _deliveryCols = `
+			run_id,
+			event_id,
+			user_id,
+			status,
+			fcm_message_id,
+			error_code,
+			created_at`
+
+func (r *repo) BatchInsertDeliveries(ctx context.Context, deliveries []Delivery) (int64, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	return r.db.CopyFrom(
+		ctx,
+		pgx.Identifier{"event_deliveries"},
+		[]string{"run_id", "event_id", "user_id", "status", "fcm_message_id", "error_code"},
+		pgx.CopyFromSlice(len(deliveries), func(i int) ([]any, error) {
+			d := deliveries[i]
+			return []any{d.RunID, d.EventID, d.UserID, d.Status, d.FcmMessageID, d.ErrorCode}, nil
+		}))
+}
+
+func (r *repo) GetDeliveries(ctx context.Context, eventID, runID int, limit, offset uint) ([]*Delivery, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	rows, err := r.db.Query(ctx, `SELECT `+_deliveryCols+` FROM event_deliveries WHERE event_id = $1 AND run_id = $2 ORDER BY user_id LIMIT $3 OFFSET $4`, eventID, runID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries = make([]*Delivery, 0)
+	for rows.Next() {
+		var d = new(Delivery)
+		err = rows.Scan(&d.RunID, &d.EventID, &d.UserID, &d.Status, &d.FcmMessageID, &d.ErrorCode, &d.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		return nil, repomodel.ErrNotFound
+	}
+
+	return deliveries, nil
+}
+
+func (r *repo) GetDeliveryStatuses(ctx context.Context, runID int, userIDs []int) (map[int]string, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	rows, err := r.db.Query(ctx, `SELECT user_id, status FROM event_deliveries WHERE run_id = $1 AND user_id = ANY($2)`, runID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statuses = make(map[int]string)
+	for rows.Next() {
+		var (
+			userID int
+			status string
+		)
+		err = rows.Scan(&userID, &status)
+		if err != nil {
+			return nil, err
+		}
+		statuses[userID] = status
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"notifications/internal/lib/language"
+	"notifications/internal/repo/user"
 )
 
 type Event struct {
@@ -19,14 +20,94 @@ type Event struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	ScheduledAt time.Time
+	// CountryID targets the users of the country, 0 targets all the countries
+	CountryID int8
+	// Segment is the rule-based audience of the event, nil if the users are loaded from a file or all of them
+	Segment *user.Segment
+	// ABTest splits the audience between the variants of the texts, nil if all the users get the same texts
+	ABTest *ABTest
+	// Recurrence runs the event repeatedly, nil if the event is run once
+	Recurrence *Recurrence
+	// SubmittedBy is the admin who submitted the event for approval and ApprovedBy is the other one who approved it
+	SubmittedBy int
+	ApprovedBy  int
+	// Delivery is how the event reaches the users, to the topics they are subscribed to or directly to their tokens
+	Delivery string
+}
+
+// Recurrence of the event, the occurrences of the cron expression are in the timezone of the country of the event,
+// UTC if it targets all the countries
+type Recurrence struct {
+	Cron string `json:"cron"`
+	// EndAt and MaxOccurrences stop the recurrence, the zero ones are not applied
+	EndAt          *time.Time `json:"endAt,omitempty"`
+	MaxOccurrences int        `json:"maxOccurrences,omitempty"`
+}
+
+// Run is the record of the send of the event, the recurring events have one per occurrence
+type Run struct {
+	ID           int
+	EventID      int
+	Number       int
+	Status       string
+	SuccessCount int
+	FailedCount  int
+	Error        string
+	ScheduledAt  time.Time
+	CreatedAt    time.Time
+}
+
+// Progress is the checkpoint of the run, the users of the event are processed in the pages after Cursor,
+// Attempts is increased every time the run is resumed and fences the checkpoints of the previous attempts
+type Progress struct {
+	RunID        int
+	EventID      int
+	Status       string
+	Stage        string
+	Cursor       int
+	Total        int
+	Processed    int
+	SuccessCount int
+	FailedCount  int
+	Attempts     int
+	Error        string
+	StartedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Delivery is the result of the direct send of the event to the user in the run
+type Delivery struct {
+	RunID        int
+	EventID      int
+	UserID       int
+	Status       string
+	FcmMessageID string
+	ErrorCode    string
+	CreatedAt    time.Time
+}
+
+// ABTest splits the audience of the event between the variants by the percents, the rest is the holdout
+type ABTest struct {
+	Variants []Variant `json:"variants"`
+	// WinnerAfter is the number of minutes after the send the winner is sent to the holdout, 0 if it's picked manually
+	WinnerAfter int `json:"winnerAfter,omitempty"`
+}
+
+type Variant struct {
+	Key     string            `json:"key"`
+	Percent int               `json:"percent"`
+	Title   language.Language `json:"title"`
+	Body    language.Language `json:"body"`
+	Image   language.Language `json:"image"`
 }
 
 type Filter struct {
-	Topic  string
-	Status string
-	ID     uint
-	Limit  uint
-	Offset uint
+	Topic     string
+	Status    string
+	ID        uint
+	Limit     uint
+	Offset    uint
+	CountryID int8
 }
 
 const _cols = `
@@ -41,7 +122,14 @@ const _cols = `
 			extra_data,
 			created_at, 
 			updated_at, 
-			scheduled_at`
+			scheduled_at,
+			country_id,
+			segment,
+			ab_test,
+			recurrence,
+			submitted_by,
+			approved_by,
+			delivery`
 
 func fields(e *Event) []any {
 	return []any{
@@ -57,5 +145,70 @@ func fields(e *Event) []any {
 		&e.CreatedAt,
 		&e.UpdatedAt,
 		&e.ScheduledAt,
+		&e.CountryID,
+		&e.Segment,
+		&e.ABTest,
+		&e.Recurrence,
+		&e.SubmittedBy,
+		&e.ApprovedBy,
+		&e.Delivery,
+	}
+}
+
+const _runCols = `
+			id,
+			event_id,
+			number,
+			status,
+			success_count,
+			failed_count,
+			error,
+			scheduled_at,
+			created_at`
+
+func runFields(r *Run) []any {
+	return []any{
+		&r.ID,
+		&r.EventID,
+		&r.Number,
+		&r.Status,
+		&r.SuccessCount,
+		&r.FailedCount,
+		&r.Error,
+		&r.ScheduledAt,
+		&r.CreatedAt,
+	}
+}
+
+const _progressCols = `
+			run_id,
+			event_id,
+			status,
+			stage,
+			cursor,
+			total,
+			processed,
+			success_count,
+			failed_count,
+			attempts,
+			error,
+			started_at,
+			updated_at`
+
+func progressFields(p *Progress) []any {
+	return []any{
+		&p.RunID,
+		&p.EventID,
+		&p.Status,
+		&p.Stage,
+		&p.Cursor,
+		&p.Total,
+		&p.Processed,
+		&p.SuccessCount,
+		&p.FailedCount,
+		&p.Attempts,
+		&p.Error,
+		&p.StartedAt,
+		&p.UpdatedAt,
 	}
 }
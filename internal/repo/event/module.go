@@ -2,6 +2,7 @@ package event
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/fx"
 
@@ -20,17 +21,39 @@ type writer interface {
 	Create(context.Context, *Event) (*Event, error)
 	Update(context.Context, *Event) (*Event, error)
 	UpdateImage(ctx context.Context, id int, image language.Language) (*Event, error)
-	UpdateStatus(ctx context.Context, id int, status string) error
+	// Transition changes the status of the event if it's still the given one, ErrNotFound otherwise
+	Transition(ctx context.Context, id int, from, to string) error
+	SetReviewers(ctx context.Context, id, submittedBy, approvedBy int) error
 	UpdateExtraData(ctx context.Context, id int, extraData map[string]string) error
+	Reschedule(ctx context.Context, id int, scheduledAt time.Time) error
+	CreateRun(ctx context.Context, run *Run) (*Run, error)
+	FinishRun(ctx context.Context, run *Run) error
+	CreateProgress(ctx context.Context, p *Progress) error
+	// ClaimProgress takes over the stale run, Checkpoint saves the progress of the attempt which claimed the run last,
+	// both return ErrNotFound if the run is taken over by another attempt
+	ClaimProgress(ctx context.Context, p *Progress, updatedBefore time.Time) error
+	Checkpoint(ctx context.Context, p *Progress) error
+	// BatchInsertDeliveries saves the per-user results of the direct send of the event
+	BatchInsertDeliveries(ctx context.Context, deliveries []Delivery) (int64, error)
 	Delete(ctx context.Context, id int) error
 }
 
 type reader interface {
+	// GetByID and GetByIDWithLock return the event which is not sent, failed or cancelled
 	GetByID(ctx context.Context, id int) (*Event, error)
-	GetActiveByIDWithLock(ctx context.Context, eventID int) (*Event, error)
+	GetByIDWithLock(ctx context.Context, eventID int) (*Event, error)
+	GetSentByIDWithLock(ctx context.Context, eventID int) (*Event, error)
 	GetByFilter(context.Context, Filter) ([]*Event, error)
-	GetAllActive(ctx context.Context) ([]*Event, error)
+	GetAllReady(ctx context.Context) ([]*Event, error)
 	GetSent(ctx context.Context) ([]*Event, error)
+	GetRuns(ctx context.Context, eventID int, limit, offset uint) ([]*Run, error)
+	GetRunByID(ctx context.Context, id int) (*Run, error)
+	GetProgress(ctx context.Context, runID int) (*Progress, error)
+	// GetStaleProgress returns the runs in the status which are not updated since updatedBefore
+	GetStaleProgress(ctx context.Context, status string, updatedBefore time.Time) ([]*Progress, error)
+	GetDeliveries(ctx context.Context, eventID, runID int, limit, offset uint) ([]*Delivery, error)
+	// GetDeliveryStatuses returns the statuses of the deliveries of the users recorded in the run by the user
+	GetDeliveryStatuses(ctx context.Context, runID int, userIDs []int) (map[int]string, error)
 }
 
 type Params struct {
@@ -0,0 +1,146 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+func (r *repo) CreateProgress(ctx context.Context, p *Progress) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	_, err := r.db.Exec(ctx, `
+				INSERT INTO event_run_progress (run_id, event_id, status, stage, cursor, total) 
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+		p.RunID,
+		p.EventID,
+		p.Status,
+		p.Stage,
+		p.Cursor,
+		p.Total)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *repo) GetProgress(ctx context.Context, runID int) (*Progress, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	var p = new(Progress)
+	err := r.db.QueryRow(ctx, `SELECT `+_progressCols+` FROM event_run_progress WHERE run_id = $1`, runID).Scan(progressFields(p)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repomodel.ErrNotFound
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *repo) GetStaleProgress(ctx context.Context, status string, updatedBefore time.Time) ([]*Progress, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	rows, err := r.db.Query(ctx, `SELECT `+_progressCols+` FROM event_run_progress WHERE status = $1 AND updated_at < $2 ORDER BY updated_at`, status, updatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list = make([]*Progress, 0)
+	for rows.Next() {
+		var p = new(Progress)
+		err = rows.Scan(progressFields(p)...)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, repomodel.ErrNotFound
+	}
+
+	return list, nil
+}
+
+// ClaimProgress takes over the run if it's not updated since updatedBefore, the attempts are increased
+// so the checkpoints of the previous attempt are rejected
+func (r *repo) ClaimProgress(ctx context.Context, p *Progress, updatedBefore time.Time) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	err := r.db.QueryRow(ctx, `
+				UPDATE event_run_progress SET attempts = attempts + 1, updated_at = now() 
+				WHERE run_id = $1 AND status = $2 AND attempts = $3 AND updated_at < $4 
+				RETURNING `+_progressCols,
+		p.RunID,
+		p.Status,
+		p.Attempts,
+		updatedBefore).Scan(progressFields(p)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repomodel.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Checkpoint saves the progress of the attempt of the run, ErrNotFound if the run is taken over by another attempt
+func (r *repo) Checkpoint(ctx context.Context, p *Progress) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	res, err := r.db.Exec(ctx, `
+				UPDATE event_run_progress SET 
+					status = $1,
+					stage = $2,
+					cursor = $3,
+					processed = $4,
+					success_count = $5,
+					failed_count = $6,
+					error = $7,
+					updated_at = now()
+				WHERE run_id = $8 AND attempts = $9`,
+		p.Status,
+		p.Stage,
+		p.Cursor,
+		p.Processed,
+		p.SuccessCount,
+		p.FailedCount,
+		p.Error,
+		p.RunID,
+		p.Attempts)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return repomodel.ErrNotFound
+	}
+	return nil
+}
@@ -14,15 +14,19 @@ import (
 )
 
 func (r *repo) GetByID(ctx context.Context, eventID int) (*Event, error) {
-	return r.selectEvent(ctx, "id = $1 AND (status = 'active' OR status = 'draft')", eventID)
+	return r.selectEvent(ctx, "id = $1 AND status NOT IN ('sent', 'failed', 'cancelled')", eventID)
 }
 
-func (r *repo) GetActiveByIDWithLock(ctx context.Context, eventID int) (*Event, error) {
-	return r.selectEvent(ctx, "id = $1 AND (status = 'active' OR status = 'draft') FOR UPDATE", eventID)
+func (r *repo) GetByIDWithLock(ctx context.Context, eventID int) (*Event, error) {
+	return r.selectEvent(ctx, "id = $1 AND status NOT IN ('sent', 'failed', 'cancelled') FOR UPDATE", eventID)
 }
 
-func (r *repo) GetAllActive(ctx context.Context) ([]*Event, error) {
-	return r.selectEvents(ctx, "status = 'active'")
+func (r *repo) GetSentByIDWithLock(ctx context.Context, eventID int) (*Event, error) {
+	return r.selectEvent(ctx, "id = $1 AND status = 'sent' FOR UPDATE", eventID)
+}
+
+func (r *repo) GetAllReady(ctx context.Context) ([]*Event, error) {
+	return r.selectEvents(ctx, "status = 'ready'")
 }
 
 func (r *repo) GetSent(ctx context.Context) ([]*Event, error) {
@@ -59,6 +63,11 @@ func (r *repo) GetByFilter(ctx context.Context, filter Filter) ([]*Event, error)
 		conditions += " AND status = $" + strset.IntToStr(int(idx))
 		args = append(args, filter.Status)
 	}
+	if filter.CountryID != 0 {
+		idx++
+		conditions += " AND country_id = $" + strset.IntToStr(int(idx))
+		args = append(args, filter.CountryID)
+	}
 
 	conditions += " ORDER BY updated_at DESC LIMIT $" + strset.IntToStr(int(idx+1)) + " OFFSET $" + strset.IntToStr(int(idx+2))
 	args = append(args, filter.Limit, filter.Offset)
@@ -0,0 +1,106 @@
+package event
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+// CreateRun saves the run numbering it after the previous runs of the event, the event is expected to be locked
+func (r *repo) CreateRun(ctx context.Context, run *Run) (*Run, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	var created = new(Run)
+	err := r.db.QueryRow(ctx, `
+				INSERT INTO event_runs (id, event_id, number, status, success_count, failed_count, error, scheduled_at) 
+				VALUES ($1, $2, (SELECT COALESCE(max(number), 0) + 1 FROM event_runs WHERE event_id = $2), $3, $4, $5, $6, $7) 
+				RETURNING `+_runCols,
+		run.ID,
+		run.EventID,
+		run.Status,
+		run.SuccessCount,
+		run.FailedCount,
+		run.Error,
+		run.ScheduledAt).Scan(runFields(created)...)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *repo) GetRunByID(ctx context.Context, id int) (*Run, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	var run = new(Run)
+	err := r.db.QueryRow(ctx, `SELECT `+_runCols+` FROM event_runs WHERE id = $1`, id).Scan(runFields(run)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repomodel.ErrNotFound
+		}
+		return nil, err
+	}
+	return run, nil
+}
+
+// FinishRun saves the status, the counts and the error of the run
+func (r *repo) FinishRun(ctx context.Context, run *Run) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	_, err := r.db.Exec(ctx, `UPDATE event_runs SET status = $1, success_count = $2, failed_count = $3, error = $4 WHERE id = $5`,
+		run.Status,
+		run.SuccessCount,
+		run.FailedCount,
+		run.Error,
+		run.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *repo) GetRuns(ctx context.Context, eventID int, limit, offset uint) ([]*Run, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	rows, err := r.db.Query(ctx, `SELECT `+_runCols+` FROM event_runs WHERE event_id = $1 ORDER BY number DESC LIMIT $2 OFFSET $3`, eventID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs = make([]*Run, 0)
+	for rows.Next() {
+		var run = new(Run)
+		err = rows.Scan(runFields(run)...)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(runs) == 0 {
+		return nil, repomodel.ErrNotFound
+	}
+
+	return runs, nil
+}
@@ -2,10 +2,12 @@ package event
 
 import (
 	"context"
+	"time"
 
 	"notifications/internal/db"
 	"notifications/internal/lib/ctxman"
 	"notifications/internal/lib/language"
+	"notifications/internal/repo/repomodel"
 )
 
 func (r *repo) Create(ctx context.Context, event *Event) (*Event, error) {
@@ -16,8 +18,8 @@ func (r *repo) Create(ctx context.Context, event *Event) (*Event, error) {
 
 	var e = new(Event)
 	err := r.db.QueryRow(ctx, `
-				INSERT INTO events (id, topic, status, title, body, image, category, link, extra_data, scheduled_at) 
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING `+_cols,
+				INSERT INTO events (id, topic, status, title, body, image, category, link, extra_data, scheduled_at, country_id, segment, ab_test, recurrence, delivery) 
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING `+_cols,
 		event.ID,
 		event.Topic,
 		event.Status,
@@ -27,7 +29,12 @@ func (r *repo) Create(ctx context.Context, event *Event) (*Event, error) {
 		event.Category,
 		event.Link,
 		event.ExtraData,
-		event.ScheduledAt).Scan(fields(e)...)
+		event.ScheduledAt,
+		event.CountryID,
+		event.Segment,
+		event.ABTest,
+		event.Recurrence,
+		event.Delivery).Scan(fields(e)...)
 	if err != nil {
 		return nil, err
 	}
@@ -50,8 +57,12 @@ func (r *repo) Update(ctx context.Context, event *Event) (*Event, error) {
 				link = $5,
 				extra_data = $6,
 				scheduled_at = $7,
+				segment = $8,
+				ab_test = $9,
+				recurrence = $10,
+				delivery = $11,
 				updated_at = now()
-			WHERE id = $8 RETURNING `+_cols,
+			WHERE id = $12 RETURNING `+_cols,
 		event.Status,
 		event.Title,
 		event.Body,
@@ -59,6 +70,10 @@ func (r *repo) Update(ctx context.Context, event *Event) (*Event, error) {
 		event.Link,
 		event.ExtraData,
 		event.ScheduledAt,
+		event.Segment,
+		event.ABTest,
+		event.Recurrence,
+		event.Delivery,
 		event.ID).Scan(fields(e)...)
 	if err != nil {
 		return nil, err
@@ -66,13 +81,43 @@ func (r *repo) Update(ctx context.Context, event *Event) (*Event, error) {
 	return e, nil
 }
 
-func (r *repo) UpdateStatus(ctx context.Context, id int, status string) error {
+func (r *repo) Transition(ctx context.Context, id int, from, to string) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	res, err := r.db.Exec(ctx, `UPDATE events SET status = $1, updated_at = now() WHERE id = $2 AND status = $3`, to, id, from)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return repomodel.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) SetReviewers(ctx context.Context, id, submittedBy, approvedBy int) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	_, err := r.db.Exec(ctx, `UPDATE events SET submitted_by = $1, approved_by = $2, updated_at = now() WHERE id = $3`, submittedBy, approvedBy, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *repo) Reschedule(ctx context.Context, id int, scheduledAt time.Time) error {
 	ctx = ctxman.Save(ctx, ctxman.Info{
 		DBName:    db.Notifications,
 		IsReplica: false,
 	})
 
-	_, err := r.db.Exec(ctx, `UPDATE events SET status = $1, updated_at = now() WHERE id = $2`, status, id)
+	_, err := r.db.Exec(ctx, `UPDATE events SET scheduled_at = $1, updated_at = now() WHERE id = $2`, scheduledAt, id)
 	if err != nil {
 		return err
 	}
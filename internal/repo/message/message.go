@@ -0,0 +1,75 @@
+package message
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+func (r *repo) Create(ctx context.Context, message *Message) (*Message, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	var m = new(Message)
+	err := r.db.QueryRow(ctx, `
+				INSERT INTO messages (id, request_id, user_id, phone, policy, attempts, status) 
+				VALUES ($1, $2, $3, $4, $5, $6, $7) 
+				ON CONFLICT (request_id) DO UPDATE SET request_id = EXCLUDED.request_id 
+				RETURNING `+_cols,
+		message.ID,
+		message.RequestID,
+		message.UserID,
+		message.Phone,
+		message.Policy,
+		message.Attempts,
+		message.Status).Scan(fields(m)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (r *repo) Update(ctx context.Context, message *Message) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	res, err := r.db.Exec(ctx, `
+				UPDATE messages SET 
+					attempts = $1,
+					status = $2,
+					channel = $3,
+					updated_at = now()
+				WHERE id = $4`,
+		message.Attempts,
+		message.Status,
+		message.Channel,
+		message.ID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return repomodel.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) GetByRequestID(ctx context.Context, requestID string) (*Message, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	var message = new(Message)
+	err := r.db.QueryRow(ctx, `SELECT `+_cols+` FROM messages WHERE request_id = $1`, requestID).Scan(fields(message)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repomodel.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return message, nil
+}
@@ -0,0 +1,61 @@
+package message
+
+import "time"
+
+// Message is the record of the message sent through the channels of its policy one by one until one of them succeeds
+type Message struct {
+	ID        int
+	RequestID string
+	UserID    int
+	Phone     string
+	Policy    []Step
+	Attempts  []Attempt
+	Status    string
+	// Channel is the channel the message is delivered by
+	Channel   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Step is the channel of the policy, the next channel is tried if the channel fails within Timeout seconds
+type Step struct {
+	Channel string `json:"channel"`
+	Timeout int    `json:"timeout,omitempty"`
+}
+
+// Attempt is the outcome of the channel of the policy
+type Attempt struct {
+	Channel    string    `json:"channel"`
+	Status     string    `json:"status"`
+	MessageID  string    `json:"messageID,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+const _cols = `
+			id,
+			request_id,
+			user_id,
+			phone,
+			policy,
+			attempts,
+			status,
+			channel,
+			created_at,
+			updated_at`
+
+func fields(m *Message) []any {
+	return []any{
+		&m.ID,
+		&m.RequestID,
+		&m.UserID,
+		&m.Phone,
+		&m.Policy,
+		&m.Attempts,
+		&m.Status,
+		&m.Channel,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	}
+}
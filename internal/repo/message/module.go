@@ -0,0 +1,42 @@
+package message
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/db"
+)
+
+var Module = fx.Provide(New)
+
+type Repo interface {
+	writer
+	reader
+}
+
+type writer interface {
+	// Create saves the message unless the message of the request is saved already, the saved message is returned
+	Create(context.Context, *Message) (*Message, error)
+	Update(context.Context, *Message) error
+}
+
+type reader interface {
+	GetByRequestID(ctx context.Context, requestID string) (*Message, error)
+}
+
+type Params struct {
+	fx.In
+
+	DB db.QueryExecutor
+}
+
+type repo struct {
+	db db.QueryExecutor
+}
+
+func New(p Params) Repo {
+	return &repo{
+		db: p.DB,
+	}
+}
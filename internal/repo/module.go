@@ -3,10 +3,15 @@ package repo
 import (
 	"go.uber.org/fx"
 
+	"notifications/internal/repo/analytics"
 	"notifications/internal/repo/apiclient"
+	"notifications/internal/repo/delivery"
 	"notifications/internal/repo/event"
+	"notifications/internal/repo/message"
+	"notifications/internal/repo/outbox"
 	"notifications/internal/repo/push"
 	"notifications/internal/repo/rom"
+	"notifications/internal/repo/template"
 	"notifications/internal/repo/user"
 )
 
@@ -16,4 +21,9 @@ var Module = fx.Options(
 	apiclient.Module,
 	event.Module,
 	rom.Module,
+	outbox.Module,
+	delivery.Module,
+	template.Module,
+	analytics.Module,
+	message.Module,
 )
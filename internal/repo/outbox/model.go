@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/bytedance/sonic"
+
+	"notifications/internal/repo/rom"
+)
+
+// Message kinds
+const (
+	KindRomInbox = "rom.inbox"
+	KindRomEvent = "rom.event"
+	KindBroker   = "broker"
+)
+
+type Message struct {
+	ID            int
+	Kind          string
+	Stream        string
+	Subject       string
+	LastError     string
+	Payload       []byte
+	Attempts      int
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+}
+
+// RomEvent is the payload of KindRomEvent message, CountryIDs are the countries of the users the rows are partitioned by
+type RomEvent struct {
+	Event      rom.Event `json:"event"`
+	UserIDs    []int     `json:"userIDs"`
+	CountryIDs []int8    `json:"countryIDs"`
+	EventID    int       `json:"eventID"`
+}
+
+func NewRomInbox(inbox *rom.Inbox) (*Message, error) {
+	payload, err := sonic.Marshal(inbox)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{Kind: KindRomInbox, Payload: payload}, nil
+}
+
+func NewRomEvent(eventID int, userIDs []int, countryIDs []int8, event *rom.Event) (*Message, error) {
+	payload, err := sonic.Marshal(RomEvent{
+		Event:      *event,
+		UserIDs:    userIDs,
+		CountryIDs: countryIDs,
+		EventID:    eventID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{Kind: KindRomEvent, Payload: payload}, nil
+}
+
+func NewBroker(stream, subject string, msg any) (*Message, error) {
+	payload, err := sonic.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{Kind: KindBroker, Stream: stream, Subject: subject, Payload: payload}, nil
+}
+
+// Publish writes the broker message with the repo of the transaction, it is published by the relay after commit
+func Publish(ctx context.Context, repo Repo, stream, subject string, msg any) error {
+	message, err := NewBroker(stream, subject, msg)
+	if err != nil {
+		return err
+	}
+	return repo.Insert(ctx, message)
+}
+
+const _cols = `
+			id,
+			kind,
+			stream,
+			subject,
+			last_error,
+			payload,
+			attempts,
+			created_at,
+			next_attempt_at`
+
+func fields(m *Message) []any {
+	return []any{
+		&m.ID,
+		&m.Kind,
+		&m.Stream,
+		&m.Subject,
+		&m.LastError,
+		&m.Payload,
+		&m.Attempts,
+		&m.CreatedAt,
+		&m.NextAttemptAt,
+	}
+}
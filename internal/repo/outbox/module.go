@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/db"
+)
+
+var Module = fx.Provide(New)
+
+// Repo is used to write outbox messages in the same transaction with the business data,
+// then the relay delivers them to the rom db and the broker
+type Repo interface {
+	Insert(context.Context, *Message) error
+	GetPending(ctx context.Context, limit int) ([]*Message, error)
+	Delete(ctx context.Context, id int) error
+	Reschedule(ctx context.Context, id int, lastErr string, nextAttemptAt time.Time) error
+}
+
+type Params struct {
+	fx.In
+
+	DB db.QueryExecutor
+}
+
+type repo struct {
+	db db.QueryExecutor
+}
+
+func New(p Params) Repo {
+	return &repo{
+		db: p.DB,
+	}
+}
@@ -0,0 +1,94 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+// Insert saves the message to be relayed right away, or not before NextAttemptAt if it's set
+func (r *repo) Insert(ctx context.Context, message *Message) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	_, err := r.db.Exec(ctx, `
+				INSERT INTO outbox (kind, stream, subject, payload, next_attempt_at) 
+				VALUES ($1, $2, $3, $4, GREATEST(now(), $5))`,
+		message.Kind,
+		message.Stream,
+		message.Subject,
+		message.Payload,
+		message.NextAttemptAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetPending must be called in a transaction, the selected rows stay locked until the end of it,
+// so the relays running on other pods skip them
+func (r *repo) GetPending(ctx context.Context, limit int) ([]*Message, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	rows, err := r.db.Query(ctx, `
+				SELECT `+_cols+` FROM outbox 
+				WHERE next_attempt_at <= now() 
+				ORDER BY id 
+				LIMIT $1 
+				FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages = make([]*Message, 0, limit)
+
+	for rows.Next() {
+		var message = new(Message)
+		err = rows.Scan(fields(message)...)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, repomodel.ErrNotFound
+	}
+
+	return messages, nil
+}
+
+func (r *repo) Delete(ctx context.Context, id int) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	_, err := r.db.Exec(ctx, `DELETE FROM outbox WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *repo) Reschedule(ctx context.Context, id int, lastErr string, nextAttemptAt time.Time) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	_, err := r.db.Exec(ctx, `
+				UPDATE outbox SET 
+					attempts = attempts + 1, 
+					last_error = $1, 
+					next_attempt_at = $2 
+				WHERE id = $3`, lastErr, nextAttemptAt, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
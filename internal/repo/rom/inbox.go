@@ -3,14 +3,18 @@ package rom
 import (
 	"context"
 
+	"github.com/jackc/pgx/v5"
+
 	"notifications/internal/db"
 	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
 )
 
 func (r *repo) InsertInbox(ctx context.Context, inbox *Inbox) error {
 	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Rom, IsReplica: false})
 	_, err := r.db.Exec(ctx, `INSERT INTO notification_inbox (id, user_id, country_id, type, title, body, extra_data) 
-									VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+									VALUES ($1, $2, $3, $4, $5, $6, $7)
+									ON CONFLICT DO NOTHING`,
 		inbox.ID, inbox.UserID, inbox.CountryID, inbox.Type, inbox.Title, inbox.Body, inbox.ExtraData)
 	if err != nil {
 		return err
@@ -18,3 +22,130 @@ func (r *repo) InsertInbox(ctx context.Context, inbox *Inbox) error {
 
 	return nil
 }
+
+// GetInbox merges stateful pushes and event fan-outs of the user, ordered from the newest to the oldest
+func (r *repo) GetInbox(ctx context.Context, countryID int8, userID int, cursor InboxCursor, limit int) ([]InboxItem, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Rom, IsReplica: false})
+
+	var (
+		condition = "TRUE"
+		args      = []any{userID, limit, countryID}
+	)
+
+	if cursor.ID != 0 {
+		condition = "(i.created_at, i.id) < ($4, $5)"
+		args = append(args, cursor.CreatedAt, cursor.ID)
+	}
+
+	rows, err := r.db.Query(ctx, `
+				SELECT i.source, i.type, i.id, i.title, i.body, i.image, i.extra_data, i.created_at, i.is_read
+				FROM (
+					SELECT 'push' AS source, type, id, title, body, '{}'::jsonb AS image, extra_data, created_at, is_read
+					FROM notification_inbox
+					WHERE country_id = $3 AND user_id = $1
+					UNION ALL
+					SELECT 'event' AS source, 'event' AS type, e.id, e.title, e.body, COALESCE(e.image, '{}'::jsonb), e.extra_data, r.created_at, r.is_read
+					FROM notification_events_user_relation r
+					JOIN notification_events e ON e.id = r.event_id
+					WHERE r.country_id = $3 AND r.user_id = $1
+				) i
+				WHERE `+condition+`
+				ORDER BY i.created_at DESC, i.id DESC
+				LIMIT $2`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items = make([]InboxItem, 0, limit)
+
+	for rows.Next() {
+		var item InboxItem
+		err = rows.Scan(
+			&item.Source,
+			&item.Type,
+			&item.ID,
+			&item.Title,
+			&item.Body,
+			&item.Image,
+			&item.ExtraData,
+			&item.CreatedAt,
+			&item.IsRead)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *repo) CountUnread(ctx context.Context, countryID int8, userID int) (int, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Rom, IsReplica: false})
+
+	var count int
+	err := r.db.QueryRow(ctx, `
+				SELECT
+					(SELECT count(*) FROM notification_inbox WHERE country_id = $1 AND user_id = $2 AND NOT is_read) +
+					(SELECT count(*) FROM notification_events_user_relation WHERE country_id = $1 AND user_id = $2 AND NOT is_read)`,
+		countryID, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *repo) MarkRead(ctx context.Context, countryID int8, userID int, source string, id int) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Rom, IsReplica: false})
+
+	var query = `UPDATE notification_inbox SET is_read = TRUE WHERE country_id = $1 AND user_id = $2 AND id = $3`
+	if source == SourceEvent {
+		query = `UPDATE notification_events_user_relation SET is_read = TRUE WHERE country_id = $1 AND user_id = $2 AND event_id = $3`
+	}
+
+	res, err := r.db.Exec(ctx, query, countryID, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return repomodel.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) MarkAllRead(ctx context.Context, countryID int8, userID int) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Rom, IsReplica: false})
+
+	var batch = new(pgx.Batch)
+	batch.Queue(`UPDATE notification_inbox SET is_read = TRUE WHERE country_id = $1 AND user_id = $2 AND NOT is_read`, countryID, userID)
+	batch.Queue(`UPDATE notification_events_user_relation SET is_read = TRUE WHERE country_id = $1 AND user_id = $2 AND NOT is_read`, countryID, userID)
+
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+func (r *repo) DeleteInboxItem(ctx context.Context, countryID int8, userID int, source string, id int) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Rom, IsReplica: false})
+
+	var query = `DELETE FROM notification_inbox WHERE country_id = $1 AND user_id = $2 AND id = $3`
+	if source == SourceEvent {
+		query = `DELETE FROM notification_events_user_relation WHERE country_id = $1 AND user_id = $2 AND event_id = $3`
+	}
+
+	res, err := r.db.Exec(ctx, query, countryID, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return repomodel.ErrNotFound
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package rom
 
 import (
+	"time"
+
 	"notifications/internal/lib/language"
 )
 
@@ -21,3 +23,27 @@ type Inbox struct {
 	Body      language.Language
 	ExtraData map[string]string
 }
+
+// Inbox item sources
+const (
+	SourcePush  = "push"
+	SourceEvent = "event"
+)
+
+type InboxItem struct {
+	Source    string
+	Type      string
+	ID        int
+	Title     language.Language
+	Body      language.Language
+	Image     language.Language
+	ExtraData map[string]string
+	CreatedAt time.Time
+	IsRead    bool
+}
+
+// InboxCursor points to the last item of the previous page, zero value means the first page
+type InboxCursor struct {
+	CreatedAt time.Time
+	ID        int
+}
@@ -12,7 +12,22 @@ var Module = fx.Provide(New)
 
 type Repo interface {
 	InsertInbox(context.Context, *Inbox) error
-	BatchInsert(ctx context.Context, eventID int, userIDs []int, event *Event) error
+	// BatchInsert inserts the rows of the users of the event, countryIDs are the countries of the users by index
+	BatchInsert(ctx context.Context, eventID int, userIDs []int, countryIDs []int8, event *Event) error
+	inboxReader
+	inboxWriter
+}
+
+// the inbox rows are partitioned by the country of the user, so the queries of the user are limited to the country
+type inboxReader interface {
+	GetInbox(ctx context.Context, countryID int8, userID int, cursor InboxCursor, limit int) ([]InboxItem, error)
+	CountUnread(ctx context.Context, countryID int8, userID int) (int, error)
+}
+
+type inboxWriter interface {
+	MarkRead(ctx context.Context, countryID int8, userID int, source string, id int) error
+	MarkAllRead(ctx context.Context, countryID int8, userID int) error
+	DeleteInboxItem(ctx context.Context, countryID int8, userID int, source string, id int) error
 }
 
 type Params struct {
@@ -13,9 +13,10 @@ import (
 )
 
 // BatchInsert in terms of performance is better than  Insert query, because it uses CopyFrom method.
-// If there is a unique violation, it deletes all the rows with the same event_id and inserts them again, because CopyFrom does not support ON CONFLICT DO NOTHING.
+// If there is a unique violation, it deletes the rows of the users of the event and inserts them again, because CopyFrom does not support ON CONFLICT DO NOTHING,
+// so the batches of the users of the same event are inserted independently.
 // https://stackoverflow.com/questions/46715354/how-does-copy-work-and-why-is-it-so-much-faster-than-insert
-func (r *repo) BatchInsert(ctx context.Context, eventID int, userIDs []int, event *Event) error {
+func (r *repo) BatchInsert(ctx context.Context, eventID int, userIDs []int, countryIDs []int8, event *Event) error {
 	ctx = ctxman.Save(ctx, ctxman.Info{
 		DBName:    db.Rom,
 		IsReplica: false,
@@ -35,23 +36,35 @@ func (r *repo) BatchInsert(ctx context.Context, eventID int, userIDs []int, even
 	}()
 
 	const (
-		_tableName  = "notification_events_user_relation"
-		_colEventID = "event_id"
-		_colUserID  = "user_id"
+		_tableName    = "notification_events_user_relation"
+		_colEventID   = "event_id"
+		_colUserID    = "user_id"
+		_colCountryID = "country_id"
 	)
 
-	_, err = tx.CopyFrom(ctx, pgx.Identifier{_tableName}, []string{_colEventID, _colUserID}, pgx.CopyFromSlice(len(userIDs), func(i int) ([]any, error) {
-		return []any{eventID, userIDs[i]}, nil
+	var (
+		columns = []string{_colEventID, _colUserID, _colCountryID}
+		// the messages enqueued before the rows were partitioned carry no countries
+		countryID = func(i int) int8 {
+			if i < len(countryIDs) {
+				return countryIDs[i]
+			}
+			return 0
+		}
+	)
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{_tableName}, columns, pgx.CopyFromSlice(len(userIDs), func(i int) ([]any, error) {
+		return []any{eventID, userIDs[i], countryID(i)}, nil
 	}))
 	if err != nil {
 		var pgErr = new(pgconn.PgError)
 		if errors.As(err, &pgErr) && pgerrcode.UniqueViolation == pgErr.Code {
-			_, err = tx.Exec(ctx, `DELETE FROM notification_events_user_relation WHERE event_id = $1`, eventID)
+			_, err = tx.Exec(ctx, `DELETE FROM notification_events_user_relation WHERE event_id = $1 AND user_id = ANY($2)`, eventID, userIDs)
 			if err != nil {
 				return err
 			}
-			_, err = tx.CopyFrom(ctx, pgx.Identifier{_tableName}, []string{_colEventID, _colUserID}, pgx.CopyFromSlice(len(userIDs), func(i int) ([]any, error) {
-				return []any{eventID, userIDs[i]}, nil
+			_, err = tx.CopyFrom(ctx, pgx.Identifier{_tableName}, columns, pgx.CopyFromSlice(len(userIDs), func(i int) ([]any, error) {
+				return []any{eventID, userIDs[i], countryID(i)}, nil
 			}))
 			if err != nil {
 				return err
@@ -0,0 +1,40 @@
+package template
+
+import (
+	"time"
+
+	"notifications/internal/lib/language"
+)
+
+type Template struct {
+	Key       string
+	Title     language.Language
+	Body      language.Language
+	Variables []Variable
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Variable struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+const _cols = `
+			key,
+			title,
+			body,
+			variables,
+			created_at,
+			updated_at`
+
+func fields(t *Template) []any {
+	return []any{
+		&t.Key,
+		&t.Title,
+		&t.Body,
+		&t.Variables,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	}
+}
@@ -0,0 +1,43 @@
+package template
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/db"
+)
+
+var Module = fx.Provide(New)
+
+type Repo interface {
+	writer
+	reader
+}
+
+type writer interface {
+	Create(context.Context, *Template) (*Template, error)
+	Update(context.Context, *Template) (*Template, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type reader interface {
+	GetByKey(ctx context.Context, key string) (*Template, error)
+	GetList(ctx context.Context, limit, offset uint) ([]*Template, error)
+}
+
+type Params struct {
+	fx.In
+
+	DB db.QueryExecutor
+}
+
+type repo struct {
+	db db.QueryExecutor
+}
+
+func New(p Params) Repo {
+	return &repo{
+		db: p.DB,
+	}
+}
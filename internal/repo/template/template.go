@@ -0,0 +1,120 @@
+package template
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+func (r *repo) Create(ctx context.Context, template *Template) (*Template, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	var t = new(Template)
+	err := r.db.QueryRow(ctx, `
+				INSERT INTO notification_templates (key, title, body, variables) 
+				VALUES ($1, $2, $3, $4) RETURNING `+_cols,
+		template.Key,
+		template.Title,
+		template.Body,
+		template.Variables).Scan(fields(t)...)
+	if err != nil {
+		var pgErr = new(pgconn.PgError)
+		if errors.As(err, &pgErr) && pgerrcode.UniqueViolation == pgErr.Code {
+			return nil, repomodel.ErrUniqueViolation
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *repo) Update(ctx context.Context, template *Template) (*Template, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	var t = new(Template)
+	err := r.db.QueryRow(ctx, `
+				UPDATE notification_templates SET 
+					title = $1,
+					body = $2,
+					variables = $3,
+					updated_at = now()
+				WHERE key = $4 RETURNING `+_cols,
+		template.Title,
+		template.Body,
+		template.Variables,
+		template.Key).Scan(fields(t)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repomodel.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *repo) Delete(ctx context.Context, key string) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	res, err := r.db.Exec(ctx, `DELETE FROM notification_templates WHERE key = $1`, key)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return repomodel.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) GetByKey(ctx context.Context, key string) (*Template, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	var t = new(Template)
+	err := r.db.QueryRow(ctx, `SELECT `+_cols+` FROM notification_templates WHERE key = $1`, key).Scan(fields(t)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repomodel.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *repo) GetList(ctx context.Context, limit, offset uint) ([]*Template, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	rows, err := r.db.Query(ctx, `SELECT `+_cols+` FROM notification_templates ORDER BY key LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*Template
+	for rows.Next() {
+		var t = new(Template)
+		if err = rows.Scan(fields(t)...); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, repomodel.ErrNotFound
+	}
+
+	return list, nil
+}
@@ -0,0 +1,138 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+// UpsertDevice registers the device of the user or marks it seen, the token moves to the user if it was registered
+// by another one. The empty attributes of the device don't override the saved ones, the provider is FCM by default.
+// The keys of the browser subscription are renewed with it
+func (r *repo) UpsertDevice(ctx context.Context, device Device) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO user_devices (user_id,
+		                          token,
+		                          platform,
+		                          app_version,
+		                          locale,
+		                          provider,
+		                          p256dh,
+		                          auth,
+		                          active,
+		                          last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, ''), 'fcm'), NULLIF($7, ''), NULLIF($8, ''), true, now())
+		ON CONFLICT (token) DO UPDATE
+			SET user_id      = $1,
+				platform     = COALESCE(NULLIF($3, ''), user_devices.platform),
+				app_version  = COALESCE(NULLIF($4, ''), user_devices.app_version),
+				locale       = COALESCE(NULLIF($5, ''), user_devices.locale),
+				provider     = COALESCE(NULLIF($6, ''), user_devices.provider),
+				p256dh       = COALESCE(NULLIF($7, ''), user_devices.p256dh),
+				auth         = COALESCE(NULLIF($8, ''), user_devices.auth),
+				active       = true,
+				last_seen_at = now(),
+				updated_at   = now()`,
+		device.UserID,
+		device.Token,
+		device.Platform,
+		device.AppVersion,
+		device.Locale,
+		device.Provider,
+		device.P256dh,
+		device.Auth)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeactivateDevice deactivates the device of the token, ErrNotFound if the user has no active device with the token
+func (r *repo) DeactivateDevice(ctx context.Context, userID int, token string) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	res, err := r.db.Exec(ctx, `
+		UPDATE user_devices SET active = false, updated_at = now()
+		WHERE user_id = $1 AND token = $2 AND active`, userID, token)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return repomodel.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetStaleDevices returns the next page of the active devices of the provider after the token which are not seen since
+// seenBefore and not validated since validatedBefore, ordered by the token
+func (r *repo) GetStaleDevices(ctx context.Context, provider string, seenBefore, validatedBefore time.Time, afterToken string, limit int) ([]Device, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	rows, err := r.db.Query(ctx, `
+		SELECT user_id, token, provider, last_seen_at
+		FROM user_devices
+		WHERE active
+		  AND provider = $1
+		  AND last_seen_at < $2
+		  AND (validated_at IS NULL OR validated_at < $3)
+		  AND token > $4
+		ORDER BY token
+		LIMIT $5`, provider, seenBefore, validatedBefore, afterToken, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices = make([]Device, 0, limit)
+
+	for rows.Next() {
+		var device = Device{Active: true}
+		err = rows.Scan(&device.UserID, &device.Token, &device.Provider, &device.LastSeenAt)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(devices) == 0 {
+		return nil, repomodel.ErrNotFound
+	}
+
+	return devices, nil
+}
+
+// MarkDevicesValidated saves the time the tokens of the devices are validated by the provider
+func (r *repo) MarkDevicesValidated(ctx context.Context, tokens []string) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	_, err := r.db.Exec(ctx, `UPDATE user_devices SET validated_at = now() WHERE token = ANY($1)`, tokens)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -13,12 +13,110 @@ type User struct {
 	UpdatedAt         time.Time
 	CountryID         int8
 	PushEnabled       bool
+	// Devices are the active devices of the user, the latest seen first
+	Devices []DeviceToken
+}
+
+// ActiveDevices returns the active devices of the user, the token of the user if it registered no device yet
+func (u User) ActiveDevices() []DeviceToken {
+	return activeDevices(u.Devices, u.Token)
+}
+
+// Device is the app installation of the user registered by its token, the device is deactivated once the token is rejected
+type Device struct {
+	UserID     int
+	Token      string
+	Provider   string
+	P256dh     string
+	Auth       string
+	Platform   string
+	AppVersion string
+	Locale     string
+	Active     bool
+	LastSeenAt time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Audience columns, the users imported from a file are referred by one of them
+const (
+	ColumnUserID            = "user_id"
+	ColumnPhone             = "phone"
+	ColumnPersonExternalRef = "person_external_ref"
+)
+
+// Segment is the rule-based audience of an event, the rules are joined with AND and the empty ones are not applied
+type Segment struct {
+	CountryID     int8       `json:"countryID,omitempty"`
+	Languages     []string   `json:"languages,omitempty"`
+	Statuses      []string   `json:"statuses,omitempty"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+	PushEnabled   *bool      `json:"pushEnabled,omitempty"`
 }
 
 type EventRelation struct {
 	UserID int
 	Topic  string
 	Lang   string
+	// Variant is the key of the A/B test variant of the user, empty if the event has no A/B test
+	Variant string
+}
+
+// Recipient is the user subscribed to the event with the devices the event is sent to directly,
+// the unreachable users have no token or opted out of marketing pushes
+type Recipient struct {
+	UserID    int
+	Token     string
+	Devices   []DeviceToken
+	Lang      string
+	Variant   string
+	CountryID int8
+	Reachable bool
+}
+
+// ActiveDevices returns the active devices of the recipient, the token of the user if it registered no device yet
+func (r Recipient) ActiveDevices() []DeviceToken {
+	return activeDevices(r.Devices, r.Token)
+}
+
+// Push providers the tokens of the devices are issued by, the iOS app which sends by APNs directly
+// registers its tokens of ProviderApns. The browser subscription of ProviderWebPush is registered by its endpoint
+// as the token with its keys. APNs and Web Push have no topics, so their devices get the events sent directly only
+const (
+	ProviderFcm     = "fcm"
+	ProviderHms     = "hms"
+	ProviderApns    = "apns"
+	ProviderWebPush = "webpush"
+)
+
+// DeviceToken is the token of the device with the push provider which issued it, the keys are of the browser subscription
+type DeviceToken struct {
+	Token    string `json:"token"`
+	Provider string `json:"provider"`
+	P256dh   string `json:"p256dh,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// HasTopics reports if the provider subscribes the devices to the topics
+func HasTopics(provider string) bool {
+	return provider == ProviderFcm || provider == ProviderHms
+}
+
+// ByProvider groups the tokens of the devices by their providers
+func ByProvider(devices []DeviceToken) map[string][]string {
+	var tokens = make(map[string][]string)
+	for _, device := range devices {
+		tokens[device.Provider] = append(tokens[device.Provider], device.Token)
+	}
+	return tokens
+}
+
+// RelationCount is the number of the users subscribed to the event in the language and the variant
+type RelationCount struct {
+	Lang    string
+	Variant string
+	Count   int
 }
 
 const _cols = `
@@ -31,7 +129,8 @@ const _cols = `
 		language,
 		country_id,
 		created_at, 
-		updated_at`
+		updated_at,
+		` + _devicesCol
 
 func fields(u *User) []any {
 	return []any{
@@ -45,5 +144,55 @@ func fields(u *User) []any {
 		&u.CountryID,
 		&u.CreatedAt,
 		&u.UpdatedAt,
+		&u.Devices,
+	}
+}
+
+// _devicesCol selects the active devices of the user of the row of users
+const _devicesCol = `COALESCE((
+		SELECT jsonb_agg(jsonb_strip_nulls(jsonb_build_object('token', d.token, 'provider', d.provider, 'p256dh', d.p256dh, 'auth', d.auth)) ORDER BY d.last_seen_at DESC)
+		FROM user_devices d WHERE d.user_id = users.user_id AND d.active), '[]')`
+
+// activeDevices returns the devices, the token of the user issued by FCM if there is no device
+func activeDevices(devices []DeviceToken, token string) []DeviceToken {
+	if len(devices) != 0 || token == "" {
+		return devices
 	}
+	return []DeviceToken{{Token: token, Provider: ProviderFcm}}
+}
+
+// Preference categories
+const (
+	CategoryOTP           = "otp"
+	CategoryTransactional = "transactional"
+	CategoryMarketing     = "marketing"
+)
+
+// Preference channels
+const (
+	ChannelPush  = "push"
+	ChannelSms   = "sms"
+	ChannelEmail = "email"
+)
+
+// Preference of the user for the category on the channel, there is no row if the user didn't change the default (enabled)
+type Preference struct {
+	Category  string
+	Channel   string
+	Enabled   bool
+	UpdatedAt time.Time
+}
+
+// Suppressible reports if the user can opt out of the category, e.g. otp is always delivered
+func Suppressible(category string) bool {
+	return category != CategoryOTP
+}
+
+// QuietHours overrides the quiet hours of the user's country, Start and End are local "15:04"
+// and are empty if the user turned quiet hours off
+type QuietHours struct {
+	Start     string
+	End       string
+	Enabled   bool
+	UpdatedAt time.Time
 }
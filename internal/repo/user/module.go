@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/fx"
 
@@ -13,6 +14,10 @@ var Module = fx.Provide(New)
 type Repo interface {
 	writer
 	reader
+	preferences
+	quietHours
+	segments
+	devices
 }
 
 type writer interface {
@@ -37,11 +42,40 @@ type reader interface {
 	GetActiveByPhone(ctx context.Context, phone string) (*User, error)
 	GetActiveByPersonExternalRef(ctx context.Context, personExternalRef string) (*User, error)
 
+	GetByColumn(ctx context.Context, column string, values []string) ([]*User, error)
 	GetTokensByUserIDs(ctx context.Context, userIDs []string) ([]User, error)
 	GetTopicsByUserID(ctx context.Context, userID int) ([]EventRelation, error)
 	GetUserIDsByEventID(ctx context.Context, eventID int) ([]int, error)
 	GetRelationsByEventID(ctx context.Context, eventID int) ([]EventRelation, error)
-	GetTokensWithLimit(ctx context.Context, lastID int) ([]User, error)
+	CountRelations(ctx context.Context, eventID int) ([]RelationCount, error)
+	// GetRecipients returns the next page of the users of the event after lastID, ErrNotFound after the last page
+	GetRecipients(ctx context.Context, eventID, lastID, limit int) ([]Recipient, error)
+	GetTokensWithLimit(ctx context.Context, lastID int, countryID int8) ([]User, error)
+}
+
+type preferences interface {
+	GetPreferences(ctx context.Context, userID int) ([]Preference, error)
+	UpsertPreferences(ctx context.Context, userID int, preferences []Preference) error
+	IsOptedOut(ctx context.Context, userID int, category, channel string) (bool, error)
+	GetOptedOutUserIDs(ctx context.Context, userIDs []int, category, channel string) ([]int, error)
+}
+
+type quietHours interface {
+	GetQuietHours(ctx context.Context, userID int) (*QuietHours, error)
+	UpsertQuietHours(ctx context.Context, userID int, quietHours QuietHours) error
+	DeleteQuietHours(ctx context.Context, userID int) error
+}
+
+type segments interface {
+	GetBySegment(ctx context.Context, segment Segment, lastID, limit int) ([]User, error)
+	CountBySegment(ctx context.Context, segment Segment) (total, reachable int, err error)
+}
+
+type devices interface {
+	UpsertDevice(ctx context.Context, device Device) error
+	DeactivateDevice(ctx context.Context, userID int, token string) error
+	GetStaleDevices(ctx context.Context, provider string, seenBefore, validatedBefore time.Time, afterToken string, limit int) ([]Device, error)
+	MarkDevicesValidated(ctx context.Context, tokens []string) error
 }
 
 type Params struct {
@@ -0,0 +1,104 @@
+package user
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+func (r *repo) GetPreferences(ctx context.Context, userID int) ([]Preference, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	rows, err := r.db.Query(ctx, `
+			SELECT category, channel, enabled, updated_at 
+			FROM user_notification_preferences 
+			WHERE user_id = $1 ORDER BY category, channel`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var preferences = make([]Preference, 0)
+	for rows.Next() {
+		var p Preference
+		err = rows.Scan(&p.Category, &p.Channel, &p.Enabled, &p.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		preferences = append(preferences, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(preferences) == 0 {
+		return nil, repomodel.ErrNotFound
+	}
+
+	return preferences, nil
+}
+
+func (r *repo) UpsertPreferences(ctx context.Context, userID int, preferences []Preference) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	var batch = new(pgx.Batch)
+	for _, p := range preferences {
+		batch.Queue(`
+			INSERT INTO user_notification_preferences (user_id, category, channel, enabled) 
+			VALUES ($1, $2, $3, $4) 
+			ON CONFLICT (user_id, category, channel) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = now()`,
+			userID, p.Category, p.Channel, p.Enabled)
+	}
+
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+func (r *repo) IsOptedOut(ctx context.Context, userID int, category, channel string) (bool, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	var optedOut bool
+	err := r.db.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM user_notification_preferences 
+				WHERE user_id = $1 AND category = $2 AND channel = $3 AND NOT enabled)`,
+		userID, category, channel).Scan(&optedOut)
+	if err != nil {
+		return false, err
+	}
+
+	return optedOut, nil
+}
+
+func (r *repo) GetOptedOutUserIDs(ctx context.Context, userIDs []int, category, channel string) ([]int, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	rows, err := r.db.Query(ctx, `
+			SELECT user_id FROM user_notification_preferences 
+			WHERE user_id = ANY($1) AND category = $2 AND channel = $3 AND NOT enabled`,
+		userIDs, category, channel)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids = make([]int, 0)
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,61 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+)
+
+func (r *repo) GetQuietHours(ctx context.Context, userID int) (*QuietHours, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	var quietHours = new(QuietHours)
+	err := r.db.QueryRow(ctx, `
+			SELECT start_time, end_time, enabled, updated_at 
+			FROM user_quiet_hours 
+			WHERE user_id = $1`, userID).
+		Scan(&quietHours.Start, &quietHours.End, &quietHours.Enabled, &quietHours.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repomodel.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return quietHours, nil
+}
+
+func (r *repo) UpsertQuietHours(ctx context.Context, userID int, quietHours QuietHours) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	_, err := r.db.Exec(ctx, `
+			INSERT INTO user_quiet_hours (user_id, start_time, end_time, enabled) 
+			VALUES ($1, $2, $3, $4) 
+			ON CONFLICT (user_id) DO UPDATE SET 
+				start_time = EXCLUDED.start_time, 
+				end_time = EXCLUDED.end_time, 
+				enabled = EXCLUDED.enabled, 
+				updated_at = now()`,
+		userID, quietHours.Start, quietHours.End, quietHours.Enabled)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *repo) DeleteQuietHours(ctx context.Context, userID int) error {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	_, err := r.db.Exec(ctx, `DELETE FROM user_quiet_hours WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
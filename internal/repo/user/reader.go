@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
 
 	"github.com/jackc/pgx/v5"
@@ -32,13 +33,21 @@ func (r *repo) GetByPhones(ctx context.Context, phones []string) ([]*User, error
 	return r.selectUsers(ctx, "phone = ANY($1) AND status != 'deleted'", phones)
 }
 
+// GetByColumn returns the users referred by the values of the audience column, the deleted users as well
+func (r *repo) GetByColumn(ctx context.Context, column string, values []string) ([]*User, error) {
+	if !slices.Contains([]string{ColumnUserID, ColumnPhone, ColumnPersonExternalRef}, column) {
+		return nil, errors.New("unknown audience column: " + column)
+	}
+	return r.selectUsers(ctx, column+" = ANY($1)", values)
+}
+
 func (r *repo) GetTokensByUserIDs(ctx context.Context, userIDs []string) ([]User, error) {
 	ctx = ctxman.Save(ctx, ctxman.Info{
 		DBName:    db.Notifications,
 		IsReplica: false,
 	})
 
-	var query = `SELECT user_id, token, language FROM users WHERE user_id = ANY($1) AND status != 'deleted'`
+	var query = `SELECT user_id, token, language, country_id, ` + _devicesCol + ` FROM users WHERE user_id = ANY($1) AND status != 'deleted'`
 
 	rows, err := r.db.Query(ctx, query, userIDs)
 	if err != nil {
@@ -50,7 +59,7 @@ func (r *repo) GetTokensByUserIDs(ctx context.Context, userIDs []string) ([]User
 
 	for rows.Next() {
 		var user User
-		err = rows.Scan(&user.UserID, &user.Token, &user.Language)
+		err = rows.Scan(&user.UserID, &user.Token, &user.Language, &user.CountryID, &user.Devices)
 		if err != nil {
 			return nil, err
 		}
@@ -64,15 +73,19 @@ func (r *repo) GetTokensByUserIDs(ctx context.Context, userIDs []string) ([]User
 	return users, nil
 }
 
-func (r *repo) GetTokensWithLimit(ctx context.Context, lastID int) ([]User, error) {
+// GetTokensWithLimit returns the next page of the users after lastID, of all the countries if countryID is 0
+func (r *repo) GetTokensWithLimit(ctx context.Context, lastID int, countryID int8) ([]User, error) {
 	ctx = ctxman.Save(ctx, ctxman.Info{
 		DBName:    db.Notifications,
 		IsReplica: false,
 	})
 
-	var query = `SELECT user_id, token, language FROM users WHERE user_id > $1 AND status != 'deleted' ORDER BY user_id LIMIT 1000`
+	var query = `
+			SELECT user_id, token, language, country_id, ` + _devicesCol + ` FROM users
+			WHERE user_id > $1 AND status != 'deleted' AND ($2 = 0 OR country_id = $2)
+			ORDER BY user_id LIMIT 1000`
 
-	rows, err := r.db.Query(ctx, query, lastID)
+	rows, err := r.db.Query(ctx, query, lastID, countryID)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +95,7 @@ func (r *repo) GetTokensWithLimit(ctx context.Context, lastID int) ([]User, erro
 
 	for rows.Next() {
 		var user User
-		err = rows.Scan(&user.UserID, &user.Token, &user.Language)
+		err = rows.Scan(&user.UserID, &user.Token, &user.Language, &user.CountryID, &user.Devices)
 		if err != nil {
 			return nil, err
 		}
@@ -132,7 +145,7 @@ func (r *repo) GetRelationsByEventID(ctx context.Context, eventID int) ([]EventR
 		IsReplica: false,
 	})
 
-	rows, err := r.db.Query(ctx, "SELECT user_id, language FROM user_event_relations WHERE event_id = $1 ", eventID)
+	rows, err := r.db.Query(ctx, "SELECT user_id, language, variant FROM user_event_relations WHERE event_id = $1 ", eventID)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +155,7 @@ func (r *repo) GetRelationsByEventID(ctx context.Context, eventID int) ([]EventR
 
 	for rows.Next() {
 		var rel EventRelation
-		err = rows.Scan(&rel.UserID, &rel.Lang)
+		err = rows.Scan(&rel.UserID, &rel.Lang, &rel.Variant)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,111 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"notifications/internal/db"
+	"notifications/internal/lib/ctxman"
+	"notifications/internal/repo/repomodel"
+	"notifications/pkg/util/strset"
+)
+
+// GetBySegment returns the next page of the users of the segment after lastID, ErrNotFound after the last page
+func (r *repo) GetBySegment(ctx context.Context, segment Segment, lastID, limit int) ([]User, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	conditions, args := segment.conditions([]any{lastID})
+	args = append(args, limit)
+
+	var builder strings.Builder
+	builder.WriteString("SELECT user_id, token, language, country_id, " + _devicesCol + " FROM users WHERE user_id > $1 AND ")
+	builder.WriteString(conditions)
+	builder.WriteString(" ORDER BY user_id LIMIT $")
+	builder.WriteString(strset.IntToStr(len(args)))
+
+	rows, err := r.db.Query(ctx, builder.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users = make([]User, 0, limit)
+
+	for rows.Next() {
+		var user User
+		err = rows.Scan(&user.UserID, &user.Token, &user.Language, &user.CountryID, &user.Devices)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, repomodel.ErrNotFound
+	}
+
+	return users, nil
+}
+
+// CountBySegment returns the number of the users of the segment and of the reachable ones among them,
+// who have an active device or the token and didn't opt out of marketing pushes
+func (r *repo) CountBySegment(ctx context.Context, segment Segment) (total, reachable int, err error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{DBName: db.Notifications, IsReplica: false})
+
+	conditions, args := segment.conditions([]any{CategoryMarketing, ChannelPush})
+
+	var builder strings.Builder
+	builder.WriteString(`
+			SELECT count(*), count(*) FILTER (
+				WHERE (token != '' OR EXISTS (SELECT 1 FROM user_devices d WHERE d.user_id = users.user_id AND d.active)) AND NOT EXISTS (
+					SELECT 1 FROM user_notification_preferences p 
+					WHERE p.user_id = users.user_id AND p.category = $1 AND p.channel = $2 AND NOT p.enabled))
+			FROM users WHERE `)
+	builder.WriteString(conditions)
+
+	err = r.db.QueryRow(ctx, builder.String(), args...).Scan(&total, &reachable)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return total, reachable, nil
+}
+
+// conditions appends the values of the rules to args and returns the conditions referring to them,
+// the deleted users never match
+func (s Segment) conditions(args []any) (string, []any) {
+	var conditions strings.Builder
+	conditions.WriteString("status != 'deleted'")
+
+	var add = func(condition string, value any) {
+		args = append(args, value)
+		conditions.WriteString(" AND ")
+		conditions.WriteString(fmt.Sprintf(condition, "$"+strset.IntToStr(len(args))))
+	}
+
+	if s.CountryID != 0 {
+		add("country_id = %s", s.CountryID)
+	}
+	if len(s.Languages) > 0 {
+		add("language = ANY(%s)", s.Languages)
+	}
+	if len(s.Statuses) > 0 {
+		add("status = ANY(%s)", s.Statuses)
+	}
+	if s.CreatedAfter != nil {
+		add("created_at > %s", *s.CreatedAfter)
+	}
+	if s.CreatedBefore != nil {
+		add("created_at < %s", *s.CreatedBefore)
+	}
+	if s.PushEnabled != nil {
+		add("push_enabled = %s", *s.PushEnabled)
+	}
+
+	return conditions.String(), args
+}
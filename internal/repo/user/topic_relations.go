@@ -20,7 +20,9 @@ func (r *repo) GetTopicsByUserID(ctx context.Context, userID int) ([]EventRelati
 		IsReplica: false,
 	})
 
-	rows, err := r.db.Query(ctx, `SELECT e.topic, uer.language FROM user_event_relations uer LEFT JOIN events e ON uer.event_id = e.id WHERE uer.user_id = $1`, userID)
+	rows, err := r.db.Query(ctx, `
+			SELECT CASE WHEN uer.variant = '' THEN e.topic ELSE e.topic || '_' || uer.variant END, uer.language 
+			FROM user_event_relations uer JOIN events e ON uer.event_id = e.id WHERE uer.user_id = $1 AND e.delivery != 'direct'`, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -139,14 +141,15 @@ func (r *repo) batchInsertWithRetries(ctx context.Context, eventID int, relation
 		_eventIDCol  = "event_id"
 		_userIDCol   = "user_id"
 		_languageCol = "language"
+		_variantCol  = "variant"
 	)
 
 	rowsCount, err := r.db.CopyFrom(
 		ctx,
 		pgx.Identifier{_tableName},
-		[]string{_eventIDCol, _userIDCol, _languageCol},
+		[]string{_eventIDCol, _userIDCol, _languageCol, _variantCol},
 		pgx.CopyFromSlice(len(relations), func(i int) ([]any, error) {
-			return []any{eventID, relations[i].UserID, relations[i].Lang}, nil
+			return []any{eventID, relations[i].UserID, relations[i].Lang, relations[i].Variant}, nil
 		}))
 	if err != nil {
 		var pgErr = new(pgconn.PgError)
@@ -166,3 +169,80 @@ func (r *repo) batchInsertWithRetries(ctx context.Context, eventID int, relation
 
 	return rowsCount, nil
 }
+
+// CountRelations returns the number of the users subscribed to the event by language and variant
+func (r *repo) CountRelations(ctx context.Context, eventID int) ([]RelationCount, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	rows, err := r.db.Query(ctx, `SELECT language, variant, count(*) FROM user_event_relations WHERE event_id = $1 GROUP BY language, variant`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts = make([]RelationCount, 0)
+
+	for rows.Next() {
+		var count RelationCount
+		err = rows.Scan(&count.Lang, &count.Variant, &count.Count)
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *repo) GetRecipients(ctx context.Context, eventID, lastID, limit int) ([]Recipient, error) {
+	ctx = ctxman.Save(ctx, ctxman.Info{
+		DBName:    db.Notifications,
+		IsReplica: false,
+	})
+
+	rows, err := r.db.Query(ctx, `
+			SELECT uer.user_id, COALESCE(u.token, ''), 
+				COALESCE((
+					SELECT jsonb_agg(jsonb_strip_nulls(jsonb_build_object('token', d.token, 'provider', d.provider, 'p256dh', d.p256dh, 'auth', d.auth)) ORDER BY d.last_seen_at DESC)
+					FROM user_devices d WHERE d.user_id = uer.user_id AND d.active), '[]'),
+				uer.language, uer.variant, COALESCE(u.country_id, 0), (COALESCE(u.token, '') != '' OR EXISTS (
+				SELECT 1 FROM user_devices d WHERE d.user_id = uer.user_id AND d.active)) AND NOT EXISTS (
+				SELECT 1 FROM user_notification_preferences p 
+				WHERE p.user_id = uer.user_id AND p.category = $3 AND p.channel = $4 AND NOT p.enabled) 
+			FROM user_event_relations uer LEFT JOIN users u ON uer.user_id = u.user_id 
+			WHERE uer.event_id = $1 AND uer.user_id > $2 
+			ORDER BY uer.user_id LIMIT $5`,
+		eventID, lastID, CategoryMarketing, ChannelPush, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients = make([]Recipient, 0, limit)
+
+	for rows.Next() {
+		var recipient Recipient
+		err = rows.Scan(&recipient.UserID, &recipient.Token, &recipient.Devices, &recipient.Lang, &recipient.Variant, &recipient.CountryID, &recipient.Reachable)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(recipients) == 0 {
+		return nil, repomodel.ErrNotFound
+	}
+
+	return recipients, nil
+}
@@ -2,13 +2,32 @@ package admin
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"slices"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
 
 	"notifications/internal/api/resp"
 	"notifications/internal/gateway/gatewaymodel"
 )
 
 func (s *service) Authorize(ctx context.Context, token string) (Admin, error) {
+	var (
+		cacheKey = _tokenCacheKey + hashToken(token)
+		cached   Admin
+	)
+
+	err := s.cache.Get(ctx, cacheKey, &cached)
+	if err == nil {
+		return cached, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		s.logger.Warning("err occurred during getting admin token from cache", zap.Error(err))
+	}
+
 	admin, err := s.gateway.Authorize(ctx, token)
 	if err != nil {
 		switch {
@@ -21,10 +40,33 @@ func (s *service) Authorize(ctx context.Context, token string) (Admin, error) {
 		}
 	}
 
-	return Admin{
-		ID:        admin.ID,
-		Username:  admin.Username,
-		FullName:  admin.FullName,
-		CountryID: admin.CountryID,
-	}, nil
+	var validated = Admin{
+		ID:          admin.ID,
+		Username:    admin.Username,
+		FullName:    admin.FullName,
+		CountryID:   admin.CountryID,
+		Permissions: admin.Permissions,
+	}
+
+	err = s.cache.SetObj(ctx, cacheKey, validated, _tokenCacheTTL)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during caching admin token", zap.Error(err), zap.Int("adminID", admin.ID))
+	}
+
+	return validated, nil
+}
+
+func (s *service) CheckPermission(a Admin, permission string) error {
+	if !slices.Contains(a.Permissions, permission) {
+		s.logger.Warning("admin permission denied", zap.Int("adminID", a.ID), zap.String("permission", permission))
+		return resp.Wrap(resp.ErrForbidden, "admin doesn't have permission to perform this action")
+	}
+	return nil
+}
+
+// hashToken is used to avoid storing raw admin tokens as cache keys
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -5,11 +5,12 @@ import "time"
 const CtxKey = "admin"
 
 type Admin struct {
-	ID        int
-	CountryID int
-	IP        string
-	Username  string
-	FullName  string
+	ID          int
+	CountryID   int
+	IP          string
+	Username    string
+	FullName    string
+	Permissions []string
 }
 
 type Audit struct {
@@ -29,4 +30,33 @@ const (
 	UploadImageEvent         = "upload_image_event"
 	RemoveImageEvent         = "remove_image_event"
 	RunEvent                 = "run_event"
+	SendWinnerEvent          = "send_winner_event"
+	TransitionEvent          = "transition_notifications_event"
+	ReplayDlqEvent           = "replay_dlq_message"
+	CreateTemplateEvent      = "create_notifications_template"
+	UpdateTemplateEvent      = "update_notifications_template"
+	DeleteTemplateEvent      = "delete_notifications_template"
+	TokenHygieneEvent        = "token_hygiene"
+)
+
+// Permissions granted to admin users by the admin service
+const (
+	ReadEventPermission     = "notifications.event.read"
+	CreateEventPermission   = "notifications.event.create"
+	UpdateEventPermission   = "notifications.event.update"
+	DeleteEventPermission   = "notifications.event.delete"
+	RunEventPermission      = "notifications.event.run"
+	ApproveEventPermission  = "notifications.event.approve"
+	LoadUsersPermission     = "notifications.event.load_users"
+	UploadImagePermission   = "notifications.event.upload_image"
+	ReadDlqPermission       = "notifications.dlq.read"
+	ReplayDlqPermission     = "notifications.dlq.replay"
+	ReadTemplatePermission  = "notifications.template.read"
+	WriteTemplatePermission = "notifications.template.write"
+	ReadMessagePermission   = "notifications.message.read"
+)
+
+const (
+	_tokenCacheKey = ":admin-token:"
+	_tokenCacheTTL = time.Minute
 )
@@ -6,6 +6,7 @@ import (
 	"go.uber.org/fx"
 
 	"notifications/internal/gateway/admin"
+	"notifications/pkg/lib/cache"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/observer/sentry"
 )
@@ -14,6 +15,7 @@ var Module = fx.Provide(New)
 
 type Service interface {
 	Authorize(ctx context.Context, token string) (Admin, error)
+	CheckPermission(a Admin, permission string) error
 }
 
 type Params struct {
@@ -21,12 +23,14 @@ type Params struct {
 
 	Logger  logger.Logger
 	Sentry  sentry.Sentry
+	Cache   cache.Cache
 	Gateway admin.Gateway
 }
 
 type service struct {
 	logger  logger.Logger
 	sentry  sentry.Sentry
+	cache   cache.Cache
 	gateway admin.Gateway
 }
 
@@ -34,6 +38,7 @@ func New(p Params) Service {
 	return &service{
 		logger:  p.Logger,
 		sentry:  p.Sentry,
+		cache:   p.Cache,
 		gateway: p.Gateway,
 	}
 }
@@ -0,0 +1,125 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/lib/language"
+	"notifications/internal/repo/analytics"
+	"notifications/internal/repo/event"
+	"notifications/internal/repo/repomodel"
+)
+
+func (s *service) Track(ctx context.Context, request TrackRequest) error {
+	var interaction = analytics.Interaction{
+		Source:   analytics.SourceEvent,
+		SourceID: request.EventID,
+		UserID:   request.UserID,
+		Language: request.Language,
+		Variant:  request.Variant,
+	}
+
+	switch {
+	case request.EventID != 0 && request.PushID != 0, request.EventID == 0 && request.PushID == 0:
+		return resp.Wrap(resp.ErrBadRequest, "one of eventID and pushID is required")
+	case request.PushID != 0:
+		interaction.Source, interaction.SourceID = analytics.SourcePush, request.PushID
+	}
+
+	if request.UserID <= 0 || interaction.SourceID < 0 {
+		return resp.Wrap(resp.ErrBadRequest, "userID and the notification ID must be positive")
+	}
+
+	idx := slices.Index(_funnel, request.Action)
+	if idx == -1 {
+		return resp.Wrap(resp.ErrBadRequest, "action is not valid, allowed: delivered, opened, clicked")
+	}
+
+	if !slices.Contains(language.GetAll(), interaction.Language) {
+		interaction.Language = s.userLanguage(ctx, request.UserID)
+	}
+
+	err := s.analyticsRepo.Track(ctx, interaction, _funnel[:idx+1])
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during tracking", zap.Error(err), zap.Any("request", request))
+		return err
+	}
+
+	// the run of the event has its own funnel
+	if request.RunID > 0 && interaction.Source == analytics.SourceEvent {
+		interaction.Source, interaction.SourceID = analytics.SourceRun, request.RunID
+		err = s.analyticsRepo.Track(ctx, interaction, _funnel[:idx+1])
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during tracking run", zap.Error(err), zap.Any("request", request))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetEventStats(ctx context.Context, eventID, runID int) (*EventStats, error) {
+	_, err := s.eventRepo.GetByFilter(ctx, event.Filter{ID: uint(eventID), Limit: 1})
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting event", zap.Error(err), zap.Int("eventID", eventID))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "event not found")
+	}
+
+	var (
+		stats            = &EventStats{EventID: eventID, RunID: runID, Languages: make(map[string]*Funnel), Variants: make(map[string]*Funnel)}
+		source, sourceID = analytics.SourceEvent, eventID
+	)
+	if runID != 0 {
+		source, sourceID = analytics.SourceRun, runID
+	}
+
+	list, err := s.analyticsRepo.GetStats(ctx, source, sourceID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting stats", zap.Error(err), zap.Int("eventID", eventID))
+			return nil, err
+		}
+		return stats, nil
+	}
+
+	for _, item := range list {
+		funnelOf(stats.Languages, item.Language).add(item.Action, item.Count)
+		if item.Variant != "" {
+			funnelOf(stats.Variants, item.Variant).add(item.Action, item.Count)
+		}
+		stats.add(item.Action, item.Count)
+	}
+
+	stats.rates()
+	for _, funnel := range stats.Languages {
+		funnel.rates()
+	}
+	for _, funnel := range stats.Variants {
+		funnel.rates()
+	}
+
+	return stats, nil
+}
+
+// userLanguage returns the language of the user if the app didn't report it, empty if the user is not found
+func (s *service) userLanguage(ctx context.Context, userID int) string {
+	selectedUser, err := s.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.logger.Warning("failed to get user language", zap.Error(err), zap.Int("userID", userID))
+		}
+		return ""
+	}
+	return selectedUser.Language
+}
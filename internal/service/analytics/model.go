@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"math"
+
+	"notifications/internal/repo/analytics"
+)
+
+// _funnel is the order of the actions, every action implies the previous ones
+var _funnel = []string{analytics.ActionDelivered, analytics.ActionOpened, analytics.ActionClicked}
+
+// TrackRequest is the action of the user with the event or the push, one of EventID and PushID is set
+type TrackRequest struct {
+	EventID  int
+	PushID   int
+	UserID   int
+	Action   string
+	Language string
+	// Variant is the key of the A/B test variant and RunID is the run of the event reported in the data of the push
+	Variant string
+	RunID   int
+}
+
+type EventStats struct {
+	EventID int `json:"eventID"`
+	RunID   int `json:"runID,omitempty"`
+	Funnel
+	Languages map[string]*Funnel `json:"languages"`
+	// Variants is the breakdown by the A/B test variants, the holdout got the winner
+	Variants map[string]*Funnel `json:"variants,omitempty"`
+}
+
+// Funnel of the notification, the rates are the shares of the previous step
+type Funnel struct {
+	Targeted     int     `json:"targeted"`
+	Delivered    int     `json:"delivered"`
+	Opened       int     `json:"opened"`
+	Clicked      int     `json:"clicked"`
+	DeliveryRate float64 `json:"deliveryRate"`
+	OpenRate     float64 `json:"openRate"`
+	ClickRate    float64 `json:"clickRate"`
+}
+
+func (f *Funnel) add(action string, count int) {
+	switch action {
+	case analytics.ActionTargeted:
+		f.Targeted += count
+	case analytics.ActionDelivered:
+		f.Delivered += count
+	case analytics.ActionOpened:
+		f.Opened += count
+	case analytics.ActionClicked:
+		f.Clicked += count
+	}
+}
+
+// funnelOf returns the funnel of the key adding it to the breakdown if it's missing
+func funnelOf(breakdown map[string]*Funnel, key string) *Funnel {
+	funnel, ok := breakdown[key]
+	if !ok {
+		funnel = new(Funnel)
+		breakdown[key] = funnel
+	}
+	return funnel
+}
+
+func (f *Funnel) rates() {
+	f.DeliveryRate = rate(f.Delivered, f.Targeted)
+	f.OpenRate = rate(f.Opened, f.Delivered)
+	f.ClickRate = rate(f.Clicked, f.Opened)
+}
+
+func rate(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(count)/float64(total)*10000) / 10000
+}
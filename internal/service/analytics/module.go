@@ -0,0 +1,52 @@
+package analytics
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/repo/analytics"
+	"notifications/internal/repo/event"
+	"notifications/internal/repo/user"
+	"notifications/pkg/lib/observer/logger"
+	"notifications/pkg/lib/observer/sentry"
+)
+
+var Module = fx.Provide(New)
+
+type Service interface {
+	// Track saves the action reported by the app, the previous actions of the funnel are implied,
+	// e.g. the opened notification is delivered as well
+	Track(context.Context, TrackRequest) error
+	// GetEventStats returns the funnel of the event with the breakdown by language and A/B test variant,
+	// the funnel of the run if the run ID is given
+	GetEventStats(ctx context.Context, eventID, runID int) (*EventStats, error)
+}
+
+type Params struct {
+	fx.In
+
+	Logger        logger.Logger
+	Sentry        sentry.Sentry
+	AnalyticsRepo analytics.Repo
+	EventRepo     event.Repo
+	UserRepo      user.Repo
+}
+
+type service struct {
+	logger        logger.Logger
+	sentry        sentry.Sentry
+	analyticsRepo analytics.Repo
+	eventRepo     event.Repo
+	userRepo      user.Repo
+}
+
+func New(p Params) Service {
+	return &service{
+		logger:        p.Logger,
+		sentry:        p.Sentry,
+		analyticsRepo: p.AnalyticsRepo,
+		eventRepo:     p.EventRepo,
+		userRepo:      p.UserRepo,
+	}
+}
@@ -0,0 +1,18 @@
+package apiclient
+
+import "time"
+
+type Usage struct {
+	Client    string `json:"client"`
+	RateLimit int64  `json:"rateLimit"`
+	Daily     Quota  `json:"daily"`
+	Monthly   Quota  `json:"monthly"`
+}
+
+// Quota values are -1 if the quota is unlimited
+type Quota struct {
+	ResetAt   time.Time `json:"resetAt"`
+	Limit     int64     `json:"limit"`
+	Used      int64     `json:"used"`
+	Remaining int64     `json:"remaining"`
+}
@@ -0,0 +1,43 @@
+package apiclient
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/repo/apiclient"
+	"notifications/pkg/lib/observer/logger"
+	"notifications/pkg/lib/observer/sentry"
+	"notifications/pkg/lib/security/ratelimiter"
+)
+
+var Module = fx.Provide(New)
+
+type Service interface {
+	Usage(ctx context.Context, client string) (Usage, error)
+}
+
+type Params struct {
+	fx.In
+
+	Logger        logger.Logger
+	Sentry        sentry.Sentry
+	RateLimiter   ratelimiter.Limiter
+	APIClientRepo apiclient.Repo
+}
+
+type service struct {
+	logger        logger.Logger
+	sentry        sentry.Sentry
+	rateLimiter   ratelimiter.Limiter
+	apiClientRepo apiclient.Repo
+}
+
+func New(p Params) Service {
+	return &service{
+		logger:        p.Logger,
+		sentry:        p.Sentry,
+		rateLimiter:   p.RateLimiter,
+		apiClientRepo: p.APIClientRepo,
+	}
+}
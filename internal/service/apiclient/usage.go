@@ -0,0 +1,37 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/repo/repomodel"
+)
+
+func (s *service) Usage(ctx context.Context, client string) (Usage, error) {
+	apiClient, err := s.apiClientRepo.GetByUserID(ctx, client)
+	if err != nil {
+		if errors.Is(err, repomodel.ErrNotFound) {
+			return Usage{}, resp.Wrap(resp.ErrNotFound, "api client not found")
+		}
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during getting api client", zap.Error(err), zap.String("client", client))
+		return Usage{}, resp.ErrInternalErr
+	}
+
+	usage, err := s.rateLimiter.Usage(ctx, client)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during getting api client usage", zap.Error(err), zap.String("client", client))
+		return Usage{}, resp.ErrInternalErr
+	}
+
+	return Usage{
+		Client:    apiClient.Client,
+		RateLimit: apiClient.RateLimit,
+		Daily:     newQuota(apiClient.DailyQuota, usage.Daily, usage.DailyResetAt),
+		Monthly:   newQuota(apiClient.MonthlyQuota, usage.Monthly, usage.MonthlyResetAt),
+	}, nil
+}
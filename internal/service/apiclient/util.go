@@ -0,0 +1,18 @@
+package apiclient
+
+import "time"
+
+func newQuota(limit, used int64, resetAt time.Time) Quota {
+	var quota = Quota{
+		ResetAt:   resetAt,
+		Limit:     limit,
+		Used:      used,
+		Remaining: -1,
+	}
+
+	if limit > 0 {
+		quota.Remaining = max(limit-used, 0)
+	}
+
+	return quota
+}
@@ -0,0 +1,102 @@
+package dlq
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/transport/broker/stream"
+	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/service/admin"
+	"notifications/pkg/lib/broker/nats"
+	"notifications/pkg/util/strset"
+)
+
+func (s *service) GetMessages(ctx context.Context, filter Filter) (*Page, error) {
+	if strset.IsEmpty(filter.Subject) {
+		filter.Subject = subject.NotificationsDlq
+	}
+	if !strings.HasPrefix(filter.Subject, _subjectPref) {
+		return nil, resp.Wrap(resp.ErrBadRequest, "subject must start with "+_subjectPref)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = _defaultLimit
+	}
+	filter.Limit = min(filter.Limit, _maxLimit)
+
+	// one more message is fetched to know if there is a next page
+	list, err := s.nats.DeadLetters(ctx, stream.NotificationsDlq, filter.Subject, filter.Cursor, filter.Limit+1)
+	if err != nil {
+		if !errors.Is(err, nats.ErrDeadLetterNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting dead letters", zap.Error(err), zap.String("subject", filter.Subject))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "dlq messages not found")
+	}
+
+	var page = new(Page)
+	if len(list) > filter.Limit {
+		list = list[:filter.Limit]
+		page.NextCursor = list[len(list)-1].Sequence + 1
+	}
+
+	page.Items = make([]*Message, 0, len(list))
+	for _, item := range list {
+		var message = new(Message)
+		message.toService(&item)
+		page.Items = append(page.Items, message)
+	}
+
+	return page, nil
+}
+
+func (s *service) GetMessage(ctx context.Context, seq uint64) (*Message, error) {
+	item, err := s.nats.DeadLetter(ctx, stream.NotificationsDlq, seq)
+	if err != nil {
+		if !errors.Is(err, nats.ErrDeadLetterNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting dead letter", zap.Error(err), zap.Uint64("seq", seq))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "dlq message not found")
+	}
+
+	var message = new(Message)
+	message.toService(item)
+
+	return message, nil
+}
+
+func (s *service) Replay(ctx context.Context, a admin.Admin, seq uint64) error {
+	message, err := s.GetMessage(ctx, seq)
+	if err != nil {
+		return err
+	}
+
+	err = s.nats.Replay(ctx, stream.NotificationsDlq, seq)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during replaying dead letter", zap.Error(err), zap.Uint64("seq", seq))
+		return err
+	}
+
+	err = s.nats.Publish(stream.Audit, subject.AuditAdd, admin.Audit{
+		AdminId:   a.ID,
+		IpAddress: a.IP,
+		EventName: admin.ReplayDlqEvent,
+		OldData:   message,
+		NewData:   nil,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to publish audit event", zap.Error(err), zap.Uint64("seq", seq))
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package dlq
+
+import (
+	"encoding/json"
+	"time"
+
+	"notifications/pkg/lib/broker/nats"
+)
+
+const (
+	_defaultLimit = 20
+	_maxLimit     = 100
+	_subjectPref  = "notifications.dlq."
+)
+
+type Filter struct {
+	// Subject is the dlq subject, e.g. notifications.dlq.push.sent, all the subjects are listed if empty
+	Subject string
+	Cursor  uint64
+	Limit   int
+}
+
+type Page struct {
+	Items      []*Message `json:"items"`
+	NextCursor uint64     `json:"nextCursor,omitempty"`
+}
+
+type Message struct {
+	Sequence        uint64          `json:"sequence"`
+	Subject         string          `json:"subject"`
+	OriginalStream  string          `json:"originalStream"`
+	OriginalSubject string          `json:"originalSubject"`
+	Consumer        string          `json:"consumer"`
+	Error           string          `json:"error"`
+	Attempts        int             `json:"attempts"`
+	Data            json.RawMessage `json:"data"`
+	FailedAt        time.Time       `json:"failedAt"`
+	ReceivedAt      time.Time       `json:"receivedAt"`
+}
+
+func (m *Message) toService(item *nats.DeadLetter) {
+	m.Sequence = item.Sequence
+	m.Subject = item.Subject
+	m.OriginalStream = item.Stream
+	m.OriginalSubject = item.OrigSubj
+	m.Consumer = item.Consumer
+	m.Error = item.Error
+	m.Attempts = item.Attempts
+	m.FailedAt = item.FailedAt
+	m.ReceivedAt = item.ReceivedAt
+
+	// payloads are json in general, anything else is shown as a string
+	m.Data = item.Data
+	if !json.Valid(item.Data) {
+		m.Data, _ = json.Marshal(string(item.Data))
+	}
+}
@@ -0,0 +1,51 @@
+package dlq
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/service/admin"
+	"notifications/pkg/lib/broker/nats"
+	"notifications/pkg/lib/observer/logger"
+	"notifications/pkg/lib/observer/sentry"
+)
+
+var Module = fx.Provide(New)
+
+type Service interface {
+	reader
+	replayer
+}
+
+type reader interface {
+	GetMessages(ctx context.Context, filter Filter) (*Page, error)
+	GetMessage(ctx context.Context, seq uint64) (*Message, error)
+}
+
+type replayer interface {
+	// Replay publishes the message to its original subject and removes it from the dlq
+	Replay(ctx context.Context, a admin.Admin, seq uint64) error
+}
+
+type Params struct {
+	fx.In
+
+	Logger logger.Logger
+	Sentry sentry.Sentry
+	Nats   nats.Event
+}
+
+type service struct {
+	logger logger.Logger
+	sentry sentry.Sentry
+	nats   nats.Event
+}
+
+func New(p Params) Service {
+	return &service{
+		logger: p.Logger,
+		sentry: p.Sentry,
+		nats:   p.Nats,
+	}
+}
@@ -2,14 +2,34 @@ package email
 
 import (
 	"context"
+	"maps"
 	"strings"
 
 	"go.uber.org/zap"
 
+	"notifications/internal/service/template"
 	"notifications/pkg/lib/notifier/email"
+	"notifications/pkg/util/strset"
 )
 
-func (s *service) Send(_ context.Context, request Email) error {
+func (s *service) Send(ctx context.Context, request Email) error {
+	if !strset.IsEmpty(request.TemplateKey) {
+		content, err := s.templates.Render(ctx, request.TemplateKey, template.Recipient{
+			UserID:   request.UserID,
+			Language: request.Language,
+		}, request.Variables)
+		if err != nil {
+			s.logger.Error("err occurred during rendering template", zap.Error(err), zap.String("templateKey", request.TemplateKey))
+			return err
+		}
+
+		var body = make(map[string]string, len(request.Body)+2)
+		maps.Copy(body, request.Body)
+		body[_subject] = content.Title
+		body[_text] = content.Body
+		request.Body = body
+	}
+
 	var (
 		host      = s.config.GetString("email.host")
 		port      = s.config.GetString("email.port")
@@ -8,6 +8,12 @@ const (
 
 type Email struct {
 	Body map[string]string
+	// TemplateKey is rendered to the subject and text of the Body with the Variables,
+	// the language is taken from the user by UserID if it's not set
+	TemplateKey string
+	Language    string
+	UserID      int
+	Variables   map[string]any
 }
 
 const (
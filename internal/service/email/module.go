@@ -6,6 +6,7 @@ import (
 
 	"go.uber.org/fx"
 
+	"notifications/internal/service/template"
 	"notifications/pkg/lib/config"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/observer/sentry"
@@ -20,9 +21,10 @@ type Service interface {
 type Params struct {
 	fx.In
 
-	Config config.Config
-	Logger logger.Logger
-	Sentry sentry.Sentry
+	Config    config.Config
+	Logger    logger.Logger
+	Sentry    sentry.Sentry
+	Templates template.Service
 }
 
 type service struct {
@@ -30,13 +32,15 @@ type service struct {
 	logger    logger.Logger
 	sentry    sentry.Sentry
 	plainAuth smtp.Auth
+	templates template.Service
 }
 
 func New(p Params) Service {
 	return &service{
-		config: p.Config,
-		logger: p.Logger,
-		sentry: p.Sentry,
+		config:    p.Config,
+		logger:    p.Logger,
+		sentry:    p.Sentry,
+		templates: p.Templates,
 		plainAuth: smtp.PlainAuth("",
 			p.Config.GetString("email.from"),
 			p.Config.GetString("email.password"),
@@ -0,0 +1,207 @@
+package event
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/lib/country"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/user"
+	"notifications/pkg/lib/fileman"
+	"notifications/pkg/util/sheet"
+	"notifications/pkg/util/strset"
+)
+
+// _columns are the headers of the audience columns, the headers are compared in lower case without separators,
+// the first matched column is used if the file has several ones
+var _columns = []struct {
+	column  string
+	headers []string
+}{
+	{column: user.ColumnUserID, headers: []string{"userid"}},
+	{column: user.ColumnPersonExternalRef, headers: []string{"personexternalref", "externalref"}},
+	{column: user.ColumnPhone, headers: []string{"phone", "phonenumber", "msisdn"}},
+}
+
+// detectColumn returns the audience column and its index in the header
+func detectColumn(header []string) (string, int, error) {
+	var normalized = make([]string, 0, len(header))
+	for _, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		normalized = append(normalized, strings.NewReplacer(" ", "", "_", "", "-", "").Replace(h))
+	}
+
+	for _, c := range _columns {
+		for idx, h := range normalized {
+			for _, name := range c.headers {
+				if h == name {
+					return c.column, idx, nil
+				}
+			}
+		}
+	}
+
+	return "", 0, resp.Wrap(resp.ErrBadRequest, "header must have userID, phone or personExternalRef column")
+}
+
+// normalize returns the value of the audience column in the format it's stored in
+func (s *service) normalize(column, value string, countryID int8) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", false
+	}
+
+	switch column {
+	case user.ColumnUserID:
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			return "", false
+		}
+		return strset.IntToStr(id), true
+	case user.ColumnPhone:
+		var countries = s.countries.GetAll()
+		if c, err := s.countries.ByID(countryID); err == nil {
+			countries = []country.Country{c}
+		}
+		for _, c := range countries {
+			if phone, ok := c.NormalizePhone(value); ok {
+				return phone, true
+			}
+		}
+		return "", false
+	default:
+		return value, true
+	}
+}
+
+// openSheet returns the reader of the rows of the file, the xlsx file is spooled to a temporary file
+// as zip needs random access, close removes it
+func openSheet(file io.Reader, ext string) (_ sheet.Reader, closeFn func(), err error) {
+	if ext != fileman.Xlsx {
+		return sheet.NewCSV(file), func() {}, nil
+	}
+
+	if f, ok := file.(interface {
+		io.ReaderAt
+		io.Seeker
+	}); ok {
+		size, err := f.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader, err := sheet.NewXLSX(f, size)
+		return reader, func() {}, err
+	}
+
+	tmp, err := os.CreateTemp("", "audience-*.xlsx")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeFn = func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}
+
+	size, err := io.Copy(tmp, file)
+	if err != nil {
+		closeFn()
+		return nil, nil, err
+	}
+
+	reader, err := sheet.NewXLSX(tmp, size)
+	if err != nil {
+		closeFn()
+		return nil, nil, err
+	}
+
+	return reader, closeFn, nil
+}
+
+// rejectedReport writes the rejected rows to a temporary csv file, upload sends it to the file storage
+type rejectedReport struct {
+	file   *os.File
+	writer *csv.Writer
+	count  int
+}
+
+func newRejectedReport() (*rejectedReport, error) {
+	file, err := os.CreateTemp("", "rejected-*.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	var r = &rejectedReport{file: file, writer: csv.NewWriter(file)}
+
+	err = r.writer.Write([]string{"line", "value", "reason"})
+	if err != nil {
+		r.close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *rejectedReport) write(rows []rejectedRow) error {
+	for _, row := range rows {
+		err := r.writer.Write([]string{strset.IntToStr(row.Line), row.Value, row.Reason})
+		if err != nil {
+			return err
+		}
+		r.count++
+	}
+	return nil
+}
+
+func (r *rejectedReport) upload(fm fileman.FileManager, bucket *string, dir, fileName string) error {
+	r.writer.Flush()
+	if err := r.writer.Error(); err != nil {
+		return err
+	}
+
+	_, err := r.file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	return fm.Upload(r.file, bucket, dir, fileName)
+}
+
+func (r *rejectedReport) close() {
+	_ = r.file.Close()
+	_ = os.Remove(r.file.Name())
+}
+
+func (s *service) GetRejectedReport(ctx context.Context, id int) (io.ReadCloser, error) {
+	selectedEvent, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting event", zap.Error(err), zap.Int("id", id))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "event not found or not active")
+	}
+
+	fileName, ok := selectedEvent.ExtraData[_rejectedReportKey]
+	if !ok {
+		return nil, resp.Wrap(resp.ErrNotFound, "event has no rejected rows")
+	}
+
+	report, err := s.fileManager.Download(&s.bucket, s.audienceDirectory, fileName)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to download rejected report", zap.Error(err), zap.Int("id", id))
+		return nil, err
+	}
+
+	return report, nil
+}
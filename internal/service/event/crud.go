@@ -14,8 +14,8 @@ import (
 	"notifications/internal/api/resp"
 	"notifications/internal/api/transport/broker/stream"
 	"notifications/internal/api/transport/broker/subject"
-	"notifications/internal/lib/language"
 	"notifications/internal/repo/event"
+	"notifications/internal/repo/outbox"
 	"notifications/internal/repo/repomodel"
 	"notifications/internal/service/admin"
 	"notifications/pkg/lib/tinypng"
@@ -25,11 +25,12 @@ import (
 
 func (s *service) GetEvents(ctx context.Context, filter Filter) ([]*Event, error) {
 	list, err := s.eventRepo.GetByFilter(ctx, event.Filter{
-		ID:     filter.ID,
-		Topic:  filter.Topic,
-		Status: filter.Status,
-		Limit:  filter.Limit,
-		Offset: filter.Offset,
+		ID:        filter.ID,
+		Topic:     filter.Topic,
+		Status:    filter.Status,
+		Limit:     filter.Limit,
+		Offset:    filter.Offset,
+		CountryID: filter.CountryID,
 	})
 	if err != nil {
 		if !errors.Is(err, repomodel.ErrNotFound) {
@@ -51,19 +52,19 @@ func (s *service) GetEvents(ctx context.Context, filter Filter) ([]*Event, error
 	return events, nil
 }
 
-func (s *service) Create(ctx context.Context, a admin.Admin, request *Request) (*Event, error) {
+func (s *service) Create(ctx context.Context, a admin.Admin, request *Request) (_ *Event, err error) {
 	if strset.IsEmpty(request.Topic) {
 		return nil, resp.Wrap(resp.ErrBadRequest, "topic cannot be empty")
 	}
 
-	err := s.validate(request)
+	err = s.validate(request)
 	if err != nil {
 		return nil, err
 	}
 
 	var eventItem = &event.Event{
 		ID:          int(s.idGenerator.Generate().Int64()),
-		Status:      request.Status,
+		Status:      _draft,
 		Title:       request.Title,
 		Body:        request.Body,
 		Topic:       request.Topic,
@@ -71,20 +72,53 @@ func (s *service) Create(ctx context.Context, a admin.Admin, request *Request) (
 		Link:        request.Link,
 		ExtraData:   request.ExtraData,
 		ScheduledAt: request.ScheduledAtTime,
+		CountryID:   request.CountryID,
+		Segment:     segmentToRepo(request.Segment),
+		ABTest:      abTestToRepo(request.ABTest),
+		Recurrence:  recurrenceToRepo(request.Recurrence),
+		Delivery:    request.Delivery,
 	}
 
-	createdEvent, err := s.eventRepo.Create(ctx, eventItem)
+	if eventItem.Delivery == _empty {
+		eventItem.Delivery = _topicDelivery
+	}
+
+	if eventItem.Recurrence != nil {
+		eventItem.ScheduledAt, err = s.firstOccurrence(eventItem.Recurrence, eventItem.CountryID, eventItem.ScheduledAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tx := s.transactor.New()
+	err = tx.Begin(ctx)
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("failed to create events", zap.Error(err))
+		s.logger.Error("err on tx.Begin", zap.Error(err))
 		return nil, err
 	}
 
-	var item = new(Event)
-	item.toService(createdEvent)
-	s.setImgURL(item)
+	defer func() {
+		if err != nil {
+			if errX := tx.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	createdEvent, err := tx.EventRepo().Create(ctx, eventItem)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to create events", zap.Error(err))
+		return nil, err
+	}
 
-	err = s.nats.Publish(stream.Audit, subject.AuditAdd, admin.Audit{
+	err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
 		AdminId:   a.ID,
 		IpAddress: a.IP,
 		EventName: admin.CreateNotificationsEvent,
@@ -94,14 +128,26 @@ func (s *service) Create(ctx context.Context, a admin.Admin, request *Request) (
 	})
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("failed to publish audit event", zap.Error(err))
+		s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+		return nil, err
+	}
+
+	if request.Status == _pendingApproval {
+		err = s.submit(ctx, tx, a, createdEvent)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	var item = new(Event)
+	item.toService(createdEvent)
+	s.setImgURL(item)
+
 	return item, nil
 }
 
-func (s *service) Update(ctx context.Context, a admin.Admin, request *Request) (*Event, error) {
-	err := s.validate(request)
+func (s *service) Update(ctx context.Context, a admin.Admin, request *Request) (_ *Event, err error) {
+	err = s.validate(request)
 	if err != nil {
 		return nil, err
 	}
@@ -116,23 +162,20 @@ func (s *service) Update(ctx context.Context, a admin.Admin, request *Request) (
 		return nil, resp.Wrap(resp.ErrNotFound, "event not found")
 	}
 
-	if selectedEvent.Status == _sent {
-		return nil, resp.Wrap(resp.ErrBadRequest, "cannot change event with sent status")
+	// the approved event is rejected back to draft to be changed
+	err = checkStatus(selectedEvent, "change", _draft)
+	if err != nil {
+		return nil, err
 	}
 
 	var oldEvent = *selectedEvent
 
-	var _emptyLang language.Language
-
-	if request.Title != _emptyLang {
+	if len(request.Title) != 0 {
 		selectedEvent.Title = request.Title
 	}
-	if request.Body != _emptyLang {
+	if len(request.Body) != 0 {
 		selectedEvent.Body = request.Body
 	}
-	if !strset.IsEmpty(request.Status) && selectedEvent.Status != request.Status {
-		selectedEvent.Status = request.Status
-	}
 	if !strset.IsEmpty(request.Category) && selectedEvent.Category != request.Category {
 		selectedEvent.Category = request.Category
 	}
@@ -143,20 +186,77 @@ func (s *service) Update(ctx context.Context, a admin.Admin, request *Request) (
 		selectedEvent.ScheduledAt = request.ScheduledAtTime
 	}
 
+	if request.Segment != nil {
+		if selectedEvent.CountryID != 0 && request.Segment.CountryID != 0 && request.Segment.CountryID != selectedEvent.CountryID {
+			return nil, resp.Wrap(resp.ErrBadRequest, "segment country differs from the event country")
+		}
+		selectedEvent.Segment = segmentToRepo(request.Segment)
+	}
+
+	// the users are subscribed to the topics of their variants when they are loaded
+	if request.ABTest != nil || (request.Delivery != _empty && request.Delivery != selectedEvent.Delivery) {
+		counts, err := s.userRepo.CountRelations(ctx, selectedEvent.ID)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("failed to count user relations", zap.Error(err), zap.Int("id", request.ID))
+			return nil, err
+		}
+		if len(counts) != 0 {
+			return nil, resp.Wrap(resp.ErrBadRequest, "cannot change A/B test or delivery after the users are loaded")
+		}
+	}
+
+	if request.ABTest != nil {
+		selectedEvent.ABTest = abTestToRepo(request.ABTest)
+	}
+	if request.Delivery != _empty {
+		selectedEvent.Delivery = request.Delivery
+	}
+
+	if request.Recurrence != nil {
+		selectedEvent.Recurrence = recurrenceToRepo(request.Recurrence)
+	}
+	if selectedEvent.Recurrence != nil {
+		if selectedEvent.ABTest != nil {
+			return nil, resp.Wrap(resp.ErrBadRequest, "event with A/B test cannot be recurring")
+		}
+		selectedEvent.ScheduledAt, err = s.firstOccurrence(selectedEvent.Recurrence, selectedEvent.CountryID, selectedEvent.ScheduledAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	selectedEvent.ExtraData = request.ExtraData
 
-	updatedEvent, err := s.eventRepo.Update(ctx, selectedEvent)
+	tx := s.transactor.New()
+	err = tx.Begin(ctx)
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("failed to update events", zap.Error(err), zap.Int("id", request.ID))
+		s.logger.Error("err on tx.Begin", zap.Error(err))
 		return nil, err
 	}
 
-	var item = new(Event)
-	item.toService(updatedEvent)
-	s.setImgURL(item)
+	defer func() {
+		if err != nil {
+			if errX := tx.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
 
-	err = s.nats.Publish(stream.Audit, subject.AuditAdd, admin.Audit{
+	updatedEvent, err := tx.EventRepo().Update(ctx, selectedEvent)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to update events", zap.Error(err), zap.Int("id", request.ID))
+		return nil, err
+	}
+
+	err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
 		AdminId:   a.ID,
 		IpAddress: a.IP,
 		EventName: admin.UpdateNotificationsEvent,
@@ -166,9 +266,21 @@ func (s *service) Update(ctx context.Context, a admin.Admin, request *Request) (
 	})
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("failed to publish audit event", zap.Error(err))
+		s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+		return nil, err
+	}
+
+	if request.Status == _pendingApproval {
+		err = s.submit(ctx, tx, a, updatedEvent)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	var item = new(Event)
+	item.toService(updatedEvent)
+	s.setImgURL(item)
+
 	return item, nil
 }
 
@@ -189,11 +301,15 @@ func (s *service) validate(request *Request) error {
 		return resp.Wrap(resp.ErrBadRequest, "status cannot be empty")
 	}
 
-	if !slices.Contains([]string{_active, _draft}, request.Status) {
+	if !slices.Contains([]string{_draft, _pendingApproval}, request.Status) {
 		s.logger.Warning("status is not valid", zap.String("status", request.Status))
 		return resp.Wrap(resp.ErrBadRequest, "status is not valid")
 	}
 
+	if !slices.Contains([]string{_empty, _topicDelivery, _directDelivery}, request.Delivery) {
+		return resp.Wrap(resp.ErrBadRequest, "delivery is not valid, allowed: topic, direct")
+	}
+
 	scheduledAt, err := time.Parse(timeset.Layout, request.ScheduledAt)
 	if err != nil {
 		s.logger.Warning("scheduled at is not valid", zap.String("status", request.ScheduledAt))
@@ -206,13 +322,36 @@ func (s *service) validate(request *Request) error {
 		return resp.Wrap(resp.ErrBadRequest, "scheduled at cannot be before or equal to current time")
 	}
 
+	if request.CountryID != 0 {
+		_, err = s.countries.ByID(request.CountryID)
+		if err != nil {
+			s.logger.Warning("country is not valid", zap.Error(err))
+			return resp.Wrap(resp.ErrBadRequest, err.Error())
+		}
+	}
+
+	err = s.validateSegment(request)
+	if err != nil {
+		return err
+	}
+
+	err = validateABTest(request.ABTest)
+	if err != nil {
+		return err
+	}
+
+	err = validateRecurrence(request)
+	if err != nil {
+		return err
+	}
+
 	request.ScheduledAtTime = scheduledAt
 	return nil
 }
 
-func (s *service) Delete(ctx context.Context, a admin.Admin, id int) error {
+func (s *service) Delete(ctx context.Context, a admin.Admin, id int) (err error) {
 	tx := s.transactor.New()
-	err := tx.Begin(ctx)
+	err = tx.Begin(ctx)
 	if err != nil {
 		s.sentry.CaptureException(err)
 		s.logger.Error("err on tx.Begin", zap.Error(err))
@@ -242,6 +381,10 @@ func (s *service) Delete(ctx context.Context, a admin.Admin, id int) error {
 		return resp.Wrap(resp.ErrNotFound, "event not found")
 	}
 
+	if selectedEvent.Status == _loadingUsers || selectedEvent.Status == _sending {
+		return resp.Wrap(resp.ErrBadRequest, fmt.Sprintf("cannot delete event with %s status", selectedEvent.Status))
+	}
+
 	for _, img := range selectedEvent.Image.GetAll() {
 		if !strset.IsEmpty(img) {
 			var eg errgroup.Group
@@ -268,7 +411,7 @@ func (s *service) Delete(ctx context.Context, a admin.Admin, id int) error {
 		return err
 	}
 
-	err = s.nats.Publish(stream.Audit, subject.AuditAdd, admin.Audit{
+	err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
 		AdminId:   a.ID,
 		IpAddress: a.IP,
 		EventName: admin.DeleteNotificationsEvent,
@@ -278,7 +421,8 @@ func (s *service) Delete(ctx context.Context, a admin.Admin, id int) error {
 	})
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("failed to publish audit event", zap.Error(err))
+		s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+		return err
 	}
 
 	return nil
@@ -0,0 +1,398 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"notifications/internal/repo/event"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/user"
+	usersrv "notifications/internal/service/user"
+	"notifications/pkg/lib/notifier/apns"
+	"notifications/pkg/lib/notifier/firebase"
+	"notifications/pkg/lib/notifier/hms"
+	"notifications/pkg/lib/notifier/webpush"
+	"notifications/pkg/util/strset"
+)
+
+const (
+	// _offlineTTL is the time APNs and the push services of the browsers keep the push of the event for the offline device
+	_offlineTTL = 24 * time.Hour
+	// _eventCollapsePrefix is the prefix of the collapse ID of the event, the pending push is replaced by the latest one
+	_eventCollapsePrefix = "event-"
+)
+
+const (
+	// _multicastLimit is the max number of the tokens of the multicast message
+	_multicastLimit = 500
+	_recipientsPage = 10 * _multicastLimit
+	_directWorkers  = 10
+)
+
+// Statuses of the deliveries of the event sent directly
+const (
+	_deliverySent   = "sent"
+	_deliveryFailed = "failed"
+)
+
+// sendDirect sends the event to the tokens of the users of the variants in batches of _multicastLimit
+// and records the result of every user in the run, the invalid tokens of the devices are reported to be removed
+func (s *service) sendDirect(ctx context.Context, e *event.Event, runID int, variants []string) (success, failed int, err error) {
+	var lastID int
+
+	for {
+		recipients, err := s.userRepo.GetRecipients(ctx, e.ID, lastID, _recipientsPage)
+		if err != nil {
+			if errors.Is(err, repomodel.ErrNotFound) {
+				break
+			}
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting recipients", zap.Error(err), zap.Int("eventID", e.ID))
+			return success, failed, err
+		}
+
+		lastID = recipients[len(recipients)-1].UserID
+
+		sent, notSent, err := s.sendRecipients(ctx, e, runID, recipients, variants)
+		success, failed = success+sent, failed+notSent
+		if err != nil {
+			return success, failed, err
+		}
+	}
+
+	return success, failed, nil
+}
+
+// sendRecipients sends the event to the page of the recipients in batches in parallel. The recipients recorded in
+// the deliveries of the run already are not sent again and counted by their status, so the page of the resumed run
+// is sent only to the recipients of the batches which weren't sent before it was interrupted
+func (s *service) sendRecipients(ctx context.Context, e *event.Event, runID int, recipients []user.Recipient, variants []string) (success, failed int, err error) {
+	var userIDs = make([]int, 0, len(recipients))
+	for _, recipient := range recipients {
+		userIDs = append(userIDs, recipient.UserID)
+	}
+
+	statuses, err := s.eventRepo.GetDeliveryStatuses(ctx, runID, userIDs)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during getting delivery statuses", zap.Error(err), zap.Int("eventID", e.ID), zap.Int("runID", runID))
+		return 0, 0, err
+	}
+
+	if len(statuses) != 0 {
+		recipients = slices.DeleteFunc(slices.Clone(recipients), func(recipient user.Recipient) bool {
+			status, ok := statuses[recipient.UserID]
+			switch {
+			case !ok:
+				return false
+			case status == _deliverySent:
+				success++
+			default:
+				failed++
+			}
+			return true
+		})
+	}
+
+	var successCount, failedCount atomic.Int64
+	successCount.Store(int64(success))
+	failedCount.Store(int64(failed))
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(_directWorkers)
+
+	for _, batch := range batchRecipients(recipients, variants) {
+		eg.Go(func() error {
+			sent, err := s.sendBatch(egCtx, e, runID, batch)
+			if err != nil {
+				return err
+			}
+			successCount.Add(int64(sent))
+			failedCount.Add(int64(len(batch) - sent))
+			return nil
+		})
+	}
+
+	err = eg.Wait()
+	return int(successCount.Load()), int(failedCount.Load()), err
+}
+
+// sendBatch sends the message to the devices of the recipients of the same language and variant at their providers
+// and returns the number of the succeeded ones, the recipient succeeded if any of its devices got the message
+func (s *service) sendBatch(ctx context.Context, e *event.Event, runID int, batch []user.Recipient) (int, error) {
+	var (
+		fcm, hmsDevices, apnsDevices, webDevices batchDevices
+		deliveries                               = make([]event.Delivery, 0, len(batch))
+	)
+	for i, recipient := range batch {
+		for _, device := range recipient.ActiveDevices() {
+			switch device.Provider {
+			case user.ProviderHms:
+				hmsDevices.add(device, i)
+			case user.ProviderApns:
+				apnsDevices.add(device, i)
+			case user.ProviderWebPush:
+				webDevices.add(device, i)
+			default:
+				fcm.add(device, i)
+			}
+		}
+
+		deliveries = append(deliveries, event.Delivery{
+			RunID:   runID,
+			EventID: e.ID,
+			UserID:  recipient.UserID,
+			Status:  _deliveryFailed,
+		})
+	}
+
+	var success int
+
+	if len(fcm.tokens) != 0 {
+		response, err := s.fcmSender.SendMulticast(ctx, multicastMessage(e, batch[0].Lang, batch[0].Variant, runID, fcm.tokens))
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during sending multicast", zap.Error(err), zap.Int("eventID", e.ID))
+			return 0, err
+		}
+
+		for i, res := range response.Responses {
+			var delivery = &deliveries[fcm.recipients[i]]
+
+			if res.Success {
+				success += sent(delivery, res.MessageID)
+				continue
+			}
+
+			if delivery.Status != _deliverySent {
+				delivery.ErrorCode = firebase.ErrCode(res.Error)
+			}
+
+			if usersrv.IsInvalidTokenErr(res.Error) {
+				s.users.PublishTokenRemoved(delivery.UserID, fcm.tokens[i])
+			}
+		}
+	}
+
+	if len(hmsDevices.tokens) != 0 {
+		success += s.sendHmsBatch(ctx, e, runID, batch[0], hmsDevices, deliveries)
+	}
+
+	if len(apnsDevices.tokens) != 0 {
+		success += s.sendApnsBatch(ctx, e, runID, batch[0], apnsDevices, deliveries)
+	}
+
+	if len(webDevices.tokens) != 0 {
+		success += s.sendWebBatch(ctx, e, runID, batch[0], webDevices, deliveries)
+	}
+
+	// the pushes are sent already, the failure to record them doesn't fail the run
+	_, err := s.eventRepo.BatchInsertDeliveries(ctx, deliveries)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during saving deliveries", zap.Error(err), zap.Int("eventID", e.ID), zap.Int("runID", runID))
+	}
+
+	return success, nil
+}
+
+// sendHmsBatch sends the message to the HMS devices of the batch and returns the number of the recipients succeeded
+// by them. The pushes to FCM are sent already, so the failure of HMS is recorded to the deliveries and doesn't fail the run
+func (s *service) sendHmsBatch(ctx context.Context, e *event.Event, runID int, recipient user.Recipient, devices batchDevices, deliveries []event.Delivery) (success int) {
+	var message = &hms.Message{Tokens: devices.tokens}
+	hms.NotificationMSG(message, pushData(e, recipient.Lang, recipient.Variant, runID), hms.UrgencyNormal)
+
+	response, err := s.hmsSender.Send(ctx, message)
+	if err != nil {
+		if !usersrv.IsInvalidTokenErr(err) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during sending hms message", zap.Error(err), zap.Int("eventID", e.ID))
+		}
+		for i, token := range devices.tokens {
+			var delivery = &deliveries[devices.recipients[i]]
+			if delivery.Status != _deliverySent {
+				delivery.ErrorCode = hms.ErrCode(err)
+			}
+			if usersrv.IsInvalidTokenErr(err) {
+				s.users.PublishTokenRemoved(delivery.UserID, token)
+			}
+		}
+		return 0
+	}
+
+	for i, token := range devices.tokens {
+		var delivery = &deliveries[devices.recipients[i]]
+
+		if !slices.Contains(response.IllegalTokens, token) {
+			success += sent(delivery, response.RequestID)
+			continue
+		}
+
+		if delivery.Status != _deliverySent {
+			delivery.ErrorCode = hms.CodeAllTokensInvalid
+		}
+		s.users.PublishTokenRemoved(delivery.UserID, token)
+	}
+
+	return success
+}
+
+// sendApnsBatch sends the notification to the APNs devices of the batch one by one and returns the number of the recipients
+// succeeded by them. The notifications of the event collapse into the latest one and expire if they're not delivered in time
+func (s *service) sendApnsBatch(ctx context.Context, e *event.Event, runID int, recipient user.Recipient, devices batchDevices, deliveries []event.Delivery) (success int) {
+	var notification = &apns.Notification{
+		CollapseID: _eventCollapsePrefix + strset.IntToStr(e.ID),
+		Expiration: time.Now().Add(_offlineTTL),
+	}
+	apns.AlertMSG(notification, pushData(e, recipient.Lang, recipient.Variant, runID), apns.PriorityNormal)
+
+	for i, token := range devices.tokens {
+		var delivery = &deliveries[devices.recipients[i]]
+
+		notification.Token = token
+		response, err := s.apnsSender.Send(ctx, notification)
+		if err == nil {
+			success += sent(delivery, response.ApnsID)
+			continue
+		}
+
+		if delivery.Status != _deliverySent {
+			delivery.ErrorCode = apns.ErrReason(err)
+		}
+		if usersrv.IsInvalidTokenErr(err) {
+			s.users.PublishTokenRemoved(delivery.UserID, token)
+			continue
+		}
+		s.logger.Warning("err occurred during sending apns notification", zap.Error(err), zap.Int("eventID", e.ID), zap.Int("userID", delivery.UserID))
+	}
+
+	return success
+}
+
+// sendWebBatch sends the push to the browser subscriptions of the batch one by one and returns the number of the recipients
+// succeeded by them, the pending push of the event is replaced by the latest one
+func (s *service) sendWebBatch(ctx context.Context, e *event.Event, runID int, recipient user.Recipient, devices batchDevices, deliveries []event.Delivery) (success int) {
+	payload, err := sonic.Marshal(pushData(e, recipient.Lang, recipient.Variant, runID))
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during marshaling web push", zap.Error(err), zap.Int("eventID", e.ID))
+		return 0
+	}
+
+	var message = &webpush.Message{
+		Payload: payload,
+		TTL:     _offlineTTL,
+		Urgency: webpush.UrgencyNormal,
+		Topic:   _eventCollapsePrefix + strset.IntToStr(e.ID),
+	}
+
+	for i, device := range devices.devices {
+		var delivery = &deliveries[devices.recipients[i]]
+
+		response, err := s.webSender.Send(ctx, &webpush.Subscription{Endpoint: device.Token, P256dh: device.P256dh, Auth: device.Auth}, message)
+		if err == nil {
+			success += sent(delivery, response.Location)
+			continue
+		}
+
+		if delivery.Status != _deliverySent {
+			delivery.ErrorCode = strset.IntToStr(webpush.Status(err))
+		}
+		if usersrv.IsInvalidTokenErr(err) {
+			s.users.PublishTokenRemoved(delivery.UserID, device.Token)
+			continue
+		}
+		s.logger.Warning("err occurred during sending web push", zap.Error(err), zap.Int("eventID", e.ID), zap.Int("userID", delivery.UserID))
+	}
+
+	return success
+}
+
+// batchDevices are the devices of the provider with the indexes of their recipients in the batch
+type batchDevices struct {
+	devices    []user.DeviceToken
+	tokens     []string
+	recipients []int
+}
+
+func (b *batchDevices) add(device user.DeviceToken, recipient int) {
+	b.devices = append(b.devices, device)
+	b.tokens = append(b.tokens, device.Token)
+	b.recipients = append(b.recipients, recipient)
+}
+
+// sent marks the delivery sent by the message and returns 1 if it's the first device of the recipient which got it
+func sent(delivery *event.Delivery, messageID string) int {
+	if delivery.Status == _deliverySent {
+		return 0
+	}
+	delivery.Status, delivery.FcmMessageID, delivery.ErrorCode = _deliverySent, messageID, ""
+	return 1
+}
+
+// batchRecipients groups the reachable recipients of the variants by language and variant into the batches
+// of up to _multicastLimit tokens of their devices
+func batchRecipients(recipients []user.Recipient, variants []string) [][]user.Recipient {
+	var groups = make(map[string][]user.Recipient)
+	for _, recipient := range recipients {
+		if !recipient.Reachable || !slices.Contains(variants, recipient.Variant) {
+			continue
+		}
+		key := recipient.Lang + _underscoreDelim + recipient.Variant
+		groups[key] = append(groups[key], recipient)
+	}
+
+	var batches = make([][]user.Recipient, 0, len(groups))
+	for _, group := range groups {
+		var (
+			batch  []user.Recipient
+			tokens int
+		)
+		for _, recipient := range group {
+			count := len(recipient.ActiveDevices())
+			if len(batch) != 0 && tokens+count > _multicastLimit {
+				batches = append(batches, batch)
+				batch, tokens = nil, 0
+			}
+			batch = append(batch, recipient)
+			tokens += count
+		}
+		if len(batch) != 0 {
+			batches = append(batches, batch)
+		}
+	}
+
+	return batches
+}
+
+func (s *service) GetDeliveries(ctx context.Context, id, runID int, limit, offset uint) ([]Delivery, error) {
+	if limit == 0 {
+		limit = 20
+	}
+
+	deliveries, err := s.eventRepo.GetDeliveries(ctx, id, runID, limit, offset)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting deliveries", zap.Error(err), zap.Int("runID", runID))
+			return nil, err
+		}
+		return []Delivery{}, nil
+	}
+
+	var list = make([]Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		var item Delivery
+		item.toService(delivery)
+		list = append(list, item)
+	}
+
+	return list, nil
+}
@@ -60,6 +60,11 @@ func (s *service) UploadImage(ctx context.Context, a admin.Admin, lang string, i
 		return nil, err
 	}
 
+	err = checkStatus(selectedEvent, "change image of", _draft)
+	if err != nil {
+		return nil, err
+	}
+
 	var (
 		oldEvent = *selectedEvent
 		fileExt  = fileman.GetFileExt(fileHeader.Filename)
@@ -186,6 +191,11 @@ func (s *service) RemoveImage(ctx context.Context, a admin.Admin, lang string, i
 		return nil, err
 	}
 
+	err = checkStatus(selectedEvent, "change image of", _draft)
+	if err != nil {
+		return nil, err
+	}
+
 	var (
 		oldEvent = *selectedEvent
 		fileName = selectedEvent.Image.Get(lang)
@@ -9,36 +9,65 @@ import (
 
 	"notifications/internal/repo/repomodel"
 	"notifications/internal/service/admin"
+	"notifications/pkg/lib/cache"
 )
 
+// RunJob runs under the lease of the job, so the job published by every worker is run by one pod at a time
 func (s *service) RunJob() {
-	var ctx = context.Background()
+	var currentTime = time.Now()
 
-	events, err := s.eventRepo.GetAllActive(ctx)
+	lease, err := s.cache.Acquire(context.Background(), _jobLease, _leaseTTL)
 	if err != nil {
-		if !errors.Is(err, repomodel.ErrNotFound) {
+		if !errors.Is(err, cache.ErrLocked) {
 			s.sentry.CaptureException(err)
-			s.logger.Error("err occurred during getting active events", zap.Error(err))
+			s.logger.Error("err occurred during acquiring job lease", zap.Error(err))
+			return
 		}
+		s.logger.Info("event job is run by another pod")
 		return
 	}
 
-	var currentTime = time.Now()
+	defer func() {
+		if err := lease.Release(context.Background()); err != nil {
+			s.logger.Error("err occurred during releasing job lease", zap.Error(err))
+		}
+	}()
+
+	var ctx = lease.Context()
+
+	s.resumeRuns(ctx, currentTime)
+	s.runDueEvents(ctx, currentTime)
+	s.sendDueWinners(ctx, currentTime)
+}
+
+func (s *service) runDueEvents(ctx context.Context, currentTime time.Time) {
+	events, err := s.eventRepo.GetAllReady(ctx)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting ready events", zap.Error(err))
+		}
+		return
+	}
 
 	for _, event := range events {
 		if currentTime.After(event.ScheduledAt) || currentTime.Equal(event.ScheduledAt) {
+			var countryIDs []int8
+			if event.CountryID != 0 {
+				countryIDs = append(countryIDs, event.CountryID)
+			}
+
+			// the due event is sent on the first run after the quiet hours of the targeted countries
+			if quietUntil, quiet := s.quietHours.UntilForCountries(currentTime, countryIDs...); quiet {
+				s.logger.Info("event is deferred by quiet hours", zap.Int("id", event.ID), zap.Time("until", quietUntil))
+				continue
+			}
+
+			// the failed run is recorded or left to be resumed by RunEvent
 			response, err := s.RunEvent(ctx, admin.Admin{}, event.ID)
 			if err != nil {
 				s.logger.Error("err occurred during running event", zap.Error(err), zap.Int("id", event.ID))
-
-				event.Status = _failed
-				event.ExtraData["reason"] = err.Error()
-				_, err = s.eventRepo.Update(ctx, event)
-				if err != nil {
-					s.sentry.CaptureException(err)
-					s.logger.Error("err occurred during updating event status", zap.Error(err), zap.Int("id", event.ID))
-					continue
-				}
+				continue
 			}
 
 			s.logger.Info("Message successfully sent to topic", zap.Any("event", event), zap.Any("response", response))
@@ -3,6 +3,7 @@ package event
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,12 +14,15 @@ import (
 	"notifications/internal/api/resp"
 	"notifications/internal/api/transport/broker/stream"
 	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/repo/outbox"
 	"notifications/internal/repo/repomodel"
+	userrepo "notifications/internal/repo/user"
 	"notifications/internal/service/admin"
+	"notifications/pkg/lib/cache"
 	"notifications/pkg/util/strset"
 )
 
-func (s *service) LoadAllUsers(ctx context.Context, a admin.Admin, id int) (*Event, error) {
+func (s *service) LoadAllUsers(ctx context.Context, a admin.Admin, id int) (_ *Event, err error) {
 	selectedEvent, err := s.eventRepo.GetByID(ctx, id)
 	if err != nil {
 		if !errors.Is(err, repomodel.ErrNotFound) {
@@ -26,11 +30,12 @@ func (s *service) LoadAllUsers(ctx context.Context, a admin.Admin, id int) (*Eve
 			s.logger.Error("err occurred during getting event", zap.Error(err), zap.Int("id", id))
 			return nil, err
 		}
-		return nil, resp.Wrap(resp.ErrNotFound, "event not found or not active")
+		return nil, resp.Wrap(resp.ErrNotFound, "event not found or finished")
 	}
 
-	if selectedEvent.Status == _sent {
-		return nil, resp.Wrap(resp.ErrBadRequest, "cannot load users for event with sent status")
+	err = checkStatus(selectedEvent, "load users for", _scheduled, _ready)
+	if err != nil {
+		return nil, err
 	}
 
 	var oldEvent = *selectedEvent
@@ -39,25 +44,52 @@ func (s *service) LoadAllUsers(ctx context.Context, a admin.Admin, id int) (*Eve
 	delete(selectedEvent.ExtraData, _successCountKey)
 	delete(selectedEvent.ExtraData, _failedCountKey)
 	delete(selectedEvent.ExtraData, _failedReasonKey)
-	selectedEvent.Status = _loadingUsers
 
 	event.toService(selectedEvent)
 	event.SubscribeAll = true
-	err = s.nats.Publish(stream.Notifications, subject.NotificationsTopicUsersSubscribed, event)
+
+	tx := s.transactor.New()
+	err = tx.Begin(ctx)
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("cannot publish message", zap.Error(err), zap.Int("id", id))
+		s.logger.Error("err on tx.Begin", zap.Error(err))
 		return nil, err
 	}
 
-	_, err = s.eventRepo.Update(ctx, selectedEvent)
+	defer func() {
+		if err != nil {
+			if errX := tx.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	_, err = tx.EventRepo().Update(ctx, selectedEvent)
 	if err != nil {
 		s.sentry.CaptureException(err)
 		s.logger.Error("cannot update event", zap.Error(err), zap.Int("id", id))
 		return nil, err
 	}
 
-	err = s.nats.Publish(stream.Audit, subject.AuditAdd, admin.Audit{
+	err = s.transition(ctx, tx, a, selectedEvent, _loadingUsers)
+	if err != nil {
+		return nil, err
+	}
+	event.Status = selectedEvent.Status
+
+	err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Notifications, subject.NotificationsTopicUsersSubscribed, event)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("cannot save message to outbox", zap.Error(err), zap.Int("id", id))
+		return nil, err
+	}
+
+	err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
 		AdminId:   a.ID,
 		IpAddress: a.IP,
 		EventName: admin.LoadUsersEvent,
@@ -67,30 +99,51 @@ func (s *service) LoadAllUsers(ctx context.Context, a admin.Admin, id int) (*Eve
 	})
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("failed to publish audit event", zap.Error(err))
+		s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+		return nil, err
 	}
 
 	return event, nil
 }
 
+// SubscribeAllUsers loads the users under the lease of the event, so the event is loaded by one pod at a time,
+// the pages are taken by the workers after the cursor of the event
 func (s *service) SubscribeAllUsers(ctx context.Context, event *Event) (err error) {
 	s.logger.Info("SubscribeAllUsers start", zap.Int("eventID", event.ID))
 
+	lease, err := s.cache.Acquire(ctx, fmt.Sprintf(_loadLease, event.ID), _leaseTTL)
+	if err != nil {
+		if errors.Is(err, cache.ErrLocked) {
+			s.logger.Info("users of event are loaded by another pod", zap.Int("eventID", event.ID))
+			return nil
+		}
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during acquiring lease", zap.Error(err), zap.Int("eventID", event.ID))
+		return err
+	}
+
 	defer func() {
-		if err != nil {
-			event.Status = _failedLoading
-			event.ExtraData["reason"] = err.Error()
-			_, err = s.eventRepo.Update(ctx, toRepo(event))
+		if errX := lease.Release(context.WithoutCancel(ctx)); errX != nil {
+			s.logger.Error("err occurred during releasing lease", zap.Error(errX), zap.Int("eventID", event.ID))
+		}
+	}()
+
+	defer func() {
+		// the event is left to the pod which took over the lease
+		if err != nil && !errors.Is(err, cache.ErrLeaseLost) {
+			event.ExtraData[_reasonKey] = err.Error()
+			err = errors.Join(err, s.finishLoading(ctx, event, _scheduled), lease.Delete(ctx, _cursorKey))
 		}
 	}()
 
 	var (
 		resultCh = make(chan ChunkResult)
 		wg       = new(sync.WaitGroup)
+		pages    = new(sync.Mutex)
 	)
 
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	workersCtx, cancel := context.WithCancelCause(lease.Context())
+	defer cancel(nil)
 
 	const _workerCount = 100
 
@@ -100,14 +153,18 @@ func (s *service) SubscribeAllUsers(ctx context.Context, event *Event) (err erro
 			defer wg.Done()
 			for {
 				select {
-				case <-ctx.Done():
-					s.logger.Error("context canceled", zap.Error(ctx.Err()), zap.Int("id", event.ID))
+				case <-workersCtx.Done():
+					s.logger.Error("context canceled", zap.Error(context.Cause(workersCtx)), zap.Int("id", event.ID))
 					return
 				default:
-					res := s.processUsers(ctx, event.ID, event.Topic)
+					res := s.processUsers(workersCtx, lease, pages, event)
 					if errors.Is(res.Err, repomodel.ErrNotFound) {
 						return
 					}
+					if errors.Is(res.Err, cache.ErrLeaseLost) {
+						cancel(cache.ErrLeaseLost)
+						return
+					}
 					resultCh <- res
 				}
 			}
@@ -129,7 +186,10 @@ func (s *service) SubscribeAllUsers(ctx context.Context, event *Event) (err erro
 		}
 	}
 
-	event.Status = _active
+	if errors.Is(context.Cause(workersCtx), cache.ErrLeaseLost) {
+		return cache.ErrLeaseLost
+	}
+
 	event.ExtraData[_successCountKey] = strset.IntToStr(int(response.SuccessCount))
 	event.ExtraData[_failedCountKey] = strset.IntToStr(int(response.FailedCount))
 
@@ -137,14 +197,14 @@ func (s *service) SubscribeAllUsers(ctx context.Context, event *Event) (err erro
 		event.ExtraData[_failedReasonKey] = "most of the failed to subscribe users have inactive tokens"
 	}
 
-	_, err = s.eventRepo.Update(ctx, toRepo(event))
+	err = s.finishLoading(ctx, event, _ready)
 	if err != nil {
 		s.sentry.CaptureException(err)
 		s.logger.Error("cannot update event", zap.Error(err), zap.Int("eventID", event.ID))
 		return err
 	}
 
-	err = s.cache.Delete(ctx, ":process-users")
+	err = lease.Delete(ctx, _cursorKey)
 	if err != nil {
 		s.sentry.CaptureException(err)
 		s.logger.Error("err from Delete", zap.Error(err), zap.Int("eventID", event.ID))
@@ -156,48 +216,28 @@ func (s *service) SubscribeAllUsers(ctx context.Context, event *Event) (err erro
 	return nil
 }
 
-func (s *service) processUsers(ctx context.Context, eventID int, topic string) (result ChunkResult) {
-	var (
-		cacheKey = ":process-users"
-		lastID   int
-	)
+func (s *service) processUsers(ctx context.Context, lease *cache.Lease, pages *sync.Mutex, event *Event) (result ChunkResult) {
+	var eventID = event.ID
 
-	err := s.cache.Get(ctx, cacheKey, &lastID)
+	users, err := s.nextUsers(ctx, lease, pages, event)
 	if err != nil {
-		if !errors.Is(err, redis.Nil) {
-			s.logger.Error("err Get", zap.Error(err), zap.Int("eventID", eventID))
-			return ChunkResult{Err: err}
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.logger.Error("err from getting users", zap.Error(err), zap.Int("eventID", eventID))
 		}
+		return ChunkResult{Err: err}
 	}
 
-	users, err := s.userRepo.GetTokensWithLimit(ctx, lastID)
+	subscribers, err := s.withoutOptedOut(ctx, users)
 	if err != nil {
-		s.logger.Error("err from GetTokensByUserIDs", zap.Error(err), zap.Int("eventID", eventID))
+		s.logger.Error("err from withoutOptedOut", zap.Error(err), zap.Int("eventID", eventID))
 		return ChunkResult{Err: err}
 	}
 
-	topics, relations := s.groupUsersByTopic(topic, users)
+	topics, relations := s.groupUsersByTopic(event, subscribers)
 
-	for topicLang, tokens := range topics {
-		if len(tokens) == 0 {
-			continue
-		}
-
-		response, err := s.fcmTopicMan.SubscribeTokens(ctx, tokens, topicLang)
-		if err != nil {
-			s.logger.Error("err from SubscribeTokens", zap.Error(err), zap.String("topic", topicLang), zap.Int("eventID", eventID))
-			return ChunkResult{Err: err}
-		}
-
-		if response.Errors != nil {
-			for _, errDetail := range response.Errors {
-				if errDetail.Reason == _fcmTokenErr {
-					result.ErrCount++
-				}
-			}
-		}
-		result.SuccessCount += response.SuccessCount
-		result.FailedCount += response.FailureCount
+	result, err = s.subscribeTopics(ctx, event, topics, len(relations))
+	if err != nil {
+		return ChunkResult{Err: err}
 	}
 
 	_, err = s.userRepo.BatchInsert(ctx, eventID, relations)
@@ -206,12 +246,44 @@ func (s *service) processUsers(ctx context.Context, eventID int, topic string) (
 		return ChunkResult{Err: err}
 	}
 
-	lastID = users[len(users)-1].UserID
-	err = s.cache.Set(ctx, cacheKey, lastID, 0)
+	return result
+}
+
+// nextUsers takes the page of the users after the cursor of the event and moves the cursor, the pages
+// are taken by one worker at a time and the cursor is saved only while the lease of the event is held
+func (s *service) nextUsers(ctx context.Context, lease *cache.Lease, pages *sync.Mutex, event *Event) ([]userrepo.User, error) {
+	pages.Lock()
+	defer pages.Unlock()
+
+	var (
+		lastID int
+		users  []userrepo.User
+	)
+
+	err := lease.Get(ctx, _cursorKey, &lastID)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	// the users of the segment are loaded if the event has one, they are of the event country as well
+	if event.Segment != nil {
+		segment := event.Segment.toRepo()
+		if event.CountryID != 0 {
+			segment.CountryID = event.CountryID
+		}
+		users, err = s.userRepo.GetBySegment(ctx, segment, lastID, _usersPageSize)
+	} else {
+		users, err = s.userRepo.GetTokensWithLimit(ctx, lastID, event.CountryID)
+	}
 	if err != nil {
-		s.logger.Error("err Set", zap.Error(err), zap.Int("eventID", eventID))
-		return ChunkResult{Err: err}
+		return nil, err
 	}
 
-	return result
+	// the cursor is the last user of the page, even if the user opted out
+	err = lease.Set(ctx, _cursorKey, users[len(users)-1].UserID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
 }
@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/csv"
 	"errors"
+	"fmt"
+	"io"
 	"mime/multipart"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -14,6 +18,7 @@ import (
 	"notifications/internal/api/resp"
 	"notifications/internal/api/transport/broker/stream"
 	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/repo/outbox"
 	"notifications/internal/repo/repomodel"
 	userrepo "notifications/internal/repo/user"
 	"notifications/internal/service/admin"
@@ -21,11 +26,11 @@ import (
 	"notifications/pkg/util/strset"
 )
 
-func (s *service) LoadUsers(ctx context.Context, a admin.Admin, id int, file multipart.File, fileHeader *multipart.FileHeader) (*Event, error) {
+func (s *service) LoadUsers(ctx context.Context, a admin.Admin, id int, file multipart.File, fileHeader *multipart.FileHeader) (_ *Event, err error) {
 	fileExt := fileman.GetFileExt(fileHeader.Filename)
-	if fileExt != fileman.Csv {
+	if fileExt != fileman.Csv && fileExt != fileman.Xlsx {
 		s.logger.Warning("invalid file extension", zap.String("fileName", fileHeader.Filename), zap.Int("id", id))
-		return nil, resp.Wrap(resp.ErrBadRequest, "incorrect file type")
+		return nil, resp.Wrap(resp.ErrBadRequest, "incorrect file type, csv or xlsx is expected")
 	}
 
 	selectedEvent, err := s.eventRepo.GetByID(ctx, id)
@@ -35,28 +40,34 @@ func (s *service) LoadUsers(ctx context.Context, a admin.Admin, id int, file mul
 			s.logger.Error("err occurred during getting event", zap.Error(err), zap.Int("id", id))
 			return nil, err
 		}
-		return nil, resp.Wrap(resp.ErrNotFound, "event not found or not active")
+		return nil, resp.Wrap(resp.ErrNotFound, "event not found or finished")
+	}
+
+	err = checkStatus(selectedEvent, "load users for", _scheduled, _ready)
+	if err != nil {
+		return nil, err
 	}
 
 	var oldEvent = *selectedEvent
 
-	csvReader := csv.NewReader(file)
-	records, err := csvReader.ReadAll()
+	// only the header is read to reject the file without the audience column, the rows are read by SubscribeUsers
+	rows, closeSheet, err := openSheet(file, fileExt)
 	if err != nil {
-		s.logger.Warning("cannot read csv header", zap.Error(err), zap.Int("id", id))
-		return nil, resp.Wrap(resp.ErrBadRequest, "cannot read csv file")
+		s.logger.Warning("cannot open file", zap.Error(err), zap.Int("id", id))
+		return nil, resp.Wrap(resp.ErrBadRequest, "cannot read file")
 	}
+	defer closeSheet()
 
-	if len(records) == 0 {
-		s.logger.Warning("csv file is empty", zap.Int("id", id))
-		return nil, resp.Wrap(resp.ErrBadRequest, "csv file is empty")
+	header, err := rows.Read()
+	if err != nil {
+		s.logger.Warning("cannot read file header", zap.Error(err), zap.Int("id", id))
+		return nil, resp.Wrap(resp.ErrBadRequest, "file is empty or invalid")
 	}
 
-	header := records[0]
-	// if csv file has more headers but userID, it means file is invalid
-	if len(header) != 1 || header[0] != _userIDCsvHeader {
-		s.logger.Warning("incorrect csv header", zap.Any("header", header), zap.Int("id", id))
-		return nil, resp.Wrap(resp.ErrBadRequest, "invalid csv header")
+	_, _, err = detectColumn(header)
+	if err != nil {
+		s.logger.Warning("incorrect file header", zap.Any("header", header), zap.Int("id", id))
+		return nil, err
 	}
 
 	var cacheKey = _topicSubCacheKey + strset.IntToStr(id)
@@ -65,9 +76,23 @@ func (s *service) LoadUsers(ctx context.Context, a admin.Admin, id int, file mul
 		return nil, resp.Wrap(resp.ErrBadRequest, "user loading in process")
 	}
 
-	data := records[1:]
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("cannot rewind file", zap.Error(err), zap.Int("id", id))
+		return nil, err
+	}
+
+	var fileName = strset.IntToStr(id) + "." + fileExt
 
-	err = s.cache.SetObj(ctx, cacheKey, data, 0)
+	err = s.fileManager.Upload(file, &s.bucket, s.audienceDirectory, fileName)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("cannot upload file", zap.Error(err), zap.Int("id", id))
+		return nil, err
+	}
+
+	err = s.cache.Set(ctx, cacheKey, fileName, 0)
 	if err != nil {
 		s.sentry.CaptureException(err)
 		s.logger.Error("cannot save data in cache", zap.Error(err), zap.Int("id", id))
@@ -78,25 +103,55 @@ func (s *service) LoadUsers(ctx context.Context, a admin.Admin, id int, file mul
 	delete(selectedEvent.ExtraData, _successCountKey)
 	delete(selectedEvent.ExtraData, _failedCountKey)
 	delete(selectedEvent.ExtraData, _failedReasonKey)
-	selectedEvent.Status = _loadingUsers
+	delete(selectedEvent.ExtraData, _rejectedCountKey)
+	delete(selectedEvent.ExtraData, _rejectedReportKey)
 
 	event.toService(selectedEvent)
 	event.SubscribeAll = false
-	err = s.nats.Publish(stream.Notifications, subject.NotificationsTopicUsersSubscribed, event)
+	event.AudienceFile = fileName
+
+	tx := s.transactor.New()
+	err = tx.Begin(ctx)
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("cannot publish message", zap.Error(err), zap.Int("id", id))
+		s.logger.Error("err on tx.Begin", zap.Error(err))
 		return nil, err
 	}
 
-	_, err = s.eventRepo.Update(ctx, selectedEvent)
+	defer func() {
+		if err != nil {
+			if errX := tx.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	_, err = tx.EventRepo().Update(ctx, selectedEvent)
 	if err != nil {
 		s.sentry.CaptureException(err)
 		s.logger.Error("cannot update event", zap.Error(err), zap.Int("id", id))
 		return nil, err
 	}
 
-	err = s.nats.Publish(stream.Audit, subject.AuditAdd, admin.Audit{
+	err = s.transition(ctx, tx, a, selectedEvent, _loadingUsers)
+	if err != nil {
+		return nil, err
+	}
+	event.Status = selectedEvent.Status
+
+	err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Notifications, subject.NotificationsTopicUsersSubscribed, event)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("cannot save message to outbox", zap.Error(err), zap.Int("id", id))
+		return nil, err
+	}
+
+	err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
 		AdminId:   a.ID,
 		IpAddress: a.IP,
 		EventName: admin.LoadUsersEvent,
@@ -106,7 +161,8 @@ func (s *service) LoadUsers(ctx context.Context, a admin.Admin, id int, file mul
 	})
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("failed to publish audit event", zap.Error(err))
+		s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+		return nil, err
 	}
 
 	return event, nil
@@ -115,31 +171,55 @@ func (s *service) LoadUsers(ctx context.Context, a admin.Admin, id int, file mul
 func (s *service) SubscribeUsers(ctx context.Context, event *Event) (err error) {
 	s.logger.Info("SubscribeUsers start", zap.Int("eventID", event.ID))
 
-	var (
-		data     [][]string
-		cacheKey = _topicSubCacheKey + strset.IntToStr(event.ID)
-	)
+	var cacheKey = _topicSubCacheKey + strset.IntToStr(event.ID)
 
 	defer func() {
 		if err != nil {
-			event.Status = _failedLoading
-			event.ExtraData["reason"] = err.Error()
-			_, sErr := s.eventRepo.Update(ctx, toRepo(event))
-			err = errors.Join(err, sErr)
+			event.ExtraData[_reasonKey] = err.Error()
+			sErr := s.finishLoading(ctx, event, _scheduled)
+			err = errors.Join(err, sErr, s.cache.Delete(ctx, cacheKey))
 		}
 	}()
 
-	err = s.cache.Get(context.Background(), cacheKey, &data)
+	file, err := s.fileManager.Download(&s.bucket, s.audienceDirectory, event.AudienceFile)
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("cannot get data from cache", zap.Error(err), zap.Int("eventID", event.ID))
+		s.logger.Error("cannot download file", zap.Error(err), zap.Int("eventID", event.ID))
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	rows, closeSheet, err := openSheet(file, fileman.GetFileExt(event.AudienceFile))
+	if err != nil {
+		s.logger.Error("cannot open file", zap.Error(err), zap.Int("eventID", event.ID))
+		return err
+	}
+	defer closeSheet()
+
+	header, err := rows.Read()
+	if err != nil {
+		s.logger.Error("cannot read file header", zap.Error(err), zap.Int("eventID", event.ID))
+		return err
+	}
+
+	column, columnIdx, err := detectColumn(header)
+	if err != nil {
 		return err
 	}
 
+	report, err := newRejectedReport()
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("cannot create rejected report", zap.Error(err), zap.Int("eventID", event.ID))
+		return err
+	}
+	defer report.close()
+
 	var (
-		jobCh    = make(chan []string)
+		jobCh    = make(chan []audienceRow)
 		resultCh = make(chan ChunkResult)
 		wg       = new(sync.WaitGroup)
+		readErr  error
 	)
 
 	const (
@@ -150,18 +230,36 @@ func (s *service) SubscribeUsers(ctx context.Context, event *Event) (err error)
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// By reading and processing the CSV file in chunks, we never hold more rows in memory than necessary
+	// The rows are streamed from the file in chunks, we never hold more rows in memory than necessary
 	// Only a single chunk is in memory (plus whatever our workers hold) at any time
 	go func() {
 		defer close(jobCh)
-		var batch = make([]string, 0, _chunkSize)
+		var (
+			batch = make([]audienceRow, 0, _chunkSize)
+			line  = 1
+		)
+
+		for {
+			record, err := rows.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			line++
+			if err != nil {
+				var parseErr *csv.ParseError
+				if !errors.As(err, &parseErr) {
+					readErr = err
+					return
+				}
+				batch = append(batch, audienceRow{Line: line})
+				continue
+			}
 
-		for _, record := range data {
-			if len(record) == 0 {
+			if columnIdx >= len(record) || strset.IsEmpty(strings.Join(record, "")) {
 				continue
 			}
 
-			batch = append(batch, record[0])
+			batch = append(batch, audienceRow{Line: line, Value: record[columnIdx]})
 			// if the batch is equal to chunkSize (1000), send it to the workers and reset the batch
 			if len(batch) == _chunkSize {
 				select {
@@ -170,7 +268,7 @@ func (s *service) SubscribeUsers(ctx context.Context, event *Event) (err error)
 					return
 				case jobCh <- batch:
 				}
-				batch = make([]string, 0, _chunkSize)
+				batch = make([]audienceRow, 0, _chunkSize)
 			}
 		}
 		// if there are records left in the batch or the records are less than the chunkSize, send them to the workers
@@ -192,7 +290,7 @@ func (s *service) SubscribeUsers(ctx context.Context, event *Event) (err error)
 					if !ok {
 						return
 					}
-					resultCh <- s.processChunk(ctx, event.ID, event.Topic, chunk)
+					resultCh <- s.processChunk(ctx, event, column, chunk)
 				}
 			}
 		}()
@@ -203,7 +301,10 @@ func (s *service) SubscribeUsers(ctx context.Context, event *Event) (err error)
 		close(resultCh)
 	}()
 
-	var response PrepareUsersResponse
+	var (
+		response  PrepareUsersResponse
+		reportErr error
+	)
 
 	for res := range resultCh {
 		if res.Err == nil {
@@ -211,17 +312,38 @@ func (s *service) SubscribeUsers(ctx context.Context, event *Event) (err error)
 			atomic.AddInt64(&response.FailedCount, int64(res.FailedCount))
 			atomic.AddInt64(&response.ErrCount, int64(res.ErrCount))
 		}
+		if reportErr == nil {
+			reportErr = report.write(res.Rejected)
+		}
+	}
+
+	if readErr != nil {
+		s.logger.Error("cannot read file", zap.Error(readErr), zap.Int("eventID", event.ID))
+		return readErr
 	}
 
-	event.Status = _active
 	event.ExtraData[_successCountKey] = strset.IntToStr(int(response.SuccessCount))
 	event.ExtraData[_failedCountKey] = strset.IntToStr(int(response.FailedCount))
+	event.ExtraData[_rejectedCountKey] = strset.IntToStr(report.count)
 
 	if response.ErrCount > (response.FailedCount / 2) {
 		event.ExtraData[_failedReasonKey] = "most of the failed to subscribe users have inactive tokens"
 	}
 
-	_, err = s.eventRepo.Update(ctx, toRepo(event))
+	if report.count > 0 {
+		var reportName = strset.IntToStr(event.ID) + _rejectedPostfix
+		if reportErr == nil {
+			reportErr = report.upload(s.fileManager, &s.bucket, s.audienceDirectory, reportName)
+		}
+		if reportErr != nil {
+			s.sentry.CaptureException(reportErr)
+			s.logger.Error("cannot save rejected report", zap.Error(reportErr), zap.Int("eventID", event.ID))
+		} else {
+			event.ExtraData[_rejectedReportKey] = reportName
+		}
+	}
+
+	err = s.finishLoading(ctx, event, _ready)
 	if err != nil {
 		s.sentry.CaptureException(err)
 		s.logger.Error("cannot update event", zap.Error(err), zap.Int("eventID", event.ID))
@@ -235,66 +357,231 @@ func (s *service) SubscribeUsers(ctx context.Context, event *Event) (err error)
 		return err
 	}
 
-	s.logger.Info("SubscribeUsers end", zap.Int("eventID", event.ID))
+	// the imported file has the personal data, only the report of the rejected rows is kept
+	err = s.fileManager.Remove(&s.bucket, s.audienceDirectory, event.AudienceFile)
+	if err != nil {
+		s.logger.Warning("cannot remove imported file", zap.Error(err), zap.Int("eventID", event.ID))
+	}
+
+	s.logger.Info("SubscribeUsers end", zap.Int("eventID", event.ID), zap.Int("rejected", report.count))
 
 	return nil
 }
 
-func (s *service) processChunk(ctx context.Context, eventID int, topic string, chunk []string) (result ChunkResult) {
-	users, err := s.userRepo.GetTokensByUserIDs(ctx, chunk)
-	if err != nil {
-		s.logger.Error("err from GetTokensByUserIDs", zap.Error(err), zap.Int("eventID", eventID))
+func (s *service) processChunk(ctx context.Context, event *Event, column string, chunk []audienceRow) (result ChunkResult) {
+	var (
+		rows   = make([]audienceRow, 0, len(chunk))
+		values = make([]string, 0, len(chunk))
+	)
+
+	for _, row := range chunk {
+		value, ok := s.normalize(column, row.Value, event.CountryID)
+		if !ok {
+			result.Rejected = append(result.Rejected, rejectedRow{audienceRow: row, Reason: _rejectedInvalid})
+			continue
+		}
+		rows = append(rows, audienceRow{Line: row.Line, Value: value})
+		values = append(values, value)
+	}
+
+	users, err := s.userRepo.GetByColumn(ctx, column, values)
+	if err != nil && !errors.Is(err, repomodel.ErrNotFound) {
+		s.logger.Error("err from GetByColumn", zap.Error(err), zap.Int("eventID", event.ID))
 		return ChunkResult{Err: err}
 	}
 
-	topics, relations := s.groupUsersByTopic(topic, users)
+	// the phone of the deleted user may belong to the new one, the user who isn't deleted is preferred
+	var byValue = make(map[string]*userrepo.User, len(users))
+	for _, u := range users {
+		value := columnValue(column, u)
+		if prev, ok := byValue[value]; !ok || prev.Status == _deletedUser {
+			byValue[value] = u
+		}
+	}
 
-	for topicLang, tokens := range topics {
-		if len(tokens) == 0 {
-			continue
+	var (
+		candidates = make([]userrepo.User, 0, len(rows))
+		userRows   = make(map[int][]audienceRow, len(rows))
+	)
+
+	for _, row := range rows {
+		u, ok := byValue[row.Value]
+		switch {
+		case !ok:
+			result.Rejected = append(result.Rejected, rejectedRow{audienceRow: row, Reason: _rejectedNotFound})
+		case u.Status == _deletedUser:
+			result.Rejected = append(result.Rejected, rejectedRow{audienceRow: row, Reason: _rejectedDeleted})
+		case event.CountryID != 0 && u.CountryID != event.CountryID:
+			// the users of the other countries are skipped if the event targets a country
+			result.Rejected = append(result.Rejected, rejectedRow{audienceRow: row, Reason: _rejectedOtherCountry})
+		case len(u.ActiveDevices()) == 0:
+			result.Rejected = append(result.Rejected, rejectedRow{audienceRow: row, Reason: _rejectedNoToken})
+		default:
+			if _, ok = userRows[u.UserID]; !ok {
+				candidates = append(candidates, *u)
+			}
+			userRows[u.UserID] = append(userRows[u.UserID], row)
 		}
+	}
 
-		response, err := s.fcmTopicMan.SubscribeTokens(ctx, tokens, topicLang)
-		if err != nil {
-			s.logger.Error("err from SubscribeTokens", zap.Error(err), zap.String("topic", topicLang), zap.Int("eventID", eventID))
-			return ChunkResult{Err: err}
+	subscribers, err := s.withoutOptedOut(ctx, candidates)
+	if err != nil {
+		s.logger.Error("err from withoutOptedOut", zap.Error(err), zap.Int("eventID", event.ID))
+		return ChunkResult{Err: err}
+	}
+
+	for _, u := range subscribers {
+		delete(userRows, u.UserID)
+	}
+	for _, optedOut := range userRows {
+		for _, row := range optedOut {
+			result.Rejected = append(result.Rejected, rejectedRow{audienceRow: row, Reason: _rejectedOptedOut})
 		}
+	}
+
+	topics, relations := s.groupUsersByTopic(event, subscribers)
+
+	subscribed, err := s.subscribeTopics(ctx, event, topics, len(relations))
+	if err != nil {
+		return ChunkResult{Err: err, Rejected: result.Rejected}
+	}
+	result.SuccessCount, result.FailedCount, result.ErrCount = subscribed.SuccessCount, subscribed.FailedCount, subscribed.ErrCount
+
+	if len(relations) == 0 {
+		return result
+	}
+
+	_, err = s.userRepo.BatchInsert(ctx, event.ID, relations)
+	if err != nil {
+		s.logger.Error("err from BatchInsert", zap.Error(err), zap.Int("eventID", event.ID))
+		return ChunkResult{Err: err, Rejected: result.Rejected}
+	}
+
+	return result
+}
 
-		if response.Errors != nil {
-			for _, errDetail := range response.Errors {
-				if errDetail.Reason == _fcmTokenErr {
-					result.ErrCount++
+// columnValue returns the value of the audience column of the user
+func columnValue(column string, u *userrepo.User) string {
+	switch column {
+	case userrepo.ColumnPhone:
+		return u.Phone
+	case userrepo.ColumnPersonExternalRef:
+		return u.PersonExternalRef
+	default:
+		return strset.IntToStr(u.UserID)
+	}
+}
+
+// withoutOptedOut removes the users who opted out of marketing pushes, events are always marketing
+func (s *service) withoutOptedOut(ctx context.Context, users []userrepo.User) ([]userrepo.User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+
+	var userIDs = make([]int, 0, len(users))
+	for _, u := range users {
+		userIDs = append(userIDs, u.UserID)
+	}
+
+	optedOut, err := s.userRepo.GetOptedOutUserIDs(ctx, userIDs, userrepo.CategoryMarketing, userrepo.ChannelPush)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(optedOut) == 0 {
+		return users, nil
+	}
+
+	return slices.DeleteFunc(slices.Clone(users), func(u userrepo.User) bool {
+		return slices.Contains(optedOut, u.UserID)
+	}), nil
+}
+
+// subscribeTopics subscribes the tokens of the devices to the topics, the users of the event sent directly are not subscribed
+// and count as succeeded. The devices of the providers without topics are skipped
+func (s *service) subscribeTopics(ctx context.Context, event *Event, topics map[string][]userrepo.DeviceToken, users int) (result ChunkResult, err error) {
+	if event.Delivery == _directDelivery {
+		result.SuccessCount = users
+		return result, nil
+	}
+
+	for topicLang, devices := range topics {
+		for provider, tokens := range userrepo.ByProvider(devices) {
+			if !userrepo.HasTopics(provider) {
+				continue
+			}
+
+			// the users of the page may have more devices than the tokens subscribed at once
+			for batch := range slices.Chunk(tokens, _topicTokensLimit) {
+				var res ChunkResult
+				switch provider {
+				case userrepo.ProviderHms:
+					res, err = s.subscribeHms(ctx, batch, topicLang)
+				default:
+					res, err = s.subscribeFcm(ctx, batch, topicLang)
+				}
+				if err != nil {
+					s.logger.Error("err from subscribing to topic", zap.Error(err), zap.String("topic", topicLang), zap.String("provider", provider), zap.Int("eventID", event.ID))
+					return ChunkResult{}, err
 				}
+
+				result.SuccessCount += res.SuccessCount
+				result.FailedCount += res.FailedCount
+				result.ErrCount += res.ErrCount
 			}
 		}
-		result.SuccessCount += response.SuccessCount
-		result.FailedCount += response.FailureCount
 	}
 
-	_, err = s.userRepo.BatchInsert(ctx, eventID, relations)
+	return result, nil
+}
+
+func (s *service) subscribeFcm(ctx context.Context, tokens []string, topic string) (result ChunkResult, err error) {
+	response, err := s.fcmTopicMan.SubscribeTokens(ctx, tokens, topic)
 	if err != nil {
-		s.logger.Error("err from BatchInsert", zap.Error(err), zap.Int("eventID", eventID))
-		return ChunkResult{Err: err}
+		return result, err
 	}
 
-	return result
+	for _, errDetail := range response.Errors {
+		if errDetail.Reason == _fcmTokenErr {
+			result.ErrCount++
+		}
+	}
+	result.SuccessCount = response.SuccessCount
+	result.FailedCount = response.FailureCount
+
+	return result, nil
+}
+
+func (s *service) subscribeHms(ctx context.Context, tokens []string, topic string) (result ChunkResult, err error) {
+	response, err := s.hmsTopicMan.Subscribe(ctx, tokens, topic)
+	if err != nil {
+		return result, err
+	}
+
+	result.SuccessCount = response.SuccessCount
+	result.FailedCount = response.FailureCount
+
+	return result, nil
 }
 
-func (s *service) groupUsersByTopic(baseTopic string, users []userrepo.User) (map[string][]string, []userrepo.EventRelation) {
+func (s *service) groupUsersByTopic(event *Event, users []userrepo.User) (map[string][]userrepo.DeviceToken, []userrepo.EventRelation) {
 	var (
-		topics    = make(map[string][]string)
+		topics    = make(map[string][]userrepo.DeviceToken)
 		relations = make([]userrepo.EventRelation, 0, len(users))
 	)
 
 	for _, user := range users {
-		if strset.IsEmpty(user.Token) {
+		devices := user.ActiveDevices()
+		if len(devices) == 0 {
 			continue
 		}
-		userTopic := buildTopic(baseTopic, user.Language)
-		topics[userTopic] = append(topics[userTopic], user.Token)
+		variant := event.ABTest.bucket(event.ID, user.UserID)
+		userTopic := buildTopic(variantTopic(event.Topic, variant), user.Language)
+		topics[userTopic] = append(topics[userTopic], devices...)
 		relations = append(relations, userrepo.EventRelation{
-			UserID: user.UserID,
-			Lang:   user.Language,
+			UserID:  user.UserID,
+			Lang:    user.Language,
+			Variant: variant,
 		})
 	}
 
@@ -1,6 +1,7 @@
 package event
 
 import (
+	"math"
 	"sync"
 	"time"
 
@@ -8,7 +9,9 @@ import (
 
 	"notifications/internal/lib/language"
 	"notifications/internal/repo/event"
+	"notifications/internal/repo/user"
 	"notifications/pkg/lib/notifier/firebase"
+	"notifications/pkg/util/strset"
 )
 
 const (
@@ -16,24 +19,83 @@ const (
 	_dotDelim        = "."
 	_empty           = ""
 	_topicRegex      = "[a-zA-Z0-9-_.~%]+"
+	_variantRegex    = "^[a-z0-9]{1,16}$"
 )
 
+// Statuses of the event, the transitions between them are in _transitions
 const (
-	_active        = "active"
-	_sent          = "sent"
-	_failed        = "failed"
-	_failedLoading = "failed_loading"
-	_loadingUsers  = "loading_users"
-	_draft         = "draft"
+	_draft           = "draft"
+	_pendingApproval = "pending_approval"
+	_scheduled       = "scheduled"
+	_loadingUsers    = "loading_users"
+	_ready           = "ready"
+	_sending         = "sending"
+	_sent            = "sent"
+	_failed          = "failed"
+	_cancelled       = "cancelled"
 )
 
 const (
 	_successCountKey = "successCount"
 	_failedCountKey  = "failedCount"
 	_failedReasonKey = "failedReason"
+	_reasonKey       = "reason"
 )
 
-const _userIDCsvHeader = "userID"
+// Delivery modes of the event, the event is sent to the topics the users are subscribed to when they are loaded
+// or directly to their tokens in batches
+const (
+	_topicDelivery  = "topic"
+	_directDelivery = "direct"
+)
+
+// Stages of the run, the users are processed in the pages with a checkpoint after every page, then the event
+// is sent to the topics and the run is finished
+const (
+	_running     = "running"
+	_stageUsers  = "users"
+	_stageSend   = "send"
+	_stageFinish = "finish"
+	// the run without a checkpoint for _staleRun is taken over by RunJob, up to _maxRunAttempts times
+	_staleRun       = 5 * time.Minute
+	_maxRunAttempts = 3
+)
+
+// Rejection reasons of the rows of the imported audience
+const (
+	_rejectedInvalid      = "invalid"
+	_rejectedNotFound     = "not_found"
+	_rejectedDeleted      = "deleted"
+	_rejectedNoToken      = "no_token"
+	_rejectedOtherCountry = "other_country"
+	_rejectedOptedOut     = "opted_out"
+)
+
+const (
+	_rejectedCountKey  = "rejectedCount"
+	_rejectedReportKey = "rejectedReport"
+	_rejectedPostfix   = "_rejected.csv"
+	_deletedUser       = "deleted"
+)
+
+// A/B test, the holdout is the users out of the percents of the variants, they get the winner
+const (
+	_holdout     = "holdout"
+	_maxVariants = 10
+	_winnerKey   = "winner"
+	_sentAtKey   = "sentAt"
+)
+
+// Leases of the jobs and of the loading of the users of the event, they are renewed while they are held
+const (
+	_jobLease  = "job:event-run"
+	_loadLease = "event:%d:users"
+	_leaseTTL  = 30 * time.Second
+	_cursorKey = "cursor"
+)
+
+// _usersPageSize is the number of the users loaded at once, the same as of GetTokensWithLimit
+const _usersPageSize = 1000
 
 const (
 	_title              = "title"
@@ -47,9 +109,17 @@ const (
 	_cashBackID         = "cashBackID"
 	_cashBackCategoryID = "cashBackCategoryID"
 	_badge              = "badge"
+	// _eventID and _language are reported back by the app to track the event
+	_eventID  = "eventID"
+	_language = "language"
+	_variant  = "variant"
+	_runID    = "runID"
 )
 
 const _topicSubCacheKey = ":topic-subscription:"
+
+// _topicTokensLimit is the max number of the tokens subscribed to the topic at once
+const _topicTokensLimit = 1000
 const _fcmTokenErr = "NOT_FOUND"
 
 type Message struct {
@@ -61,9 +131,22 @@ type ChunkResult struct {
 	SuccessCount int
 	FailedCount  int
 	ErrCount     int
+	Rejected     []rejectedRow
 	Err          error
 }
 
+// audienceRow refers to the user by the value of the audience column of the line of the imported file
+type audienceRow struct {
+	Line  int
+	Value string
+}
+
+// rejectedRow is the row of the imported file the user of which is not subscribed
+type rejectedRow struct {
+	audienceRow
+	Reason string
+}
+
 type PrepareUsersResponse struct {
 	SuccessCount int64
 	FailedCount  int64
@@ -77,11 +160,12 @@ type FailedResponse struct {
 }
 
 type Filter struct {
-	Topic  string
-	Status string
-	ID     uint
-	Limit  uint
-	Offset uint
+	Topic     string
+	Status    string
+	ID        uint
+	Limit     uint
+	Offset    uint
+	CountryID int8
 }
 
 type Event struct {
@@ -97,8 +181,17 @@ type Event struct {
 	CreatedAt   time.Time         `json:"createdAt"`
 	UpdatedAt   time.Time         `json:"updatedAt"`
 	ScheduledAt time.Time         `json:"scheduledAt"`
+	CountryID   int8              `json:"countryID"`
+	Segment     *Segment          `json:"segment,omitempty"`
+	ABTest      *ABTest           `json:"abTest,omitempty"`
+	Recurrence  *Recurrence       `json:"recurrence,omitempty"`
+	SubmittedBy int               `json:"submittedBy,omitempty"`
+	ApprovedBy  int               `json:"approvedBy,omitempty"`
+	Delivery    string            `json:"delivery"`
 
 	SubscribeAll bool `json:"subscribeAll,omitempty"`
+	// AudienceFile is the name of the uploaded file the users are imported from
+	AudienceFile string `json:"audienceFile,omitempty"`
 }
 
 type Request struct {
@@ -112,13 +205,119 @@ type Request struct {
 	ScheduledAt     string
 	ScheduledAtTime time.Time
 	ExtraData       map[string]string
+	CountryID       int8
+	// Segment replaces the segment of the event, the empty one removes it
+	Segment *Segment
+	// ABTest replaces the A/B test of the event, the one without variants removes it
+	ABTest *ABTest
+	// Recurrence replaces the recurrence of the event, the one without cron expression removes it
+	Recurrence *Recurrence
+	// Delivery is topic or direct, topic by default
+	Delivery string
+}
+
+// Recurrence runs the event at the occurrences of the cron expression from the scheduled time, they are
+// in the timezone of the country of the event, UTC if it targets all the countries
+type Recurrence struct {
+	Cron           string     `json:"cron"`
+	EndAt          *time.Time `json:"endAt,omitempty"`
+	MaxOccurrences int        `json:"maxOccurrences,omitempty"`
+}
+
+// Run is the send of the event, the counts are of the messages to the topics or of the users
+// if the event is sent directly
+type Run struct {
+	ID           int       `json:"id"`
+	EventID      int       `json:"eventID"`
+	Number       int       `json:"number"`
+	Status       string    `json:"status"`
+	SuccessCount int       `json:"successCount"`
+	FailedCount  int       `json:"failedCount"`
+	Error        string    `json:"error,omitempty"`
+	ScheduledAt  time.Time `json:"scheduledAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// RunResponse is the result of the run, Result has the responses of the messages to the topics
+type RunResponse struct {
+	RunID        int   `json:"runID"`
+	SuccessCount int   `json:"successCount"`
+	FailedCount  int   `json:"failedCount"`
+	Result       []any `json:"result"`
+}
+
+// Progress is the progress of the run, ETA is estimated by the rate of the processed users
+type Progress struct {
+	RunID        int        `json:"runID"`
+	EventID      int        `json:"eventID"`
+	Status       string     `json:"status"`
+	Stage        string     `json:"stage"`
+	Total        int        `json:"total"`
+	Processed    int        `json:"processed"`
+	Percent      float64    `json:"percent"`
+	SuccessCount int        `json:"successCount"`
+	FailedCount  int        `json:"failedCount"`
+	Attempts     int        `json:"attempts"`
+	Error        string     `json:"error,omitempty"`
+	StartedAt    time.Time  `json:"startedAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	ETA          *time.Time `json:"eta,omitempty"`
+}
+
+// Delivery is the result of the direct send of the event to the user
+type Delivery struct {
+	RunID        int       `json:"runID"`
+	UserID       int       `json:"userID"`
+	Status       string    `json:"status"`
+	FcmMessageID string    `json:"fcmMessageID,omitempty"`
+	ErrorCode    string    `json:"errorCode,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// ABTest splits the audience of the event between the variants of the texts by the percents,
+// the users out of the percents are the holdout which gets the winner
+type ABTest struct {
+	Variants []Variant `json:"variants"`
+	// WinnerAfter is the number of minutes after the send the winner is picked and sent to the holdout,
+	// 0 if it's picked manually
+	WinnerAfter int `json:"winnerAfter,omitempty"`
+}
+
+type Variant struct {
+	Key     string            `json:"key"`
+	Percent int               `json:"percent"`
+	Title   language.Language `json:"title"`
+	Body    language.Language `json:"body"`
+	Image   language.Language `json:"image"`
+}
+
+// WinnerRequest picks the variant sent to the holdout, the empty key picks the one with the best click rate
+type WinnerRequest struct {
+	Variant string
+}
+
+// Segment is the rule-based audience of the event, the users matching all the rules are loaded
+type Segment struct {
+	CountryID     int8       `json:"countryID,omitempty"`
+	Languages     []string   `json:"languages,omitempty"`
+	Statuses      []string   `json:"statuses,omitempty"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+	PushEnabled   *bool      `json:"pushEnabled,omitempty"`
+}
+
+// Estimate is the audience size of the segment, reachable users have a token and didn't opt out of marketing pushes
+type Estimate struct {
+	Total     int `json:"total"`
+	Reachable int `json:"reachable"`
 }
 
-func setupMessages(event *event.Event) []*messaging.Message {
+// setupMessages builds the messages to the topics of the languages, the messages of the A/B test variant
+// are sent to the topics of the variant with its texts
+func setupMessages(event *event.Event, languages []string, variant string, runID int) []*messaging.Message {
 	var (
-		languages = language.GetAll()
-		msgCh     = make(chan *messaging.Message, len(languages))
-		wg        sync.WaitGroup
+		msgCh = make(chan *messaging.Message, len(languages))
+		wg    sync.WaitGroup
 	)
 
 	for _, lang := range languages {
@@ -126,24 +325,11 @@ func setupMessages(event *event.Event) []*messaging.Message {
 		go func() {
 			defer wg.Done()
 
-			data := make(map[string]string)
-			data[_title] = event.Title.Get(lang)
-			data[_comment] = event.Title.Get(lang)
-			data[_message] = event.Body.Get(lang)
-			data[_image] = event.Image.Get(lang)
-			data[_category] = event.Category
-			data[_button] = event.Link
-			data[_sectionName] = event.ExtraData[_sectionName]
-			data[_serviceID] = event.ExtraData[_serviceID]
-			data[_cashBackID] = event.ExtraData[_cashBackID]
-			data[_cashBackCategoryID] = event.ExtraData[_cashBackCategoryID]
-			data[_badge] = event.ExtraData[_badge]
-
 			message := new(messaging.Message)
-			message.Data = data
-			message.Topic = buildTopic(event.Topic, lang)
-			firebase.AndroidMSG(message, data, firebase.AndroidNormalPriority)
-			firebase.IosMSG(message, data, firebase.ApnsNormalPriority)
+			message.Data = pushData(event, lang, variant, runID)
+			message.Topic = buildTopic(variantTopic(event.Topic, variant), lang)
+			firebase.AndroidMSG(message, message.Data, firebase.AndroidNormalPriority)
+			firebase.IosMSG(message, message.Data, firebase.ApnsNormalPriority)
 
 			msgCh <- message
 		}()
@@ -162,6 +348,50 @@ func setupMessages(event *event.Event) []*messaging.Message {
 	return messages
 }
 
+// multicastMessage builds the message of the language and the variant to the tokens of the users
+// of the event sent directly
+func multicastMessage(event *event.Event, lang, variant string, runID int, tokens []string) *messaging.MulticastMessage {
+	message := new(messaging.Message)
+	message.Data = pushData(event, lang, variant, runID)
+	firebase.AndroidMSG(message, message.Data, firebase.AndroidNormalPriority)
+	firebase.IosMSG(message, message.Data, firebase.ApnsNormalPriority)
+
+	return &messaging.MulticastMessage{
+		Tokens:  tokens,
+		Data:    message.Data,
+		Android: message.Android,
+		APNS:    message.APNS,
+	}
+}
+
+// pushData is the data of the push of the event in the language with the texts of the variant
+func pushData(event *event.Event, lang, variant string, runID int) map[string]string {
+	title, body, image := texts(event, variant)
+
+	data := make(map[string]string)
+	data[_title] = title.Get(lang)
+	data[_comment] = title.Get(lang)
+	data[_message] = body.Get(lang)
+	data[_image] = image.Get(lang)
+	data[_category] = event.Category
+	data[_button] = event.Link
+	data[_sectionName] = event.ExtraData[_sectionName]
+	data[_serviceID] = event.ExtraData[_serviceID]
+	data[_cashBackID] = event.ExtraData[_cashBackID]
+	data[_cashBackCategoryID] = event.ExtraData[_cashBackCategoryID]
+	data[_badge] = event.ExtraData[_badge]
+	data[_eventID] = strset.IntToStr(event.ID)
+	data[_language] = lang
+	if variant != _empty {
+		data[_variant] = variant
+	}
+	if runID != 0 {
+		data[_runID] = strset.IntToStr(runID)
+	}
+
+	return data
+}
+
 func (e *Event) toService(event *event.Event) {
 	e.ID = event.ID
 	e.Topic = event.Topic
@@ -175,6 +405,13 @@ func (e *Event) toService(event *event.Event) {
 	e.ScheduledAt = event.ScheduledAt
 	e.CreatedAt = event.CreatedAt
 	e.UpdatedAt = event.UpdatedAt
+	e.CountryID = event.CountryID
+	e.Segment = segmentToService(event.Segment)
+	e.ABTest = abTestToService(event.ABTest)
+	e.Recurrence = recurrenceToService(event.Recurrence)
+	e.SubmittedBy = event.SubmittedBy
+	e.ApprovedBy = event.ApprovedBy
+	e.Delivery = event.Delivery
 }
 
 func toRepo(e *Event) *event.Event {
@@ -191,5 +428,148 @@ func toRepo(e *Event) *event.Event {
 		ScheduledAt: e.ScheduledAt,
 		CreatedAt:   e.CreatedAt,
 		UpdatedAt:   e.UpdatedAt,
+		CountryID:   e.CountryID,
+		Segment:     segmentToRepo(e.Segment),
+		ABTest:      abTestToRepo(e.ABTest),
+		Recurrence:  recurrenceToRepo(e.Recurrence),
+		SubmittedBy: e.SubmittedBy,
+		ApprovedBy:  e.ApprovedBy,
+		Delivery:    e.Delivery,
+	}
+}
+
+// isEmpty reports if the segment has no rules
+func (s *Segment) isEmpty() bool {
+	return s.CountryID == 0 && len(s.Languages) == 0 && len(s.Statuses) == 0 &&
+		s.CreatedAfter == nil && s.CreatedBefore == nil && s.PushEnabled == nil
+}
+
+func (s Segment) toRepo() user.Segment {
+	return user.Segment{
+		CountryID:     s.CountryID,
+		Languages:     s.Languages,
+		Statuses:      s.Statuses,
+		CreatedAfter:  s.CreatedAfter,
+		CreatedBefore: s.CreatedBefore,
+		PushEnabled:   s.PushEnabled,
+	}
+}
+
+// segmentToRepo returns nil for the empty segment, the event without segment targets all the users
+func segmentToRepo(s *Segment) *user.Segment {
+	if s == nil || s.isEmpty() {
+		return nil
+	}
+	segment := s.toRepo()
+	return &segment
+}
+
+func segmentToService(s *user.Segment) *Segment {
+	if s == nil {
+		return nil
+	}
+	return &Segment{
+		CountryID:     s.CountryID,
+		Languages:     s.Languages,
+		Statuses:      s.Statuses,
+		CreatedAfter:  s.CreatedAfter,
+		CreatedBefore: s.CreatedBefore,
+		PushEnabled:   s.PushEnabled,
+	}
+}
+
+// abTestToRepo returns nil for the A/B test without variants, the event without A/B test has the same texts for all the users
+func abTestToRepo(t *ABTest) *event.ABTest {
+	if t == nil || len(t.Variants) == 0 {
+		return nil
+	}
+
+	var test = &event.ABTest{
+		Variants:    make([]event.Variant, 0, len(t.Variants)),
+		WinnerAfter: t.WinnerAfter,
+	}
+	for _, v := range t.Variants {
+		test.Variants = append(test.Variants, event.Variant(v))
 	}
+
+	return test
+}
+
+func abTestToService(t *event.ABTest) *ABTest {
+	if t == nil {
+		return nil
+	}
+
+	var test = &ABTest{
+		Variants:    make([]Variant, 0, len(t.Variants)),
+		WinnerAfter: t.WinnerAfter,
+	}
+	for _, v := range t.Variants {
+		test.Variants = append(test.Variants, Variant(v))
+	}
+
+	return test
+}
+
+// recurrenceToRepo returns nil for the recurrence without cron expression, the event without recurrence is run once
+func recurrenceToRepo(r *Recurrence) *event.Recurrence {
+	if r == nil || r.Cron == _empty {
+		return nil
+	}
+	return &event.Recurrence{Cron: r.Cron, EndAt: r.EndAt, MaxOccurrences: r.MaxOccurrences}
+}
+
+func recurrenceToService(r *event.Recurrence) *Recurrence {
+	if r == nil {
+		return nil
+	}
+	return &Recurrence{Cron: r.Cron, EndAt: r.EndAt, MaxOccurrences: r.MaxOccurrences}
+}
+
+func (r *Run) toService(run *event.Run) {
+	r.ID = run.ID
+	r.EventID = run.EventID
+	r.Number = run.Number
+	r.Status = run.Status
+	r.SuccessCount = run.SuccessCount
+	r.FailedCount = run.FailedCount
+	r.Error = run.Error
+	r.ScheduledAt = run.ScheduledAt
+	r.CreatedAt = run.CreatedAt
+}
+
+func (p *Progress) toService(progress *event.Progress) {
+	p.RunID = progress.RunID
+	p.EventID = progress.EventID
+	p.Status = progress.Status
+	p.Stage = progress.Stage
+	p.Total = progress.Total
+	p.Processed = progress.Processed
+	p.SuccessCount = progress.SuccessCount
+	p.FailedCount = progress.FailedCount
+	p.Attempts = progress.Attempts
+	p.Error = progress.Error
+	p.StartedAt = progress.StartedAt
+	p.UpdatedAt = progress.UpdatedAt
+
+	if p.Total > 0 {
+		p.Percent = math.Round(float64(p.Processed)*10000/float64(p.Total)) / 100
+	}
+
+	if p.Status == _running && p.Processed > 0 && p.Processed < p.Total {
+		var (
+			elapsed = p.UpdatedAt.Sub(p.StartedAt)
+			eta     = p.UpdatedAt.Add(elapsed / time.Duration(p.Processed) * time.Duration(p.Total-p.Processed))
+		)
+		p.ETA = &eta
+	}
+}
+
+func (d *Delivery) toService(delivery *event.Delivery) {
+	d.RunID = delivery.RunID
+	d.UserID = delivery.UserID
+	d.Status = delivery.Status
+	d.FcmMessageID = delivery.FcmMessageID
+	d.ErrorCode = delivery.ErrorCode
+	d.CreatedAt = delivery.CreatedAt
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/binary"
+	"io"
 	"mime/multipart"
 
 	"github.com/bwmarrin/snowflake"
@@ -11,15 +12,21 @@ import (
 	"go.uber.org/zap"
 
 	"notifications/internal/db/tx"
+	"notifications/internal/lib/country"
+	"notifications/internal/repo/analytics"
 	"notifications/internal/repo/event"
-	"notifications/internal/repo/rom"
 	"notifications/internal/repo/user"
 	"notifications/internal/service/admin"
+	"notifications/internal/service/quiethours"
+	usersrv "notifications/internal/service/user"
 	"notifications/pkg/lib/broker/nats"
 	"notifications/pkg/lib/cache"
 	"notifications/pkg/lib/config"
 	"notifications/pkg/lib/fileman"
+	"notifications/pkg/lib/notifier/apns"
 	"notifications/pkg/lib/notifier/firebase"
+	"notifications/pkg/lib/notifier/hms"
+	"notifications/pkg/lib/notifier/webpush"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/observer/sentry"
 	"notifications/pkg/lib/tinypng"
@@ -30,12 +37,21 @@ var Module = fx.Provide(New)
 type Service interface {
 	reader
 	writer
+	reviewer
 	runner
 	imageManager
 }
 
 type reader interface {
 	GetEvents(context.Context, Filter) ([]*Event, error)
+	// EstimateAudience counts the users of the segment without loading them
+	EstimateAudience(context.Context, Segment) (*Estimate, error)
+	// GetRuns returns the runs of the event starting from the last one, the recurring events have one per occurrence
+	GetRuns(ctx context.Context, id int, limit, offset uint) ([]Run, error)
+	// GetDeliveries returns the results of the users of the run of the event sent directly
+	GetDeliveries(ctx context.Context, id, runID int, limit, offset uint) ([]Delivery, error)
+	// GetProgress returns the progress of the run of the event with the estimated time of the end
+	GetProgress(ctx context.Context, id, runID int) (*Progress, error)
 }
 
 type writer interface {
@@ -44,16 +60,34 @@ type writer interface {
 	Delete(ctx context.Context, adminUser admin.Admin, id int) error
 }
 
+// reviewer moves the event through its lifecycle, the draft is submitted for the approval and approved
+// by another admin, only the approved event can be loaded with the users and run
+type reviewer interface {
+	Submit(ctx context.Context, a admin.Admin, id int) (*Event, error)
+	Approve(ctx context.Context, a admin.Admin, id int) (*Event, error)
+	// Reject gets the submitted or approved event back to draft to be changed
+	Reject(ctx context.Context, a admin.Admin, id int) (*Event, error)
+	// Cancel stops the event which is not sent yet and unsubscribes the loaded users
+	Cancel(ctx context.Context, a admin.Admin, id int) (*Event, error)
+}
+
 type runner interface {
-	// LoadUsers reads and processes a CSV file containing user references (userID, phone, or person external reference)
-	// It divides the records into chunks and concurrently subscribes these users to the given firebase cloud messaging topic
-	// The function returns a summary of how many records succeeded or failed, along with details of any failures
-	// The function uses worker pool pattern to process the CSV records concurrently
+	// LoadUsers checks the header of a CSV or XLSX file containing user references (userID, phone, or person external reference)
+	// and uploads the file, SubscribeUsers streams its rows in chunks and concurrently subscribes these users
+	// to the given firebase cloud messaging topic
+	// The summary of how many records succeeded or failed is saved to the event along with the report of the rejected rows
 	LoadUsers(context.Context, admin.Admin, int, multipart.File, *multipart.FileHeader) (*Event, error)
 	LoadAllUsers(ctx context.Context, a admin.Admin, id int) (*Event, error)
+	// RunEvent sends the event with a checkpoint after every page of the users, RunJob resumes the run
+	// of the crashed pod from the last checkpoint
 	RunEvent(ctx context.Context, a admin.Admin, id int) (any, error)
+	// SendWinner sends the winner of the A/B test of the sent event to the holdout, RunJob sends it
+	// when the time given to the variants is over
+	SendWinner(ctx context.Context, a admin.Admin, id int, request WinnerRequest) (*Event, error)
 	RunJob()
 	SubscribeUsers(context.Context, *Event) error
+	// GetRejectedReport returns the CSV report of the rows rejected during the last import of the users
+	GetRejectedReport(ctx context.Context, id int) (io.ReadCloser, error)
 	SubscribeAllUsers(context.Context, *Event) error
 	UnsubscribeUsers(context.Context, *Event) error
 }
@@ -73,12 +107,19 @@ type Params struct {
 	Cache       cache.Cache
 	FcmTopicMan firebase.TopicManager
 	FcmSender   firebase.Sender
+	HmsTopicMan hms.TopicManager
+	HmsSender   hms.Sender
+	ApnsSender  apns.Sender
+	WebSender   webpush.Sender
 	FileManager fileman.FileManager
 	TinyPng     tinypng.Resizer
 	EventRepo   event.Repo
 	UserRepo    user.Repo
-	RomRepo     rom.Repo
 	Transactor  tx.Transactor
+	QuietHours  quiethours.Service
+	Countries   country.Registry
+	Analytics   analytics.Repo
+	Users       usersrv.Service
 }
 
 type service struct {
@@ -89,17 +130,25 @@ type service struct {
 	cache       cache.Cache
 	fcmTopicMan firebase.TopicManager
 	fcmSender   firebase.Sender
+	hmsTopicMan hms.TopicManager
+	hmsSender   hms.Sender
+	apnsSender  apns.Sender
+	webSender   webpush.Sender
 	fileManager fileman.FileManager
 	tinyPng     tinypng.Resizer
 	eventRepo   event.Repo
 	userRepo    user.Repo
-	romRepo     rom.Repo
 	transactor  tx.Transactor
+	quietHours  quiethours.Service
+	countries   country.Registry
+	analytics   analytics.Repo
+	users       usersrv.Service
 	idGenerator *snowflake.Node
 
-	storageUrl string
-	bucket     string
-	directory  string
+	storageUrl        string
+	bucket            string
+	directory         string
+	audienceDirectory string
 }
 
 func New(p Params) Service {
@@ -123,15 +172,24 @@ func New(p Params) Service {
 		cache:       p.Cache,
 		fcmTopicMan: p.FcmTopicMan,
 		fcmSender:   p.FcmSender,
+		hmsTopicMan: p.HmsTopicMan,
+		hmsSender:   p.HmsSender,
+		apnsSender:  p.ApnsSender,
+		webSender:   p.WebSender,
 		fileManager: p.FileManager,
 		tinyPng:     p.TinyPng,
 		eventRepo:   p.EventRepo,
 		userRepo:    p.UserRepo,
-		romRepo:     p.RomRepo,
 		transactor:  p.Transactor,
+		quietHours:  p.QuietHours,
+		countries:   p.Countries,
+		analytics:   p.Analytics,
+		users:       p.Users,
 		idGenerator: idGenerator,
 		storageUrl:  p.Config.GetString("fileManager.storageURL"),
 		bucket:      p.Config.GetString("fileManager.bucket"),
 		directory:   p.Config.GetString("fileManager.directory"),
+
+		audienceDirectory: p.Config.GetString("fileManager.audienceDirectory"),
 	}
 }
@@ -0,0 +1,148 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/repo/event"
+	"notifications/internal/repo/outbox"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/service/admin"
+)
+
+// _errRunTaken is returned by the attempt of the run whose checkpoint is rejected, the run is resumed by another one
+var _errRunTaken = errors.New("run is taken over by another attempt")
+
+// checkpoint saves the progress of the run with the ROM message of the processed page in the transaction,
+// so the page is added to ROM once
+func (s *service) checkpoint(ctx context.Context, progress *event.Progress, romMessage *outbox.Message) (err error) {
+	transaction := s.transactor.New()
+	err = transaction.Begin(ctx)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during beginning transaction", zap.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			if errX := transaction.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = transaction.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	err = transaction.EventRepo().Checkpoint(ctx, progress)
+	if err != nil {
+		return errRunTaken(err)
+	}
+
+	if romMessage != nil {
+		err = transaction.OutboxRepo().Insert(ctx, romMessage)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during saving rom event to outbox", zap.Error(err), zap.Int("eventID", progress.EventID))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// interruptRun handles the error of the attempt of the run, the run fails if the error is not temporary,
+// otherwise the error is saved and the run is resumed from the last checkpoint by RunJob
+func (s *service) interruptRun(ctx context.Context, progress *event.Progress, reason error) {
+	if errors.Is(reason, _errRunTaken) {
+		s.logger.Info("event run is taken over", zap.Int("eventID", progress.EventID), zap.Int("runID", progress.RunID))
+		return
+	}
+
+	var err error
+	if errors.Is(reason, resp.ErrNotFound) || errors.Is(reason, resp.ErrBadRequest) || progress.Attempts >= _maxRunAttempts {
+		err = s.failRun(ctx, progress, reason)
+	} else {
+		progress.Error = reason.Error()
+		err = s.eventRepo.Checkpoint(ctx, progress)
+	}
+	if err != nil && !errors.Is(err, repomodel.ErrNotFound) {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during interrupting event run", zap.Error(err), zap.Int("eventID", progress.EventID), zap.Int("runID", progress.RunID))
+	}
+}
+
+// resumeRuns takes over the runs which have no checkpoint for _staleRun, their pods are expected to be crashed
+func (s *service) resumeRuns(ctx context.Context, currentTime time.Time) {
+	var staleBefore = currentTime.Add(-_staleRun)
+
+	runs, err := s.eventRepo.GetStaleProgress(ctx, _running, staleBefore)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting stale runs", zap.Error(err))
+		}
+		return
+	}
+
+	for _, progress := range runs {
+		err = s.eventRepo.ClaimProgress(ctx, progress, staleBefore)
+		if err != nil {
+			if !errors.Is(err, repomodel.ErrNotFound) {
+				s.sentry.CaptureException(err)
+				s.logger.Error("err occurred during claiming run", zap.Error(err), zap.Int("runID", progress.RunID))
+			}
+			continue
+		}
+
+		s.logger.Info("event run is resumed", zap.Int("eventID", progress.EventID), zap.Int("runID", progress.RunID),
+			zap.Int("attempt", progress.Attempts), zap.String("stage", progress.Stage), zap.Int("cursor", progress.Cursor))
+
+		if progress.Attempts > _maxRunAttempts {
+			s.interruptRun(ctx, progress, fmt.Errorf("run is not finished in %d attempts: %s", _maxRunAttempts, progress.Error))
+			continue
+		}
+
+		_, err = s.processRun(ctx, admin.Admin{}, progress)
+		if err != nil {
+			s.logger.Error("err occurred during resuming event run", zap.Error(err), zap.Int("runID", progress.RunID))
+		}
+	}
+}
+
+func (s *service) GetProgress(ctx context.Context, id, runID int) (*Progress, error) {
+	progress, err := s.eventRepo.GetProgress(ctx, runID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting run progress", zap.Error(err), zap.Int("runID", runID))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "run not found")
+	}
+
+	if progress.EventID != id {
+		return nil, resp.Wrap(resp.ErrNotFound, "run not found")
+	}
+
+	var item = new(Progress)
+	item.toService(progress)
+
+	return item, nil
+}
+
+// errRunTaken returns _errRunTaken if the checkpoint is rejected
+func errRunTaken(err error) error {
+	if errors.Is(err, repomodel.ErrNotFound) {
+		return _errRunTaken
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/db/tx"
+	"notifications/internal/repo/event"
+	"notifications/internal/service/admin"
+	"notifications/pkg/lib/scheduler"
+)
+
+// advance reschedules the recurring event to the next occurrence after the run and gets it back to ready,
+// the event is sent when the recurrence is over
+func (s *service) advance(ctx context.Context, transaction tx.Transactor, a admin.Admin, e *event.Event, runs int) error {
+	next := s.nextOccurrence(e, time.Now(), runs)
+	if next.IsZero() {
+		return s.transition(ctx, transaction, a, e, _sent)
+	}
+
+	err := transaction.EventRepo().Reschedule(ctx, e.ID, next)
+	if err != nil {
+		return err
+	}
+
+	e.ScheduledAt = next
+	return s.transition(ctx, transaction, a, e, _ready)
+}
+
+// nextOccurrence returns the occurrence of the recurring event after the time, zero if the recurrence is over
+func (s *service) nextOccurrence(e *event.Event, after time.Time, runs int) time.Time {
+	if e.Recurrence.MaxOccurrences > 0 && runs >= e.Recurrence.MaxOccurrences {
+		return time.Time{}
+	}
+
+	next, err := scheduler.Next(e.Recurrence.Cron, after, s.location(e.CountryID))
+	if err != nil {
+		s.logger.Warning("failed to get next occurrence", zap.Error(err), zap.Int("eventID", e.ID), zap.String("cron", e.Recurrence.Cron))
+		return time.Time{}
+	}
+
+	if e.Recurrence.EndAt != nil && next.After(*e.Recurrence.EndAt) {
+		return time.Time{}
+	}
+
+	return next.UTC()
+}
+
+// firstOccurrence returns the first occurrence of the recurrence from the scheduled time, the scheduled time itself
+// if it's the occurrence
+func (s *service) firstOccurrence(recurrence *event.Recurrence, countryID int8, scheduledAt time.Time) (time.Time, error) {
+	first, err := scheduler.Next(recurrence.Cron, scheduledAt.Add(-time.Second), s.location(countryID))
+	if err != nil {
+		return time.Time{}, resp.Wrap(resp.ErrBadRequest, "cron expression has no occurrence")
+	}
+
+	if recurrence.EndAt != nil && first.After(*recurrence.EndAt) {
+		return time.Time{}, resp.Wrap(resp.ErrBadRequest, "end date is before the first occurrence")
+	}
+
+	return first.UTC(), nil
+}
+
+// location returns the timezone the recurrence of the event is evaluated in
+func (s *service) location(countryID int8) *time.Location {
+	if countryID == 0 {
+		return time.UTC
+	}
+
+	c, err := s.countries.ByID(countryID)
+	if err != nil {
+		s.logger.Warning("failed to get event country", zap.Error(err), zap.Int8("countryID", countryID))
+		return time.UTC
+	}
+
+	return c.Location()
+}
+
+// validateRecurrence checks the cron expression and the limits, the A/B test is not repeated
+// since its holdout gets the winner once
+func validateRecurrence(request *Request) error {
+	if request.Recurrence == nil || request.Recurrence.Cron == _empty {
+		return nil
+	}
+
+	if request.ABTest != nil && len(request.ABTest.Variants) != 0 {
+		return resp.Wrap(resp.ErrBadRequest, "event with A/B test cannot be recurring")
+	}
+
+	_, err := scheduler.Parse(request.Recurrence.Cron)
+	if err != nil {
+		return resp.Wrap(resp.ErrBadRequest, "cron expression is not valid: "+err.Error())
+	}
+
+	if request.Recurrence.MaxOccurrences < 0 {
+		return resp.Wrap(resp.ErrBadRequest, "maxOccurrences cannot be negative")
+	}
+
+	return nil
+}
@@ -4,128 +4,337 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"firebase.google.com/go/v4/messaging"
 	"go.uber.org/zap"
 
 	"notifications/internal/api/resp"
 	"notifications/internal/api/transport/broker/stream"
 	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/db/tx"
+	"notifications/internal/repo/analytics"
+	"notifications/internal/repo/event"
+	"notifications/internal/repo/outbox"
 	"notifications/internal/repo/repomodel"
 	"notifications/internal/repo/rom"
+	"notifications/internal/repo/user"
 	"notifications/internal/service/admin"
+	"notifications/pkg/lib/notifier/hms"
 )
 
+// RunEvent starts the run of the event and processes it, the run which failed midway keeps its checkpoint
+// and is resumed by RunJob
 func (s *service) RunEvent(ctx context.Context, a admin.Admin, id int) (any, error) {
 	s.logger.Info("RunEvent start", zap.Int("eventID", id))
 
-	tx := s.transactor.New()
-	err := tx.Begin(ctx)
+	progress, err := s.startRun(ctx, a, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.processRun(ctx, a, progress)
+}
+
+// startRun claims the event for the run, so it's sent once by the job or the admin, and creates the run
+// with its progress
+func (s *service) startRun(ctx context.Context, a admin.Admin, id int) (*event.Progress, error) {
+	var progress *event.Progress
+
+	_, err := s.changeStatus(ctx, a, id, _sending, func(ctx context.Context, transaction tx.Transactor, e *event.Event) error {
+		counts, err := transaction.UserRepo().CountRelations(ctx, e.ID)
+		if err != nil {
+			return err
+		}
+
+		run, err := transaction.EventRepo().CreateRun(ctx, &event.Run{
+			ID:          int(s.idGenerator.Generate().Int64()),
+			EventID:     e.ID,
+			Status:      _running,
+			ScheduledAt: e.ScheduledAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		progress = &event.Progress{
+			RunID:   run.ID,
+			EventID: e.ID,
+			Status:  _running,
+			Stage:   _stageUsers,
+		}
+		for _, count := range counts {
+			progress.Total += count.Count
+		}
+
+		return transaction.EventRepo().CreateProgress(ctx, progress)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+// processRun processes the users of the event in the pages after the checkpoint, they are added to ROM
+// and sent the pushes if the event is sent directly, the progress is saved after every page,
+// the event is sent to the topics once all the users are processed
+func (s *service) processRun(ctx context.Context, a admin.Admin, progress *event.Progress) (_ *RunResponse, err error) {
+	var serviceResponse = &RunResponse{RunID: progress.RunID, Result: make([]any, 0)}
+
+	defer func() {
+		if err != nil {
+			s.interruptRun(ctx, progress, err)
+		}
+	}()
+
+	selectedEvent, err := s.eventRepo.GetByID(ctx, progress.EventID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting event", zap.Error(err), zap.Int("id", progress.EventID))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "event not found or finished")
+	}
+
+	if selectedEvent.Status != _sending {
+		return nil, resp.Wrap(resp.ErrBadRequest, "event is not being sent")
+	}
+
+	if progress.Total == 0 {
+		return nil, resp.Wrap(resp.ErrNotFound, "there are no users subscribed to this event")
+	}
+
+	var romEvent = &rom.Event{
+		ID:        selectedEvent.ID,
+		Title:     selectedEvent.Title,
+		Body:      selectedEvent.Body,
+		Image:     selectedEvent.Image,
+		ExtraData: selectedEvent.ExtraData,
+	}
+
+	for progress.Stage == _stageUsers {
+		recipients, err := s.userRepo.GetRecipients(ctx, selectedEvent.ID, progress.Cursor, _recipientsPage)
+		if err != nil && !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting recipients", zap.Error(err), zap.Int("eventID", selectedEvent.ID))
+			return nil, err
+		}
+
+		if len(recipients) == 0 {
+			progress.Stage = _stageSend
+			err = s.checkpoint(ctx, progress, nil)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		var (
+			userIDs    = make([]int, 0, len(recipients))
+			countryIDs = make([]int8, 0, len(recipients))
+		)
+		for _, recipient := range recipients {
+			userIDs = append(userIDs, recipient.UserID)
+			countryIDs = append(countryIDs, recipient.CountryID)
+		}
+
+		romMessage, err := outbox.NewRomEvent(selectedEvent.ID, userIDs, countryIDs, romEvent)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during building rom event", zap.Error(err), zap.Int("eventID", selectedEvent.ID))
+			return nil, resp.Wrap(resp.ErrInternalErr, err.Error())
+		}
+
+		// the page of the run interrupted before the checkpoint is sent again to the recipients without the deliveries
+		if selectedEvent.Delivery == _directDelivery {
+			success, failed, err := s.sendRecipients(ctx, selectedEvent, progress.RunID, recipients, variants(selectedEvent.ABTest))
+			if err != nil {
+				return nil, err
+			}
+			progress.SuccessCount += success
+			progress.FailedCount += failed
+		}
+
+		progress.Cursor = userIDs[len(userIDs)-1]
+		progress.Processed += len(recipients)
+
+		err = s.checkpoint(ctx, progress, romMessage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if progress.Stage == _stageSend {
+		if selectedEvent.Delivery != _directDelivery {
+			var (
+				languages = s.languages(selectedEvent.CountryID)
+				messages  []*messaging.Message
+			)
+			for _, variant := range variants(selectedEvent.ABTest) {
+				messages = append(messages, setupMessages(selectedEvent, languages, variant, progress.RunID)...)
+			}
+
+			s.logger.Info("firebase messaging request", zap.Any("messages", messages), zap.Int("eventID", selectedEvent.ID))
+
+			response, err := s.fcmSender.SendEach(ctx, messages)
+			if err != nil {
+				s.sentry.CaptureException(err)
+				s.logger.Error("err occurred during sending push", zap.Error(err), zap.Int("eventID", selectedEvent.ID))
+				return nil, err
+			}
+
+			s.logger.Info("firebase messaging response", zap.Any("response", response), zap.Int("eventID", selectedEvent.ID))
+
+			progress.SuccessCount, progress.FailedCount = response.SuccessCount, response.FailureCount
+
+			success, failed := s.sendHmsTopics(ctx, selectedEvent.ID, messages)
+			progress.SuccessCount, progress.FailedCount = progress.SuccessCount+success, progress.FailedCount+failed
+			for _, res := range response.Responses {
+				serviceResponse.Result = append(serviceResponse.Result, struct {
+					MessageID string `json:"messageID"`
+					Error     error  `json:"error"`
+					Success   bool   `json:"success"`
+				}{
+					MessageID: res.MessageID,
+					Error:     res.Error,
+					Success:   res.Success,
+				})
+			}
+		}
+
+		progress.Stage = _stageFinish
+		err = s.checkpoint(ctx, progress, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.finishRun(ctx, a, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceResponse.SuccessCount, serviceResponse.FailedCount = progress.SuccessCount, progress.FailedCount
+
+	s.logger.Info("RunEvent end", zap.Int("eventID", selectedEvent.ID), zap.Int("runID", progress.RunID))
+
+	return serviceResponse, nil
+}
+
+// sendHmsTopics sends the messages to the same topics at HMS and returns the number of the succeeded and failed ones,
+// the messages are sent by FCM already so the failure of HMS doesn't fail the run. Nothing is sent if HMS is not configured
+func (s *service) sendHmsTopics(ctx context.Context, eventID int, messages []*messaging.Message) (success, failed int) {
+	for _, message := range messages {
+		var hmsMessage = &hms.Message{Topic: message.Topic}
+		hms.NotificationMSG(hmsMessage, message.Data, hms.UrgencyNormal)
+
+		_, err := s.hmsSender.Send(ctx, hmsMessage)
+		if errors.Is(err, hms.ErrNotConfigured) {
+			// the HMS topics are skipped if Push Kit is not configured, there are no HMS devices then
+			return success, failed
+		}
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during sending hms push", zap.Error(err), zap.String("topic", message.Topic), zap.Int("eventID", eventID))
+			failed++
+			continue
+		}
+		success++
+	}
+
+	return success, failed
+}
+
+// finishRun saves the run as sent and moves the event out of sending
+func (s *service) finishRun(ctx context.Context, a admin.Admin, progress *event.Progress) (err error) {
+	transaction := s.transactor.New()
+	err = transaction.Begin(ctx)
 	if err != nil {
 		s.sentry.CaptureException(err)
 		s.logger.Error("err occurred during beginning transaction", zap.Error(err))
-		return nil, err
+		return err
 	}
 
 	defer func() {
 		if err != nil {
-			if errX := tx.Rollback(ctx); errX != nil {
+			if errX := transaction.Rollback(ctx); errX != nil {
 				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
 			}
 			return
 		}
-		err = tx.Commit(ctx)
+		err = transaction.Commit(ctx)
 		if err != nil {
 			err = errors.Join(err, errors.New("tx commit failed"))
 		}
 	}()
 
-	selectedEvent, err := tx.EventRepo().GetActiveByIDWithLock(ctx, id)
+	var id = progress.EventID
+
+	selectedEvent, err := transaction.EventRepo().GetByIDWithLock(ctx, id)
 	if err != nil {
 		if !errors.Is(err, repomodel.ErrNotFound) {
 			s.sentry.CaptureException(err)
 			s.logger.Error("err occurred during getting event", zap.Error(err), zap.Int("id", id))
-			return nil, err
+			return err
 		}
-		return nil, resp.Wrap(resp.ErrNotFound, "event not found or not active")
+		return resp.Wrap(resp.ErrNotFound, "event not found or finished")
 	}
 
-	if selectedEvent.Status == _sent {
-		return nil, resp.Wrap(resp.ErrBadRequest, "cannot load users for event with sent status")
+	if selectedEvent.Status != _sending {
+		return resp.Wrap(resp.ErrBadRequest, "event is not being sent")
 	}
 
 	var oldEvent = *selectedEvent
 
-	selectedEvent.Status = _sent
-	err = tx.EventRepo().UpdateStatus(ctx, selectedEvent.ID, selectedEvent.Status)
-	if err != nil {
-		s.sentry.CaptureException(err)
-		s.logger.Error("err occurred during updating event", zap.Error(err), zap.Int("eventID", id))
-		return nil, err
+	if selectedEvent.ABTest != nil {
+		err = transaction.EventRepo().UpdateExtraData(ctx, id, map[string]string{_sentAtKey: time.Now().UTC().Format(time.RFC3339)})
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during saving sent time", zap.Error(err), zap.Int("eventID", id))
+			return err
+		}
 	}
 
-	userIDs, err := tx.UserRepo().GetUserIDsByEventID(ctx, id)
+	run, err := transaction.EventRepo().GetRunByID(ctx, progress.RunID)
 	if err != nil {
-		if errors.Is(err, repomodel.ErrNotFound) {
-			return nil, resp.Wrap(resp.ErrNotFound, "there are no users subscribed to this event")
-		}
 		s.sentry.CaptureException(err)
-		s.logger.Error("err occurred during getting user relations", zap.Error(err), zap.Int("eventID", id))
-		return nil, err
+		s.logger.Error("err occurred during getting run", zap.Error(err), zap.Int("runID", progress.RunID))
+		return err
 	}
 
-	err = s.romRepo.BatchInsert(ctx, id, userIDs, &rom.Event{
-		ID:        selectedEvent.ID,
-		Title:     selectedEvent.Title,
-		Body:      selectedEvent.Body,
-		Image:     selectedEvent.Image,
-		ExtraData: selectedEvent.ExtraData,
-	})
+	run.Status, run.SuccessCount, run.FailedCount = _sent, progress.SuccessCount, progress.FailedCount
+	err = transaction.EventRepo().FinishRun(ctx, run)
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("err from BatchInsert", zap.Error(err), zap.Int("eventID", id))
-		return nil, resp.Wrap(resp.ErrInternalErr, err.Error())
+		s.logger.Error("err occurred during saving run", zap.Error(err), zap.Int("eventID", id))
+		return err
 	}
 
-	var messages = setupMessages(selectedEvent)
-
-	s.logger.Info("firebase messaging request", zap.Any("messages", messages), zap.Int("eventID", id))
-
-	response, err := s.fcmSender.SendEach(ctx, messages)
+	progress.Status = _sent
+	err = transaction.EventRepo().Checkpoint(ctx, progress)
 	if err != nil {
-		s.sentry.CaptureException(err)
-		s.logger.Error("err occurred during sending push", zap.Error(err), zap.Int("eventID", id))
-		return nil, err
+		return errRunTaken(err)
 	}
 
-	s.logger.Info("firebase messaging response", zap.Any("response", response), zap.Int("eventID", id))
-
-	var serviceResponse = &struct {
-		SuccessCount int   `json:"successCount"`
-		FailedCount  int   `json:"failedCount"`
-		Result       []any `json:"result"`
-	}{
-		SuccessCount: response.SuccessCount,
-		FailedCount:  response.FailureCount,
-		Result:       make([]any, 0, len(response.Responses)),
+	// the recurring event gets back to ready until the recurrence is over
+	if selectedEvent.Recurrence != nil {
+		err = s.advance(ctx, transaction, a, selectedEvent, run.Number)
+	} else {
+		err = s.transition(ctx, transaction, a, selectedEvent, _sent)
 	}
-
-	for _, res := range response.Responses {
-		serviceResponse.Result = append(serviceResponse.Result, struct {
-			MessageID string `json:"messageID"`
-			Error     error  `json:"error"`
-			Success   bool   `json:"success"`
-		}{
-			MessageID: res.MessageID,
-			Error:     res.Error,
-			Success:   res.Success,
-		})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during finishing event run", zap.Error(err), zap.Int("eventID", id))
+		return err
 	}
 
-	s.logger.Info("RunEvent end", zap.Int("eventID", id))
+	s.setTargeted(ctx, transaction.UserRepo(), id, run.ID, variants(selectedEvent.ABTest))
 
-	if a != (admin.Admin{}) {
-		err = s.nats.Publish(stream.Audit, subject.AuditAdd, admin.Audit{
+	if a.ID != 0 {
+		err = outbox.Publish(ctx, transaction.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
 			AdminId:   a.ID,
 			IpAddress: a.IP,
 			EventName: admin.RunEvent,
@@ -135,18 +344,72 @@ func (s *service) RunEvent(ctx context.Context, a admin.Admin, id int) (any, err
 		})
 		if err != nil {
 			s.sentry.CaptureException(err)
-			s.logger.Error("failed to publish audit event", zap.Error(err))
+			s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+			return err
 		}
 	}
 
-	var event = new(Event)
-	event.toService(selectedEvent)
-	err = s.nats.Publish(stream.Notifications, subject.NotificationsTopicUsersUnsubscribed, event)
+	// the recurring event keeps the topics for the next occurrences and the holdout of the A/B test
+	// until the winner is sent
+	if selectedEvent.Status != _sent || holdout(selectedEvent.ABTest) > 0 {
+		return nil
+	}
+
+	var item = new(Event)
+	item.toService(selectedEvent)
+	err = outbox.Publish(ctx, transaction.OutboxRepo(), stream.Notifications, subject.NotificationsTopicUsersUnsubscribed, item)
 	if err != nil {
 		s.sentry.CaptureException(err)
-		s.logger.Error("cannot publish message", zap.Error(err), zap.Int("id", id))
-		return nil, err
+		s.logger.Error("cannot save message to outbox", zap.Error(err), zap.Int("id", id))
+		return err
 	}
 
-	return serviceResponse, nil
+	return nil
+}
+
+// setTargeted saves the number of the subscribed users of the sent variants by language as the first step
+// of the funnel of the event and of the run, the stats don't fail the run
+func (s *service) setTargeted(ctx context.Context, userRepo user.Repo, id, runID int, sent []string) {
+	counts, err := userRepo.CountRelations(ctx, id)
+	if err == nil {
+		var stats = make([]analytics.Stat, 0, len(counts))
+		for _, count := range counts {
+			if slices.Contains(sent, count.Variant) {
+				stats = append(stats, analytics.Stat{Language: count.Lang, Variant: count.Variant, Count: count.Count})
+			}
+		}
+		err = s.analytics.SetTargeted(ctx, analytics.SourceEvent, id, stats)
+		if err == nil && runID != 0 {
+			err = s.analytics.SetTargeted(ctx, analytics.SourceRun, runID, stats)
+		}
+	}
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during saving targeted users", zap.Error(err), zap.Int("eventID", id))
+	}
+}
+
+func (s *service) GetRuns(ctx context.Context, id int, limit, offset uint) ([]Run, error) {
+	if limit == 0 {
+		limit = 20
+	}
+
+	runs, err := s.eventRepo.GetRuns(ctx, id, limit, offset)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting runs", zap.Error(err), zap.Int("eventID", id))
+			return nil, err
+		}
+		return []Run{}, nil
+	}
+
+	var list = make([]Run, 0, len(runs))
+	for _, run := range runs {
+		var item Run
+		item.toService(run)
+		list = append(list, item)
+	}
+
+	return list, nil
 }
@@ -0,0 +1,70 @@
+package event
+
+import (
+	"context"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/lib/language"
+)
+
+func (s *service) EstimateAudience(ctx context.Context, segment Segment) (*Estimate, error) {
+	err := s.checkSegment(&segment)
+	if err != nil {
+		return nil, err
+	}
+
+	total, reachable, err := s.userRepo.CountBySegment(ctx, segment.toRepo())
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during counting segment users", zap.Error(err), zap.Any("segment", segment))
+		return nil, err
+	}
+
+	return &Estimate{Total: total, Reachable: reachable}, nil
+}
+
+// validateSegment checks the segment of the request, the segment and the event target the same country
+func (s *service) validateSegment(request *Request) error {
+	if request.Segment == nil || request.Segment.isEmpty() {
+		return nil
+	}
+
+	switch {
+	case request.CountryID == 0:
+		request.CountryID = request.Segment.CountryID
+	case request.Segment.CountryID == 0:
+		request.Segment.CountryID = request.CountryID
+	case request.Segment.CountryID != request.CountryID:
+		return resp.Wrap(resp.ErrBadRequest, "segment country differs from the event country")
+	}
+
+	return s.checkSegment(request.Segment)
+}
+
+func (s *service) checkSegment(segment *Segment) error {
+	var languages = language.GetAll()
+
+	if segment.CountryID != 0 {
+		c, err := s.countries.ByID(segment.CountryID)
+		if err != nil {
+			s.logger.Warning("segment country is not valid", zap.Error(err))
+			return resp.Wrap(resp.ErrBadRequest, err.Error())
+		}
+		languages = c.Languages
+	}
+
+	for _, lang := range segment.Languages {
+		if !slices.Contains(languages, lang) {
+			return resp.Wrap(resp.ErrBadRequest, "segment language is not valid: "+lang)
+		}
+	}
+
+	if segment.CreatedAfter != nil && segment.CreatedBefore != nil && !segment.CreatedAfter.Before(*segment.CreatedBefore) {
+		return resp.Wrap(resp.ErrBadRequest, "segment createdAfter must be before createdBefore")
+	}
+
+	return nil
+}
@@ -0,0 +1,291 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/transport/broker/stream"
+	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/db/tx"
+	"notifications/internal/repo/event"
+	"notifications/internal/repo/outbox"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/service/admin"
+)
+
+// _transitions are the statuses the event can be moved to from the status, the event is edited as a draft,
+// approved by the other admin than the one who submitted it, then the users are loaded and it's sent,
+// the recurring event gets back to ready after every occurrence
+var _transitions = map[string][]string{
+	_draft:           {_pendingApproval},
+	_pendingApproval: {_draft, _scheduled, _cancelled},
+	_scheduled:       {_draft, _loadingUsers, _cancelled},
+	_loadingUsers:    {_ready, _scheduled},
+	_ready:           {_loadingUsers, _sending, _cancelled},
+	_sending:         {_sent, _failed, _ready},
+}
+
+func (s *service) Submit(ctx context.Context, a admin.Admin, id int) (*Event, error) {
+	return s.changeStatus(ctx, a, id, _pendingApproval, func(ctx context.Context, transaction tx.Transactor, e *event.Event) error {
+		e.SubmittedBy, e.ApprovedBy = a.ID, 0
+		return transaction.EventRepo().SetReviewers(ctx, e.ID, e.SubmittedBy, e.ApprovedBy)
+	})
+}
+
+// submit sends the created or updated draft for the approval in the same transaction
+func (s *service) submit(ctx context.Context, transaction tx.Transactor, a admin.Admin, e *event.Event) error {
+	err := s.transition(ctx, transaction, a, e, _pendingApproval)
+	if err != nil {
+		return err
+	}
+
+	e.SubmittedBy, e.ApprovedBy = a.ID, 0
+	err = transaction.EventRepo().SetReviewers(ctx, e.ID, e.SubmittedBy, e.ApprovedBy)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during saving reviewers", zap.Error(err), zap.Int("id", e.ID))
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) Approve(ctx context.Context, a admin.Admin, id int) (*Event, error) {
+	return s.changeStatus(ctx, a, id, _scheduled, func(ctx context.Context, transaction tx.Transactor, e *event.Event) error {
+		if a.ID == 0 || a.ID == e.SubmittedBy {
+			return resp.Wrap(resp.ErrForbidden, "event must be approved by other admin than the one who submitted it")
+		}
+		e.ApprovedBy = a.ID
+		return transaction.EventRepo().SetReviewers(ctx, e.ID, e.SubmittedBy, e.ApprovedBy)
+	})
+}
+
+func (s *service) Reject(ctx context.Context, a admin.Admin, id int) (*Event, error) {
+	return s.changeStatus(ctx, a, id, _draft, func(ctx context.Context, transaction tx.Transactor, e *event.Event) error {
+		e.SubmittedBy, e.ApprovedBy = 0, 0
+		return transaction.EventRepo().SetReviewers(ctx, e.ID, e.SubmittedBy, e.ApprovedBy)
+	})
+}
+
+func (s *service) Cancel(ctx context.Context, a admin.Admin, id int) (*Event, error) {
+	return s.changeStatus(ctx, a, id, _cancelled, func(ctx context.Context, transaction tx.Transactor, e *event.Event) error {
+		// the loaded users are unsubscribed from the topics of the event
+		var item = new(Event)
+		item.toService(e)
+		return outbox.Publish(ctx, transaction.OutboxRepo(), stream.Notifications, subject.NotificationsTopicUsersUnsubscribed, item)
+	})
+}
+
+// changeStatus moves the event to the status in the transaction, apply saves the rest of the changes of the transition
+func (s *service) changeStatus(ctx context.Context, a admin.Admin, id int, to string, apply func(context.Context, tx.Transactor, *event.Event) error) (_ *Event, err error) {
+	transaction := s.transactor.New()
+	err = transaction.Begin(ctx)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err on tx.Begin", zap.Error(err))
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			if errX := transaction.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = transaction.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	selectedEvent, err := transaction.EventRepo().GetByIDWithLock(ctx, id)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting event", zap.Error(err), zap.Int("id", id))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "event not found or finished")
+	}
+
+	err = s.transition(ctx, transaction, a, selectedEvent, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if apply != nil {
+		err = apply(ctx, transaction, selectedEvent)
+		if err != nil {
+			if !errors.Is(err, resp.ErrForbidden) {
+				s.sentry.CaptureException(err)
+				s.logger.Error("err occurred during changing event status", zap.Error(err), zap.Int("id", id), zap.String("status", to))
+			}
+			return nil, err
+		}
+	}
+
+	var item = new(Event)
+	item.toService(selectedEvent)
+	s.setImgURL(item)
+
+	return item, nil
+}
+
+// transition moves the event to the status if the transition is allowed and records it to the audit,
+// the transitions made by the system have no admin
+func (s *service) transition(ctx context.Context, transaction tx.Transactor, a admin.Admin, e *event.Event, to string) error {
+	if !slices.Contains(_transitions[e.Status], to) {
+		return resp.Wrap(resp.ErrBadRequest, fmt.Sprintf("event cannot be moved from %s to %s", e.Status, to))
+	}
+
+	err := transaction.EventRepo().Transition(ctx, e.ID, e.Status, to)
+	if err != nil {
+		if errors.Is(err, repomodel.ErrNotFound) {
+			return resp.Wrap(resp.ErrBadRequest, "event status is changed, try again")
+		}
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during updating event status", zap.Error(err), zap.Int("id", e.ID), zap.String("status", to))
+		return err
+	}
+
+	var oldEvent = *e
+	e.Status = to
+
+	err = outbox.Publish(ctx, transaction.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
+		AdminId:   a.ID,
+		IpAddress: a.IP,
+		EventName: admin.TransitionEvent,
+		OldData:   oldEvent,
+		NewData:   *e,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// finishLoading saves the event with the results of the loading of the users and moves it to the status,
+// ready if the users are loaded and back to scheduled if the loading failed
+func (s *service) finishLoading(ctx context.Context, e *Event, to string) (err error) {
+	transaction := s.transactor.New()
+	err = transaction.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			if errX := transaction.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = transaction.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	var item = toRepo(e)
+	item.Status = _loadingUsers
+
+	err = s.transition(ctx, transaction, admin.Admin{}, item, to)
+	if err != nil {
+		return err
+	}
+
+	_, err = transaction.EventRepo().Update(ctx, item)
+	if err != nil {
+		return err
+	}
+
+	e.Status = to
+	return nil
+}
+
+// failRun fails the run and moves the event which failed to be sent out of sending, the recurring event skips
+// the occurrence so the failure doesn't stop the recurrence, the sent pushes cannot be recalled
+func (s *service) failRun(ctx context.Context, progress *event.Progress, reason error) (err error) {
+	transaction := s.transactor.New()
+	err = transaction.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			if errX := transaction.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = transaction.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	var id = progress.EventID
+
+	progress.Status, progress.Error = _failed, reason.Error()
+	err = transaction.EventRepo().Checkpoint(ctx, progress)
+	if err != nil {
+		return err
+	}
+
+	run, err := transaction.EventRepo().GetRunByID(ctx, progress.RunID)
+	if err != nil {
+		return err
+	}
+
+	run.Status, run.SuccessCount, run.FailedCount, run.Error = _failed, progress.SuccessCount, progress.FailedCount, reason.Error()
+	err = transaction.EventRepo().FinishRun(ctx, run)
+	if err != nil {
+		return err
+	}
+
+	selectedEvent, err := transaction.EventRepo().GetByIDWithLock(ctx, id)
+	if err != nil || selectedEvent.Status != _sending {
+		if errors.Is(err, repomodel.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if selectedEvent.Recurrence == nil {
+		err = transaction.EventRepo().UpdateExtraData(ctx, id, map[string]string{_reasonKey: reason.Error()})
+		if err != nil {
+			return err
+		}
+		return s.transition(ctx, transaction, admin.Admin{}, selectedEvent, _failed)
+	}
+
+	err = s.advance(ctx, transaction, admin.Admin{}, selectedEvent, run.Number)
+	if err != nil || selectedEvent.Status != _sent {
+		return err
+	}
+
+	var item = new(Event)
+	item.toService(selectedEvent)
+	return outbox.Publish(ctx, transaction.OutboxRepo(), stream.Notifications, subject.NotificationsTopicUsersUnsubscribed, item)
+}
+
+// checkStatus returns ErrBadRequest if the event is not in one of the statuses
+func checkStatus(e *event.Event, action string, statuses ...string) error {
+	if slices.Contains(statuses, e.Status) {
+		return nil
+	}
+	return resp.Wrap(resp.ErrBadRequest, fmt.Sprintf("cannot %s event with %s status, allowed: %s", action, e.Status, strings.Join(statuses, ", ")))
+}
@@ -3,18 +3,25 @@ package event
 import (
 	"context"
 	"errors"
+	"slices"
 	"sync"
 
 	"go.uber.org/zap"
 
 	"notifications/internal/lib/language"
 	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/user"
 	"notifications/pkg/util/strset"
 )
 
 func (s *service) UnsubscribeUsers(ctx context.Context, event *Event) error {
 	s.logger.Info("UnsubscribeUsers start", zap.Int("eventID", event.ID))
 
+	// the users of the event sent directly are not subscribed to the topics
+	if event.Delivery == _directDelivery {
+		return s.deleteRelations(ctx, event)
+	}
+
 	userIDs, err := s.userRepo.GetUserIDsByEventID(ctx, event.ID)
 	if err != nil {
 		if !errors.Is(err, repomodel.ErrNotFound) {
@@ -40,9 +47,16 @@ func (s *service) UnsubscribeUsers(ctx context.Context, event *Event) error {
 		return nil
 	}
 
-	var tokens = make([]string, 0, len(users))
+	var devices = make([]user.DeviceToken, 0, len(users))
 	for i := 0; i < len(users); i++ {
-		tokens = append(tokens, users[i].Token)
+		devices = append(devices, users[i].ActiveDevices()...)
+	}
+
+	var providers = user.ByProvider(devices)
+	for provider := range providers {
+		if !user.HasTopics(provider) {
+			delete(providers, provider)
+		}
 	}
 
 	var (
@@ -50,24 +64,40 @@ func (s *service) UnsubscribeUsers(ctx context.Context, event *Event) error {
 		languages = language.GetAll()
 	)
 
-	for _, lang := range languages {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-
-			topicLang := buildTopic(event.Topic, lang)
-			_, err = s.fcmTopicMan.UnsubscribeTokens(ctx, tokens, topicLang)
-			if err != nil {
-				s.sentry.CaptureException(err)
-				s.logger.Error("err from UnsubscribeTokens", zap.Error(err), zap.String("topic", topicLang), zap.Int("eventID", event.ID))
-				return
-			}
-		}()
+	for _, topic := range event.topics() {
+		for _, lang := range languages {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				topicLang := buildTopic(topic, lang)
+				for provider, tokens := range providers {
+					for batch := range slices.Chunk(tokens, _topicTokensLimit) {
+						var err error
+						switch provider {
+						case user.ProviderHms:
+							_, err = s.hmsTopicMan.Unsubscribe(ctx, batch, topicLang)
+						default:
+							_, err = s.fcmTopicMan.UnsubscribeTokens(ctx, batch, topicLang)
+						}
+						if err != nil {
+							s.sentry.CaptureException(err)
+							s.logger.Error("err from unsubscribing from topic", zap.Error(err), zap.String("topic", topicLang), zap.String("provider", provider), zap.Int("eventID", event.ID))
+							break
+						}
+					}
+				}
+			}()
+		}
 	}
 
 	wg.Wait()
 
-	err = s.userRepo.DeleteRelationByEventID(ctx, event.ID)
+	return s.deleteRelations(ctx, event)
+}
+
+func (s *service) deleteRelations(ctx context.Context, event *Event) error {
+	err := s.userRepo.DeleteRelationByEventID(ctx, event.ID)
 	if err != nil {
 		s.sentry.CaptureException(err)
 		s.logger.Error("err occurred during deleting user relations", zap.Error(err), zap.Int("eventID", event.ID))
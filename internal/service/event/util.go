@@ -1,6 +1,12 @@
 package event
 
-import "strings"
+import (
+	"strings"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/lib/language"
+)
 
 func buildTopic(topic, lang string) string {
 	if strings.HasSuffix(topic, lang) {
@@ -8,3 +14,18 @@ func buildTopic(topic, lang string) string {
 	}
 	return topic + _underscoreDelim + lang
 }
+
+// languages returns the languages of the country the event targets, all the languages if it targets all countries
+func (s *service) languages(countryID int8) []string {
+	if countryID == 0 {
+		return language.GetAll()
+	}
+
+	c, err := s.countries.ByID(countryID)
+	if err != nil {
+		s.logger.Warning("failed to get event country", zap.Error(err), zap.Int8("countryID", countryID))
+		return language.GetAll()
+	}
+
+	return c.Languages
+}
@@ -0,0 +1,375 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"regexp"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/transport/broker/stream"
+	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/lib/language"
+	"notifications/internal/repo/analytics"
+	"notifications/internal/repo/event"
+	"notifications/internal/repo/outbox"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/service/admin"
+	"notifications/pkg/util/strset"
+)
+
+var _variantPattern = regexp.MustCompile(_variantRegex)
+
+func (s *service) SendWinner(ctx context.Context, a admin.Admin, id int, request WinnerRequest) (_ *Event, err error) {
+	s.logger.Info("SendWinner start", zap.Int("eventID", id))
+
+	tx := s.transactor.New()
+	err = tx.Begin(ctx)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during beginning transaction", zap.Error(err))
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			if errX := tx.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	selectedEvent, err := tx.EventRepo().GetSentByIDWithLock(ctx, id)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting event", zap.Error(err), zap.Int("id", id))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "event not found or not sent")
+	}
+
+	if selectedEvent.ABTest == nil {
+		return nil, resp.Wrap(resp.ErrBadRequest, "event has no A/B test")
+	}
+	if !strset.IsEmpty(selectedEvent.ExtraData[_winnerKey]) {
+		return nil, resp.Wrap(resp.ErrBadRequest, "winner is already picked: "+selectedEvent.ExtraData[_winnerKey])
+	}
+
+	var oldEvent = *selectedEvent
+
+	winner := request.Variant
+	if strset.IsEmpty(winner) {
+		winner, err = s.pickWinner(ctx, selectedEvent)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !slices.ContainsFunc(selectedEvent.ABTest.Variants, func(v event.Variant) bool { return v.Key == winner }) {
+		return nil, resp.Wrap(resp.ErrBadRequest, "variant not found: "+winner)
+	}
+
+	selectedEvent.ExtraData = maps.Clone(selectedEvent.ExtraData)
+	if selectedEvent.ExtraData == nil {
+		selectedEvent.ExtraData = make(map[string]string)
+	}
+	selectedEvent.ExtraData[_winnerKey] = winner
+	err = tx.EventRepo().UpdateExtraData(ctx, id, map[string]string{_winnerKey: winner})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during saving winner", zap.Error(err), zap.Int("eventID", id))
+		return nil, err
+	}
+
+	if holdout(selectedEvent.ABTest) > 0 {
+		// the send to the holdout is the second run of the event
+		var run = &event.Run{
+			ID:          int(s.idGenerator.Generate().Int64()),
+			EventID:     id,
+			Status:      _sent,
+			ScheduledAt: selectedEvent.ScheduledAt,
+		}
+
+		if selectedEvent.Delivery == _directDelivery {
+			run.SuccessCount, run.FailedCount, err = s.sendDirect(ctx, selectedEvent, run.ID, []string{_holdout})
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			var messages = setupMessages(selectedEvent, s.languages(selectedEvent.CountryID), _holdout, run.ID)
+
+			s.logger.Info("firebase messaging request", zap.Any("messages", messages), zap.Int("eventID", id))
+
+			response, err := s.fcmSender.SendEach(ctx, messages)
+			if err != nil {
+				s.sentry.CaptureException(err)
+				s.logger.Error("err occurred during sending push", zap.Error(err), zap.Int("eventID", id))
+				return nil, err
+			}
+
+			s.logger.Info("firebase messaging response", zap.Any("response", response), zap.Int("eventID", id))
+
+			run.SuccessCount, run.FailedCount = response.SuccessCount, response.FailureCount
+
+			success, failed := s.sendHmsTopics(ctx, id, messages)
+			run.SuccessCount, run.FailedCount = run.SuccessCount+success, run.FailedCount+failed
+		}
+
+		_, err = tx.EventRepo().CreateRun(ctx, run)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during saving run", zap.Error(err), zap.Int("eventID", id))
+			return nil, err
+		}
+
+		s.setTargeted(ctx, tx.UserRepo(), id, run.ID, []string{_holdout})
+
+		var item = new(Event)
+		item.toService(selectedEvent)
+		err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Notifications, subject.NotificationsTopicUsersUnsubscribed, item)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("cannot save message to outbox", zap.Error(err), zap.Int("id", id))
+			return nil, err
+		}
+	}
+
+	if a.ID != 0 {
+		err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
+			AdminId:   a.ID,
+			IpAddress: a.IP,
+			EventName: admin.SendWinnerEvent,
+			OldData:   oldEvent,
+			NewData:   *selectedEvent,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	s.logger.Info("SendWinner end", zap.Int("eventID", id), zap.String("winner", winner))
+
+	var item = new(Event)
+	item.toService(selectedEvent)
+	s.setImgURL(item)
+
+	return item, nil
+}
+
+// sendDueWinners sends the winners of the A/B tests to the holdout when the time given to the variants is over
+func (s *service) sendDueWinners(ctx context.Context, currentTime time.Time) {
+	events, err := s.eventRepo.GetSent(ctx)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting sent events", zap.Error(err))
+		}
+		return
+	}
+
+	for _, e := range events {
+		if e.ABTest == nil || e.ABTest.WinnerAfter <= 0 || !strset.IsEmpty(e.ExtraData[_winnerKey]) {
+			continue
+		}
+
+		sentAt, err := time.Parse(time.RFC3339, e.ExtraData[_sentAtKey])
+		if err != nil || currentTime.Before(sentAt.Add(time.Duration(e.ABTest.WinnerAfter)*time.Minute)) {
+			continue
+		}
+
+		var countryIDs []int8
+		if e.CountryID != 0 {
+			countryIDs = append(countryIDs, e.CountryID)
+		}
+		if quietUntil, quiet := s.quietHours.UntilForCountries(currentTime, countryIDs...); quiet {
+			s.logger.Info("winner is deferred by quiet hours", zap.Int("id", e.ID), zap.Time("until", quietUntil))
+			continue
+		}
+
+		_, err = s.SendWinner(ctx, admin.Admin{}, e.ID, WinnerRequest{})
+		if err != nil {
+			s.logger.Error("err occurred during sending winner", zap.Error(err), zap.Int("id", e.ID))
+		}
+	}
+}
+
+// pickWinner returns the variant with the best share of the clicked users, the share of the opened users breaks the tie
+func (s *service) pickWinner(ctx context.Context, e *event.Event) (string, error) {
+	stats, err := s.analytics.GetStats(ctx, analytics.SourceEvent, e.ID)
+	if err != nil && !errors.Is(err, repomodel.ErrNotFound) {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during getting stats", zap.Error(err), zap.Int("eventID", e.ID))
+		return "", err
+	}
+
+	var counts = make(map[string]map[string]int, len(e.ABTest.Variants))
+	for _, stat := range stats {
+		if counts[stat.Variant] == nil {
+			counts[stat.Variant] = make(map[string]int)
+		}
+		counts[stat.Variant][stat.Action] += stat.Count
+	}
+
+	var (
+		winner            = e.ABTest.Variants[0].Key
+		bestClick, bestOp float64
+	)
+	for _, v := range e.ABTest.Variants {
+		var (
+			targeted = float64(max(counts[v.Key][analytics.ActionTargeted], 1))
+			click    = float64(counts[v.Key][analytics.ActionClicked]) / targeted
+			open     = float64(counts[v.Key][analytics.ActionOpened]) / targeted
+		)
+		if click > bestClick || (click == bestClick && open > bestOp) {
+			winner, bestClick, bestOp = v.Key, click, open
+		}
+	}
+
+	return winner, nil
+}
+
+// validateABTest checks the keys and the texts of the variants and that the percents leave the holdout non-negative
+func validateABTest(test *ABTest) error {
+	if test == nil || len(test.Variants) == 0 {
+		return nil
+	}
+
+	if len(test.Variants) < 2 || len(test.Variants) > _maxVariants {
+		return resp.Wrap(resp.ErrBadRequest, fmt.Sprintf("A/B test must have from 2 to %d variants", _maxVariants))
+	}
+
+	var total int
+	for i, v := range test.Variants {
+		if !_variantPattern.MatchString(v.Key) {
+			return resp.Wrap(resp.ErrBadRequest, "variant key is not valid, allowed: "+_variantRegex)
+		}
+		if v.Key == _holdout || slices.Contains(language.GetAll(), v.Key) {
+			return resp.Wrap(resp.ErrBadRequest, "variant key is reserved: "+v.Key)
+		}
+		if slices.ContainsFunc(test.Variants[:i], func(item Variant) bool { return item.Key == v.Key }) {
+			return resp.Wrap(resp.ErrBadRequest, "variant key is duplicated: "+v.Key)
+		}
+		if v.Percent <= 0 {
+			return resp.Wrap(resp.ErrBadRequest, "percent of the variant must be positive: "+v.Key)
+		}
+		if !v.Body.ValidAny() {
+			return resp.Wrap(resp.ErrBadRequest, "body of the variant cannot be empty: "+v.Key)
+		}
+		total += v.Percent
+	}
+
+	if total > 100 {
+		return resp.Wrap(resp.ErrBadRequest, "percents of the variants cannot exceed 100")
+	}
+	if test.WinnerAfter < 0 {
+		return resp.Wrap(resp.ErrBadRequest, "winnerAfter cannot be negative")
+	}
+	if test.WinnerAfter > 0 && total == 100 {
+		return resp.Wrap(resp.ErrBadRequest, "winnerAfter requires the holdout, the percents of the variants must be less than 100")
+	}
+
+	return nil
+}
+
+// bucket returns the variant of the user, the hash of the event and the user IDs keeps the variant of the user
+// the same whenever the audience is loaded, the users out of the percents are the holdout
+func (t *ABTest) bucket(eventID, userID int) string {
+	if t == nil || len(t.Variants) == 0 {
+		return _empty
+	}
+
+	var h = fnv.New32a()
+	_, _ = h.Write([]byte(strset.IntToStr(eventID) + ":" + strset.IntToStr(userID)))
+
+	var point = int(h.Sum32() % 100)
+	for _, v := range t.Variants {
+		if point < v.Percent {
+			return v.Key
+		}
+		point -= v.Percent
+	}
+
+	return _holdout
+}
+
+// variantTopic returns the topic of the users of the variant, e.g. `topic_v1`, the languages are appended by buildTopic
+func variantTopic(topic, variant string) string {
+	if variant == _empty {
+		return topic
+	}
+	return topic + _underscoreDelim + variant
+}
+
+// variants returns the keys of the variants sent when the event is run, the event without A/B test has the only empty one
+func variants(test *event.ABTest) []string {
+	if test == nil {
+		return []string{_empty}
+	}
+
+	var keys = make([]string, 0, len(test.Variants))
+	for _, v := range test.Variants {
+		keys = append(keys, v.Key)
+	}
+	return keys
+}
+
+// topics returns the topics of the event without the languages, the ones of the variants and the holdout if it has A/B test
+func (e *Event) topics() []string {
+	if e.ABTest == nil || len(e.ABTest.Variants) == 0 {
+		return []string{e.Topic}
+	}
+
+	var topics = make([]string, 0, len(e.ABTest.Variants)+1)
+	for _, v := range e.ABTest.Variants {
+		topics = append(topics, variantTopic(e.Topic, v.Key))
+	}
+	return append(topics, variantTopic(e.Topic, _holdout))
+}
+
+// holdout returns the percent of the users out of the variants
+func holdout(test *event.ABTest) int {
+	if test == nil {
+		return 0
+	}
+
+	var total = 100
+	for _, v := range test.Variants {
+		total -= v.Percent
+	}
+	return total
+}
+
+// texts returns the texts of the variant, the holdout gets the ones of the winner and the event without A/B test its own
+func texts(e *event.Event, variant string) (title, body, image language.Language) {
+	if e.ABTest != nil {
+		key := variant
+		if key == _holdout {
+			key = e.ExtraData[_winnerKey]
+		}
+		for _, v := range e.ABTest.Variants {
+			if v.Key != key {
+				continue
+			}
+			// the variants without images get the ones of the event
+			if len(v.Image) == 0 {
+				return v.Title, v.Body, e.Image
+			}
+			return v.Title, v.Body, v.Image
+		}
+	}
+	return e.Title, e.Body, e.Image
+}
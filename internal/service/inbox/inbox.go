@@ -0,0 +1,164 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/repo/repomodel"
+)
+
+func (s *service) GetInbox(ctx context.Context, userID int, cursor string, limit int) (Page, error) {
+	if userID <= 0 {
+		return Page{}, resp.Wrap(resp.ErrBadRequest, "invalid userID")
+	}
+
+	after, ok := decodeCursor(cursor)
+	if !ok {
+		return Page{}, resp.Wrap(resp.ErrBadRequest, "invalid cursor")
+	}
+
+	if limit <= 0 {
+		limit = _defaultLimit
+	}
+	limit = min(limit, _maxLimit)
+
+	countryID, err := s.countryID(ctx, userID)
+	if err != nil {
+		return Page{}, err
+	}
+
+	// one extra item is requested to find out if there is a next page
+	items, err := s.romRepo.GetInbox(ctx, countryID, userID, after, limit+1)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during getting inbox", zap.Error(err), zap.Int("userID", userID))
+		return Page{}, resp.ErrInternalErr
+	}
+
+	var page = Page{Items: make([]Item, 0, min(len(items), limit))}
+
+	if len(items) > limit {
+		items = items[:limit]
+		page.NextCursor = encodeCursor(items[limit-1])
+	}
+
+	for _, item := range items {
+		page.Items = append(page.Items, Item{
+			Source:    item.Source,
+			Type:      item.Type,
+			ID:        item.ID,
+			Title:     item.Title,
+			Body:      item.Body,
+			Image:     item.Image,
+			ExtraData: item.ExtraData,
+			CreatedAt: item.CreatedAt,
+			IsRead:    item.IsRead,
+		})
+	}
+
+	return page, nil
+}
+
+func (s *service) CountUnread(ctx context.Context, userID int) (int, error) {
+	if userID <= 0 {
+		return 0, resp.Wrap(resp.ErrBadRequest, "invalid userID")
+	}
+
+	countryID, err := s.countryID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.romRepo.CountUnread(ctx, countryID, userID)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during counting unread inbox", zap.Error(err), zap.Int("userID", userID))
+		return 0, resp.ErrInternalErr
+	}
+
+	return count, nil
+}
+
+func (s *service) MarkRead(ctx context.Context, userID int, source string, id int) error {
+	if userID <= 0 || id <= 0 || !isValidSource(source) {
+		return resp.Wrap(resp.ErrBadRequest, "invalid userID, source or id")
+	}
+
+	countryID, err := s.countryID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.romRepo.MarkRead(ctx, countryID, userID, source, id)
+	if err != nil {
+		if errors.Is(err, repomodel.ErrNotFound) {
+			return resp.Wrap(resp.ErrNotFound, "inbox item not found")
+		}
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during marking inbox item as read", zap.Error(err), zap.Int("userID", userID), zap.Int("id", id))
+		return resp.ErrInternalErr
+	}
+
+	return nil
+}
+
+func (s *service) MarkAllRead(ctx context.Context, userID int) error {
+	if userID <= 0 {
+		return resp.Wrap(resp.ErrBadRequest, "invalid userID")
+	}
+
+	countryID, err := s.countryID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.romRepo.MarkAllRead(ctx, countryID, userID)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during marking inbox as read", zap.Error(err), zap.Int("userID", userID))
+		return resp.ErrInternalErr
+	}
+
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, userID int, source string, id int) error {
+	if userID <= 0 || id <= 0 || !isValidSource(source) {
+		return resp.Wrap(resp.ErrBadRequest, "invalid userID, source or id")
+	}
+
+	countryID, err := s.countryID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.romRepo.DeleteInboxItem(ctx, countryID, userID, source, id)
+	if err != nil {
+		if errors.Is(err, repomodel.ErrNotFound) {
+			return resp.Wrap(resp.ErrNotFound, "inbox item not found")
+		}
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during deleting inbox item", zap.Error(err), zap.Int("userID", userID), zap.Int("id", id))
+		return resp.ErrInternalErr
+	}
+
+	return nil
+}
+
+// countryID returns the country of the user the inbox rows are partitioned by
+func (s *service) countryID(ctx context.Context, userID int) (int8, error) {
+	selectedUser, err := s.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repomodel.ErrNotFound) {
+			return 0, resp.Wrap(resp.ErrNotFound, "user not found")
+		}
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during getting user", zap.Error(err), zap.Int("userID", userID))
+		return 0, resp.ErrInternalErr
+	}
+
+	return selectedUser.CountryID, nil
+}
@@ -0,0 +1,32 @@
+package inbox
+
+import (
+	"time"
+
+	"notifications/internal/lib/language"
+)
+
+const (
+	_defaultLimit = 20
+	_maxLimit     = 100
+)
+
+const _cursorDelim = ":"
+
+type Page struct {
+	Items []Item `json:"items"`
+	// NextCursor is empty if there are no more items
+	NextCursor string `json:"nextCursor"`
+}
+
+type Item struct {
+	Source    string            `json:"source"`
+	Type      string            `json:"type"`
+	ID        int               `json:"id"`
+	Title     language.Language `json:"title"`
+	Body      language.Language `json:"body"`
+	Image     language.Language `json:"image"`
+	ExtraData map[string]string `json:"extraData"`
+	CreatedAt time.Time         `json:"createdAt"`
+	IsRead    bool              `json:"isRead"`
+}
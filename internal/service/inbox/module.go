@@ -0,0 +1,55 @@
+package inbox
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/repo/rom"
+	"notifications/internal/repo/user"
+	"notifications/pkg/lib/observer/logger"
+	"notifications/pkg/lib/observer/sentry"
+)
+
+var Module = fx.Provide(New)
+
+type Service interface {
+	reader
+	writer
+}
+
+type reader interface {
+	GetInbox(ctx context.Context, userID int, cursor string, limit int) (Page, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+}
+
+type writer interface {
+	MarkRead(ctx context.Context, userID int, source string, id int) error
+	MarkAllRead(ctx context.Context, userID int) error
+	Delete(ctx context.Context, userID int, source string, id int) error
+}
+
+type Params struct {
+	fx.In
+
+	Logger   logger.Logger
+	Sentry   sentry.Sentry
+	RomRepo  rom.Repo
+	UserRepo user.Repo
+}
+
+type service struct {
+	logger   logger.Logger
+	sentry   sentry.Sentry
+	romRepo  rom.Repo
+	userRepo user.Repo
+}
+
+func New(p Params) Service {
+	return &service{
+		logger:   p.Logger,
+		sentry:   p.Sentry,
+		romRepo:  p.RomRepo,
+		userRepo: p.UserRepo,
+	}
+}
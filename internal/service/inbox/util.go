@@ -0,0 +1,51 @@
+package inbox
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"notifications/internal/repo/rom"
+)
+
+// encodeCursor builds an opaque cursor from the last item of the page
+func encodeCursor(item rom.InboxItem) string {
+	var raw = strconv.FormatInt(item.CreatedAt.UnixMicro(), 10) + _cursorDelim + strconv.Itoa(item.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (rom.InboxCursor, bool) {
+	if cursor == "" {
+		return rom.InboxCursor{}, true
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return rom.InboxCursor{}, false
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), _cursorDelim)
+	if !found {
+		return rom.InboxCursor{}, false
+	}
+
+	micro, err := strconv.ParseInt(createdAt, 10, 64)
+	if err != nil {
+		return rom.InboxCursor{}, false
+	}
+
+	itemID, err := strconv.Atoi(id)
+	if err != nil || itemID == 0 {
+		return rom.InboxCursor{}, false
+	}
+
+	return rom.InboxCursor{
+		CreatedAt: time.UnixMicro(micro),
+		ID:        itemID,
+	}, true
+}
+
+func isValidSource(source string) bool {
+	return source == rom.SourcePush || source == rom.SourceEvent
+}
@@ -0,0 +1,190 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/user"
+	"notifications/internal/service/email"
+	"notifications/internal/service/push"
+	"notifications/internal/service/sms"
+	"notifications/internal/service/telegram"
+	"notifications/internal/service/template"
+	"notifications/pkg/util/strset"
+)
+
+// errSkipped is returned if the message cannot be sent through the channel, e.g. the recipient has no address in it
+var errSkipped = errors.New("skipped")
+
+const (
+	_pushType = "pushType"
+	_otp      = "otp"
+	_push     = "push"
+)
+
+// sendTo sends the message through the channel and returns the ID of the sent message if the channel has one
+func (s *service) sendTo(ctx context.Context, request *Request, channel string) (string, error) {
+	recipient, err := s.recipient(ctx, request)
+	if err != nil {
+		return "", err
+	}
+
+	if recipient != nil && channel != ChannelTelegram && user.Suppressible(request.Category) {
+		optedOut, err := s.userRepo.IsOptedOut(ctx, recipient.UserID, request.Category, channel)
+		if err != nil {
+			s.logger.Error("err occurred during getting preference", zap.Error(err), zap.Int("userID", recipient.UserID))
+			return "", err
+		}
+		if optedOut {
+			return "", skip("user opted out of the channel")
+		}
+	}
+
+	switch channel {
+	case ChannelPush:
+		return s.sendPush(ctx, request, recipient)
+	case ChannelSms:
+		return "", s.sendSms(ctx, request, recipient)
+	case ChannelEmail:
+		return "", s.sendEmail(ctx, request, recipient)
+	default:
+		return "", s.sendTelegram(ctx, request)
+	}
+}
+
+// sendPush sends the push synchronously, the push fails if the user has no token, disabled pushes or FCM fails
+func (s *service) sendPush(ctx context.Context, request *Request, recipient *user.User) (string, error) {
+	if recipient == nil {
+		return "", skip("user not found")
+	}
+
+	var data = make(map[string]string, len(request.Data)+3)
+	maps.Copy(data, request.Data)
+	data[_pushType] = _push
+	if request.Category == user.CategoryOTP {
+		data[_pushType] = _otp
+	}
+	if strset.IsEmpty(request.TemplateKey) {
+		data[_title] = request.Title
+		data[_message] = request.Text
+	}
+
+	var pushRequest = new(push.Request)
+	pushRequest.InternalRequest.UserID = recipient.UserID
+	pushRequest.InternalRequest.Data = data
+	pushRequest.InternalRequest.TemplateKey = request.TemplateKey
+	pushRequest.InternalRequest.Variables = request.Variables
+	pushRequest.IsInternal = true
+	pushRequest.Sync = true
+
+	messageID, err := s.push.Send(ctx, pushRequest)
+	if err != nil {
+		return "", err
+	}
+	if strset.IsEmpty(messageID) {
+		return "", errors.New("push is not sent")
+	}
+
+	return messageID, nil
+}
+
+func (s *service) sendSms(ctx context.Context, request *Request, recipient *user.User) error {
+	var phone = request.Recipient.Phone
+	if strset.IsEmpty(phone) && recipient != nil {
+		phone = recipient.Phone
+	}
+	if strset.IsEmpty(phone) {
+		return skip("recipient has no phone")
+	}
+
+	return s.sms.Send(ctx, sms.Message{
+		Phone:       phone,
+		Text:        request.Text,
+		TemplateKey: request.TemplateKey,
+		Language:    request.Language,
+		UserID:      request.Recipient.UserID,
+		Variables:   request.Variables,
+	})
+}
+
+func (s *service) sendEmail(ctx context.Context, request *Request, recipient *user.User) error {
+	if strset.IsEmpty(request.Recipient.Email) {
+		return skip("recipient has no email")
+	}
+
+	var userID int
+	if recipient != nil {
+		userID = recipient.UserID
+	}
+
+	return s.email.Send(ctx, email.Email{
+		Body: map[string]string{
+			_userEmail: request.Recipient.Email,
+			_subject:   request.Title,
+			_text:      request.Text,
+		},
+		TemplateKey: request.TemplateKey,
+		Language:    request.Language,
+		UserID:      userID,
+		Variables:   request.Variables,
+	})
+}
+
+// sendTelegram sends the text to the chat, the template is rendered here as the telegram service sends the text as is
+func (s *service) sendTelegram(ctx context.Context, request *Request) error {
+	if request.Recipient.ChatID == 0 {
+		return skip("recipient has no telegram chat")
+	}
+
+	var text = request.Text
+	if !strset.IsEmpty(request.TemplateKey) {
+		content, err := s.templates.Render(ctx, request.TemplateKey, template.Recipient{
+			UserID:   request.Recipient.UserID,
+			Phone:    request.Recipient.Phone,
+			Language: request.Language,
+		}, request.Variables)
+		if err != nil {
+			return err
+		}
+		text = content.Body
+	}
+
+	return s.telegram.Send(ctx, telegram.Message{
+		ChatID: request.Recipient.ChatID,
+		Text:   text,
+		Bot:    request.Recipient.Bot,
+	})
+}
+
+// recipient returns the user of the request, nil if the user is not found
+func (s *service) recipient(ctx context.Context, request *Request) (*user.User, error) {
+	var (
+		selectedUser *user.User
+		err          error
+	)
+
+	if request.Recipient.UserID != 0 {
+		selectedUser, err = s.userRepo.GetByUserID(ctx, request.Recipient.UserID)
+	} else {
+		selectedUser, err = s.userRepo.GetActiveByPhone(ctx, request.Recipient.Phone)
+	}
+	if err != nil {
+		if errors.Is(err, repomodel.ErrNotFound) {
+			return nil, nil
+		}
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during getting user", zap.Error(err), zap.Int("userID", request.Recipient.UserID))
+		return nil, err
+	}
+
+	return selectedUser, nil
+}
+
+func skip(reason string) error {
+	return fmt.Errorf("%w: %s", errSkipped, reason)
+}
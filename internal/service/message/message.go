@@ -0,0 +1,155 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/repo/message"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/user"
+	"notifications/pkg/util/strset"
+)
+
+// Send tries the channels of the policy in the order until the message is delivered, the record is saved after every
+// attempt, so the redelivered request is continued from the channel it was interrupted at
+func (s *service) Send(ctx context.Context, request *Request) (*Message, error) {
+	err := validate(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy = make([]message.Step, 0, len(request.Policy))
+	for _, step := range request.Policy {
+		policy = append(policy, message.Step{Channel: step.Channel, Timeout: step.Timeout})
+	}
+
+	item, err := s.messageRepo.Create(ctx, &message.Message{
+		ID:        int(s.idGenerator.Generate().Int64()),
+		RequestID: request.ID,
+		UserID:    request.Recipient.UserID,
+		Phone:     request.Recipient.Phone,
+		Policy:    policy,
+		Attempts:  []message.Attempt{},
+		Status:    _pending,
+	})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during saving message", zap.Error(err), zap.String("requestID", request.ID))
+		return nil, err
+	}
+
+	for item.Status == _pending && len(item.Attempts) < len(item.Policy) {
+		var (
+			step    = item.Policy[len(item.Attempts)]
+			attempt = s.attempt(ctx, request, Step{Channel: step.Channel, Timeout: step.Timeout})
+		)
+
+		item.Attempts = append(item.Attempts, attempt)
+		switch {
+		case attempt.Status == _sent:
+			item.Status, item.Channel = _delivered, attempt.Channel
+		case len(item.Attempts) == len(item.Policy):
+			item.Status = _failed
+		}
+
+		err = s.messageRepo.Update(ctx, item)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during updating message", zap.Error(err), zap.String("requestID", request.ID))
+			return nil, err
+		}
+
+		s.logger.Info("message attempt", zap.String("requestID", request.ID), zap.String("channel", attempt.Channel),
+			zap.String("status", attempt.Status), zap.String("error", attempt.Error))
+	}
+
+	var result = new(Message)
+	result.toService(item)
+
+	return result, nil
+}
+
+func (s *service) GetByRequestID(ctx context.Context, requestID string) (*Message, error) {
+	item, err := s.messageRepo.GetByRequestID(ctx, requestID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting message", zap.Error(err), zap.String("requestID", requestID))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "message not found")
+	}
+
+	var result = new(Message)
+	result.toService(item)
+
+	return result, nil
+}
+
+// attempt sends the message through the channel within the timeout of the step
+func (s *service) attempt(ctx context.Context, request *Request, step Step) message.Attempt {
+	var attempt = message.Attempt{
+		Channel:   step.Channel,
+		StartedAt: time.Now().UTC(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, step.timeout())
+	defer cancel()
+
+	messageID, err := s.sendTo(ctx, request, step.Channel)
+	switch {
+	case errors.Is(err, errSkipped):
+		attempt.Status, attempt.Error = _skipped, err.Error()
+	case err != nil:
+		attempt.Status, attempt.Error = _failed, err.Error()
+	default:
+		attempt.Status, attempt.MessageID = _sent, messageID
+	}
+
+	attempt.FinishedAt = time.Now().UTC()
+	return attempt
+}
+
+func validate(request *Request) error {
+	if strset.IsEmpty(request.ID) {
+		return resp.Wrap(resp.ErrBadRequest, "id cannot be empty")
+	}
+
+	if request.Recipient.UserID == 0 && strset.IsEmpty(request.Recipient.Phone) {
+		return resp.Wrap(resp.ErrBadRequest, "userID or phone must be set")
+	}
+
+	if strset.IsEmpty(request.TemplateKey) && strset.IsEmpty(request.Text) {
+		return resp.Wrap(resp.ErrBadRequest, "templateKey or text must be set")
+	}
+
+	if request.Category == "" {
+		request.Category = user.CategoryTransactional
+	}
+	if !slices.Contains([]string{user.CategoryOTP, user.CategoryTransactional, user.CategoryMarketing}, request.Category) {
+		return resp.Wrap(resp.ErrBadRequest, "category is not valid: "+request.Category)
+	}
+
+	if len(request.Policy) == 0 {
+		return resp.Wrap(resp.ErrBadRequest, "policy cannot be empty")
+	}
+
+	var channels = make([]string, 0, len(request.Policy))
+	for _, step := range request.Policy {
+		if !slices.Contains([]string{ChannelPush, ChannelSms, ChannelEmail, ChannelTelegram}, step.Channel) {
+			return resp.Wrap(resp.ErrBadRequest, "channel is not valid: "+step.Channel)
+		}
+		if slices.Contains(channels, step.Channel) {
+			return resp.Wrap(resp.ErrBadRequest, fmt.Sprintf("channel %s is given twice", step.Channel))
+		}
+		channels = append(channels, step.Channel)
+	}
+
+	return nil
+}
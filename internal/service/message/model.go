@@ -0,0 +1,122 @@
+package message
+
+import (
+	"time"
+
+	"notifications/internal/repo/message"
+	"notifications/internal/repo/user"
+)
+
+// Channels of the policy
+const (
+	ChannelPush     = user.ChannelPush
+	ChannelSms      = user.ChannelSms
+	ChannelEmail    = user.ChannelEmail
+	ChannelTelegram = "telegram"
+)
+
+// Statuses of the message
+const (
+	_pending   = "pending"
+	_delivered = "delivered"
+	_failed    = "failed"
+)
+
+// Statuses of the attempts, the channel is skipped if the recipient has no address in it or opted out of it
+const (
+	_sent    = "sent"
+	_skipped = "skipped"
+)
+
+const (
+	_defaultTimeout = 30 * time.Second
+	_maxTimeout     = 2 * time.Minute
+	_subject        = "subject"
+	_userEmail      = "userEmail"
+	_text           = "text"
+	_title          = "title"
+	_message        = "message"
+)
+
+type Request struct {
+	// ID is the unique ID of the request given by the caller, the message of the same ID is sent once
+	ID        string
+	Recipient Recipient
+	Policy    []Step
+	// Category is the category of the preferences of the user, transactional by default
+	Category string
+	// TemplateKey and Variables are rendered to the Title and Text in the language of the recipient
+	TemplateKey string
+	Variables   map[string]any
+	Language    string
+	Title       string
+	Text        string
+	// Data is sent with the push
+	Data map[string]string
+}
+
+// Recipient is the user referred by UserID or Phone with the addresses of the channels which are not kept by the service
+type Recipient struct {
+	UserID int
+	Phone  string
+	Email  string
+	ChatID int64
+	Bot    string
+}
+
+type Step struct {
+	Channel string `json:"channel"`
+	// Timeout is the number of seconds the channel is given to deliver the message, 30 by default
+	Timeout int `json:"timeout,omitempty"`
+}
+
+type Message struct {
+	ID        int       `json:"id"`
+	RequestID string    `json:"requestID"`
+	UserID    int       `json:"userID,omitempty"`
+	Phone     string    `json:"phone,omitempty"`
+	Policy    []Step    `json:"policy"`
+	Attempts  []Attempt `json:"attempts"`
+	Status    string    `json:"status"`
+	Channel   string    `json:"channel,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Attempt struct {
+	Channel    string    `json:"channel"`
+	Status     string    `json:"status"`
+	MessageID  string    `json:"messageID,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+func (m *Message) toService(item *message.Message) {
+	m.ID = item.ID
+	m.RequestID = item.RequestID
+	m.UserID = item.UserID
+	m.Phone = item.Phone
+	m.Status = item.Status
+	m.Channel = item.Channel
+	m.CreatedAt = item.CreatedAt
+	m.UpdatedAt = item.UpdatedAt
+
+	m.Policy = make([]Step, 0, len(item.Policy))
+	for _, step := range item.Policy {
+		m.Policy = append(m.Policy, Step{Channel: step.Channel, Timeout: step.Timeout})
+	}
+
+	m.Attempts = make([]Attempt, 0, len(item.Attempts))
+	for _, attempt := range item.Attempts {
+		m.Attempts = append(m.Attempts, Attempt(attempt))
+	}
+}
+
+// timeout returns the time the channel is given, the default one if it's not set
+func (s Step) timeout() time.Duration {
+	if s.Timeout <= 0 {
+		return _defaultTimeout
+	}
+	return min(time.Duration(s.Timeout)*time.Second, _maxTimeout)
+}
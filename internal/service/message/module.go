@@ -0,0 +1,86 @@
+package message
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+
+	"github.com/bwmarrin/snowflake"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"notifications/internal/repo/message"
+	"notifications/internal/repo/user"
+	"notifications/internal/service/email"
+	"notifications/internal/service/push"
+	"notifications/internal/service/sms"
+	"notifications/internal/service/telegram"
+	"notifications/internal/service/template"
+	"notifications/pkg/lib/config"
+	"notifications/pkg/lib/observer/logger"
+	"notifications/pkg/lib/observer/sentry"
+)
+
+var Module = fx.Provide(New)
+
+// Service sends the message to the recipient through the channels of the policy one by one, the next channel
+// is tried if the previous one fails, the outcome of every channel is saved to the message record
+type Service interface {
+	Send(context.Context, *Request) (*Message, error)
+	GetByRequestID(ctx context.Context, requestID string) (*Message, error)
+}
+
+type Params struct {
+	fx.In
+
+	Config      config.Config
+	Logger      logger.Logger
+	Sentry      sentry.Sentry
+	MessageRepo message.Repo
+	UserRepo    user.Repo
+	Push        push.Service
+	Sms         sms.Service
+	Email       email.Service
+	Telegram    telegram.Service
+	Templates   template.Service
+}
+
+type service struct {
+	logger      logger.Logger
+	sentry      sentry.Sentry
+	messageRepo message.Repo
+	userRepo    user.Repo
+	push        push.Service
+	sms         sms.Service
+	email       email.Service
+	telegram    telegram.Service
+	templates   template.Service
+	idGenerator *snowflake.Node
+}
+
+func New(p Params) Service {
+	hash := sha1.Sum([]byte(p.Config.GetString("podName")))
+	podID := int64(binary.LittleEndian.Uint64(hash[:8]) % 1024)
+	if podID == 0 {
+		podID = 1
+	}
+
+	idGenerator, err := snowflake.NewNode(podID)
+	if err != nil {
+		p.Logger.Error("snowflake.NewNode", zap.Error(err))
+		return nil
+	}
+
+	return &service{
+		logger:      p.Logger,
+		sentry:      p.Sentry,
+		messageRepo: p.MessageRepo,
+		userRepo:    p.UserRepo,
+		push:        p.Push,
+		sms:         p.Sms,
+		email:       p.Email,
+		telegram:    p.Telegram,
+		templates:   p.Templates,
+		idGenerator: idGenerator,
+	}
+}
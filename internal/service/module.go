@@ -4,11 +4,19 @@ import (
 	"go.uber.org/fx"
 
 	"notifications/internal/service/admin"
+	"notifications/internal/service/analytics"
+	"notifications/internal/service/apiclient"
+	"notifications/internal/service/dlq"
 	"notifications/internal/service/email"
 	"notifications/internal/service/event"
+	"notifications/internal/service/inbox"
+	"notifications/internal/service/message"
+	"notifications/internal/service/outbox"
 	"notifications/internal/service/push"
+	"notifications/internal/service/quiethours"
 	"notifications/internal/service/sms"
 	"notifications/internal/service/telegram"
+	"notifications/internal/service/template"
 	"notifications/internal/service/user"
 )
 
@@ -20,4 +28,12 @@ var Module = fx.Options(
 	email.Module,
 	telegram.Module,
 	sms.Module,
+	apiclient.Module,
+	inbox.Module,
+	outbox.Module,
+	dlq.Module,
+	template.Module,
+	quiethours.Module,
+	analytics.Module,
+	message.Module,
 )
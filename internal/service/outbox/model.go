@@ -0,0 +1,10 @@
+package outbox
+
+import "time"
+
+const _batchSize = 100
+
+const (
+	_baseBackoff = time.Second
+	_maxBackoff  = 10 * time.Minute
+)
@@ -0,0 +1,47 @@
+package outbox
+
+import (
+	"go.uber.org/fx"
+
+	"notifications/internal/db/tx"
+	"notifications/internal/repo/rom"
+	"notifications/pkg/lib/broker/nats"
+	"notifications/pkg/lib/observer/logger"
+	"notifications/pkg/lib/observer/sentry"
+)
+
+var Module = fx.Provide(New)
+
+type Service interface {
+	// Relay delivers pending outbox messages to the rom db and the broker.
+	// Failed messages are retried with exponential backoff, so each message is delivered at least once
+	Relay()
+}
+
+type Params struct {
+	fx.In
+
+	Logger     logger.Logger
+	Sentry     sentry.Sentry
+	Nats       nats.Event
+	RomRepo    rom.Repo
+	Transactor tx.Transactor
+}
+
+type service struct {
+	logger     logger.Logger
+	sentry     sentry.Sentry
+	nats       nats.Event
+	romRepo    rom.Repo
+	transactor tx.Transactor
+}
+
+func New(p Params) Service {
+	return &service{
+		logger:     p.Logger,
+		sentry:     p.Sentry,
+		nats:       p.Nats,
+		romRepo:    p.RomRepo,
+		transactor: p.Transactor,
+	}
+}
@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+
+	"notifications/internal/repo/outbox"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/rom"
+)
+
+func (s *service) Relay() {
+	var ctx = context.Background()
+
+	for {
+		count, err := s.relayBatch(ctx)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during relaying outbox", zap.Error(err))
+			return
+		}
+		if count < _batchSize {
+			return
+		}
+	}
+}
+
+func (s *service) relayBatch(ctx context.Context) (count int, err error) {
+	tx := s.transactor.New()
+	err = tx.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			if errX := tx.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	messages, err := tx.OutboxRepo().GetPending(ctx, _batchSize)
+	if err != nil {
+		if errors.Is(err, repomodel.ErrNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	for _, message := range messages {
+		dErr := s.deliver(ctx, message)
+		if dErr != nil {
+			s.logger.Warning("outbox message delivery failed",
+				zap.Error(dErr),
+				zap.Int("id", message.ID),
+				zap.String("kind", message.Kind),
+				zap.Int("attempts", message.Attempts))
+
+			err = tx.OutboxRepo().Reschedule(ctx, message.ID, dErr.Error(), time.Now().Add(backoff(message.Attempts)))
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		err = tx.OutboxRepo().Delete(ctx, message.ID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(messages), nil
+}
+
+func (s *service) deliver(ctx context.Context, message *outbox.Message) error {
+	switch message.Kind {
+	case outbox.KindRomInbox:
+		var inbox rom.Inbox
+		if err := sonic.Unmarshal(message.Payload, &inbox); err != nil {
+			return err
+		}
+		return s.romRepo.InsertInbox(ctx, &inbox)
+	case outbox.KindRomEvent:
+		var payload outbox.RomEvent
+		if err := sonic.Unmarshal(message.Payload, &payload); err != nil {
+			return err
+		}
+		return s.romRepo.BatchInsert(ctx, payload.EventID, payload.UserIDs, payload.CountryIDs, &payload.Event)
+	case outbox.KindBroker:
+		return s.nats.Publish(message.Stream, message.Subject, json.RawMessage(message.Payload))
+	default:
+		return errors.New("unknown outbox message kind: " + message.Kind)
+	}
+}
+
+func backoff(attempts int) time.Duration {
+	if attempts >= 10 {
+		return _maxBackoff
+	}
+	return min(_baseBackoff<<attempts, _maxBackoff)
+}
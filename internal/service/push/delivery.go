@@ -0,0 +1,93 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/snowflake"
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/repo/delivery"
+	"notifications/internal/repo/repomodel"
+	usersrv "notifications/internal/service/user"
+	"notifications/pkg/lib/notifier/firebase"
+	"notifications/pkg/lib/observer/logger"
+	"notifications/pkg/lib/observer/sentry"
+)
+
+// tracker keeps the delivery log of the pushes. Tracking errors are logged but don't fail the push itself
+type tracker struct {
+	logger       logger.Logger
+	sentry       sentry.Sentry
+	deliveryRepo delivery.Repo
+	idGenerator  *snowflake.Node
+}
+
+// queue inserts the delivery of the push, the push deferred by quiet hours continues the delivery queued before (deliveryID)
+func (t *tracker) queue(ctx context.Context, deliveryID, userID int, requestID, apiClient string) *delivery.Delivery {
+	var item = &delivery.Delivery{
+		ID:        deliveryID,
+		UserID:    userID,
+		RequestID: requestID,
+		APIClient: apiClient,
+		Status:    DeliveryQueued,
+	}
+
+	if deliveryID != 0 {
+		return item
+	}
+
+	item.ID = int(t.idGenerator.Generate().Int64())
+
+	err := t.deliveryRepo.Insert(ctx, item)
+	if err != nil {
+		t.sentry.CaptureException(err)
+		t.logger.Error("err occurred during inserting delivery", zap.Error(err), zap.Int("userID", userID), zap.String("requestID", requestID))
+	}
+
+	return item
+}
+
+// complete sets the final status of the delivery, if err is not nil the status is failed or token_invalid depending on the fcm error
+func (t *tracker) complete(ctx context.Context, item *delivery.Delivery, status, messageID string, err error) {
+	item.Status = status
+	item.FcmMessageID = messageID[strings.LastIndex(messageID, _slashDelim)+1:]
+
+	if err != nil {
+		item.Status = DeliveryFailed
+		if usersrv.IsInvalidTokenErr(err) {
+			item.Status = DeliveryTokenInvalid
+		}
+		item.ErrorCode = firebase.ErrCode(err)
+		item.ErrorMessage = err.Error()
+	}
+
+	if uErr := t.deliveryRepo.Update(ctx, item); uErr != nil {
+		t.sentry.CaptureException(uErr)
+		t.logger.Error("err occurred during updating delivery", zap.Error(uErr), zap.Int("deliveryID", item.ID), zap.String("status", item.Status))
+	}
+}
+
+func (t *tracker) GetDelivery(ctx context.Context, apiClient, requestID string) (*Delivery, error) {
+	item, err := t.deliveryRepo.GetByRequestID(ctx, apiClient, requestID)
+	if err != nil {
+		if errors.Is(err, repomodel.ErrNotFound) {
+			return nil, resp.Wrap(resp.ErrNotFound, "push not found")
+		}
+		t.sentry.CaptureException(err)
+		t.logger.Error("err occurred during getting delivery", zap.Error(err), zap.String("requestID", requestID))
+		return nil, err
+	}
+
+	return &Delivery{
+		RequestID:    item.RequestID,
+		Status:       item.Status,
+		MessageID:    item.FcmMessageID,
+		ErrorCode:    item.ErrorCode,
+		ErrorMessage: item.ErrorMessage,
+		CreatedAt:    item.CreatedAt,
+		UpdatedAt:    item.UpdatedAt,
+	}, nil
+}
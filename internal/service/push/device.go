@@ -0,0 +1,243 @@
+package push
+
+import (
+	"context"
+	"slices"
+
+	"firebase.google.com/go/v4/messaging"
+	"github.com/bytedance/sonic"
+
+	"notifications/internal/repo/user"
+	usersrv "notifications/internal/service/user"
+	"notifications/pkg/lib/notifier/apns"
+	"notifications/pkg/lib/notifier/firebase"
+	"notifications/pkg/lib/notifier/hms"
+	"notifications/pkg/lib/notifier/webpush"
+	"notifications/pkg/lib/observer/logger"
+	"notifications/pkg/lib/observer/sentry"
+)
+
+// devices sends the pushes to all the active devices of the user by their providers, the tokens rejected by
+// the provider are published to be removed
+type devices struct {
+	logger     logger.Logger
+	sentry     sentry.Sentry
+	users      usersrv.Service
+	fcmSender  firebase.Sender
+	hmsSender  hms.Sender
+	apnsSender apns.Sender
+	webSender  webpush.Sender
+}
+
+// sendDevices sends the message to the devices and returns the ID of the message of the first provider which got it,
+// the error of the last provider if none of them got it
+func (d *devices) sendDevices(ctx context.Context, userID int, devices []user.DeviceToken, message *messaging.Message) (string, error) {
+	if len(devices) == 0 {
+		return "", errNoDevices
+	}
+
+	var (
+		messageID string
+		err       error
+	)
+
+	for provider, tokens := range user.ByProvider(devices) {
+		var (
+			id   string
+			errX error
+		)
+		switch provider {
+		case user.ProviderHms:
+			id, errX = d.sendHms(ctx, userID, tokens, message)
+		case user.ProviderApns:
+			id, errX = d.sendApns(ctx, userID, tokens, message)
+		case user.ProviderWebPush:
+			id, errX = d.sendWebPush(ctx, userID, devices, message)
+		default:
+			id, errX = d.sendFcm(ctx, userID, tokens, message)
+		}
+		if errX != nil {
+			err = errX
+			continue
+		}
+		if messageID == "" {
+			messageID = id
+		}
+	}
+
+	if messageID != "" {
+		return messageID, nil
+	}
+
+	return "", err
+}
+
+func (d *devices) sendFcm(ctx context.Context, userID int, tokens []string, message *messaging.Message) (string, error) {
+	if len(tokens) == 1 {
+		message.Token = tokens[0]
+
+		messageID, err := d.fcmSender.SendPush(ctx, message)
+		if err != nil && usersrv.IsInvalidTokenErr(err) {
+			d.users.PublishTokenRemoved(userID, tokens[0])
+		}
+		return messageID, err
+	}
+
+	response, err := d.fcmSender.SendMulticast(ctx, &messaging.MulticastMessage{
+		Tokens:       tokens,
+		Data:         message.Data,
+		Notification: message.Notification,
+		Android:      message.Android,
+		Webpush:      message.Webpush,
+		APNS:         message.APNS,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var messageID string
+	for i, res := range response.Responses {
+		if res.Success {
+			if messageID == "" {
+				messageID = res.MessageID
+			}
+			continue
+		}
+
+		err = res.Error
+		if usersrv.IsInvalidTokenErr(res.Error) {
+			d.users.PublishTokenRemoved(userID, tokens[i])
+		}
+	}
+
+	if messageID != "" {
+		return messageID, nil
+	}
+
+	return "", err
+}
+
+// sendHms sends the message to the HMS tokens, the message without android config is delivered to the app only
+func (d *devices) sendHms(ctx context.Context, userID int, tokens []string, message *messaging.Message) (string, error) {
+	var hmsMessage = &hms.Message{Data: message.Data, Urgency: hms.UrgencyHigh}
+	if message.Android != nil {
+		hms.NotificationMSG(hmsMessage, message.Data, urgency(message.Android.Priority))
+	}
+	hmsMessage.Tokens = tokens
+
+	response, err := d.hmsSender.Send(ctx, hmsMessage)
+	if err != nil {
+		if usersrv.IsInvalidTokenErr(err) {
+			for _, token := range tokens {
+				d.users.PublishTokenRemoved(userID, token)
+			}
+		}
+		return "", err
+	}
+
+	for _, token := range response.IllegalTokens {
+		d.users.PublishTokenRemoved(userID, token)
+	}
+
+	if response.SuccessCount == 0 {
+		return "", &hms.Error{Code: hms.CodeAllTokensInvalid, RequestID: response.RequestID}
+	}
+
+	return response.RequestID, nil
+}
+
+// sendApns sends the message to the APNs tokens one by one, the message without APNs config is delivered to the app only
+func (d *devices) sendApns(ctx context.Context, userID int, tokens []string, message *messaging.Message) (string, error) {
+	var notification = new(apns.Notification)
+	if message.APNS != nil {
+		apns.AlertMSG(notification, message.Data, priority(message.APNS.Headers[_apnsPriority]))
+	} else {
+		apns.BackgroundMSG(notification, message.Data)
+	}
+
+	var (
+		messageID string
+		err       error
+	)
+	for _, token := range tokens {
+		notification.Token = token
+
+		response, errX := d.apnsSender.Send(ctx, notification)
+		if errX != nil {
+			err = errX
+			if usersrv.IsInvalidTokenErr(errX) {
+				d.users.PublishTokenRemoved(userID, token)
+			}
+			continue
+		}
+		if messageID == "" {
+			messageID = response.ApnsID
+		}
+	}
+
+	if messageID != "" {
+		return messageID, nil
+	}
+
+	return "", err
+}
+
+// sendWebPush sends the data of the message to the browser subscriptions of the devices, the service worker of the web
+// client shows it. The expired subscriptions are published to be removed
+func (d *devices) sendWebPush(ctx context.Context, userID int, devices []user.DeviceToken, message *messaging.Message) (string, error) {
+	payload, err := sonic.Marshal(message.Data)
+	if err != nil {
+		return "", err
+	}
+
+	var webMessage = &webpush.Message{Payload: payload, Urgency: webpush.UrgencyNormal}
+	if message.Android != nil && message.Android.Priority == firebase.AndroidHighestPriority {
+		webMessage.Urgency = webpush.UrgencyHigh
+	}
+
+	var messageID string
+	for _, device := range devices {
+		if device.Provider != user.ProviderWebPush {
+			continue
+		}
+
+		response, errX := d.webSender.Send(ctx, &webpush.Subscription{Endpoint: device.Token, P256dh: device.P256dh, Auth: device.Auth}, webMessage)
+		if errX != nil {
+			err = errX
+			if usersrv.IsInvalidTokenErr(errX) {
+				d.users.PublishTokenRemoved(userID, device.Token)
+			}
+			continue
+		}
+		if messageID == "" {
+			messageID = response.Location
+		}
+	}
+
+	if messageID != "" {
+		return messageID, nil
+	}
+
+	return "", err
+}
+
+// priority returns the APNs priority of the apns-priority header of FCM
+func priority(header string) int {
+	if header == firebase.ApnsNormalPriority {
+		return apns.PriorityNormal
+	}
+	return apns.PriorityHigh
+}
+
+// urgency returns the HMS urgency of the android priority of FCM
+func urgency(priority string) string {
+	if priority == firebase.AndroidHighestPriority {
+		return hms.UrgencyHigh
+	}
+	return hms.UrgencyNormal
+}
+
+// hasDevice reports if the token is of one of the devices
+func hasDevice(devices []user.DeviceToken, token string) bool {
+	return slices.ContainsFunc(devices, func(device user.DeviceToken) bool { return device.Token == token })
+}
@@ -0,0 +1,151 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"firebase.google.com/go/v4/messaging"
+
+	"notifications/internal/repo/user"
+	usersrv "notifications/internal/service/user"
+	"notifications/pkg/lib/notifier/webpush"
+	"notifications/pkg/lib/observer/logger"
+)
+
+type fakeUsers struct {
+	usersrv.Service
+	removed []string
+}
+
+func (f *fakeUsers) PublishTokenRemoved(_ int, token string) {
+	f.removed = append(f.removed, token)
+}
+
+func Test_sendWebPush(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/created":
+			w.Header().Set("Location", "https://push.example.com/message/1")
+			w.WriteHeader(http.StatusCreated)
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/throttled":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		paths     []string
+		messageID string
+		expired   bool
+		removed   []string
+	}{
+		{
+			name:      "created",
+			paths:     []string{"/created"},
+			messageID: "https://push.example.com/message/1",
+		},
+		{
+			name:      "expired subscriptions are removed",
+			paths:     []string{"/gone", "/created", "/unknown"},
+			messageID: "https://push.example.com/message/1",
+			removed:   []string{srv.URL + "/gone", srv.URL + "/unknown"},
+		},
+		{
+			name:    "all subscriptions expired",
+			paths:   []string{"/gone"},
+			expired: true,
+			removed: []string{srv.URL + "/gone"},
+		},
+		{
+			name:  "throttled subscription is kept",
+			paths: []string{"/throttled"},
+		},
+	}
+
+	var sender = newWebPush(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				users   = new(fakeUsers)
+				d       = &devices{logger: logger.NewNop(), users: users, webSender: sender}
+				tokens  = []user.DeviceToken{{Token: "fcm", Provider: user.ProviderFcm}}
+				message = &messaging.Message{Data: map[string]string{"title": "title"}}
+			)
+			for _, path := range tt.paths {
+				tokens = append(tokens, newDeviceToken(t, srv.URL+path))
+			}
+
+			messageID, err := d.sendWebPush(context.Background(), 1, tokens, message)
+
+			if messageID != tt.messageID {
+				t.Errorf("messageID = %s, want %s", messageID, tt.messageID)
+			}
+			if (tt.messageID == "") != (err != nil) {
+				t.Errorf("err = %v", err)
+			}
+			if expired := webpush.IsExpiredErr(err); expired != tt.expired {
+				t.Errorf("IsExpiredErr = %v, want %v", expired, tt.expired)
+			}
+			if !reflect.DeepEqual(users.removed, tt.removed) {
+				t.Errorf("removed = %q, want %q", users.removed, tt.removed)
+			}
+		})
+	}
+}
+
+func newWebPush(t *testing.T) webpush.Sender {
+	t.Helper()
+
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return webpush.NewClient(webpush.Options{
+		PublicKey:  base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		PrivateKey: base64.RawURLEncoding.EncodeToString(key.Bytes()),
+		Subject:    "mailto:push@example.com",
+	}, logger.NewNop())
+}
+
+// newDeviceToken is the browser subscription of the endpoint with the random keys
+func newDeviceToken(t *testing.T, endpoint string) user.DeviceToken {
+	t.Helper()
+
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var auth = make([]byte, 16)
+	_, _ = rand.Read(auth)
+
+	return user.DeviceToken{
+		Token:    endpoint,
+		Provider: user.ProviderWebPush,
+		P256dh:   base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(auth),
+	}
+}
+
+func Test_sendDevices_NoDevices(t *testing.T) {
+	var d = &devices{logger: logger.NewNop()}
+
+	messageID, err := d.sendDevices(context.Background(), 1, nil, &messaging.Message{})
+	if messageID != "" || !errors.Is(err, errNoDevices) {
+		t.Errorf("sendDevices = %q, %v, want %v", messageID, err, errNoDevices)
+	}
+}
@@ -4,20 +4,22 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"firebase.google.com/go/v4/messaging"
 	"github.com/bwmarrin/snowflake"
 	"go.uber.org/zap"
 
 	"notifications/internal/api/resp"
-	"notifications/internal/api/transport/broker/stream"
-	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/db/tx"
+	"notifications/internal/lib/country"
 	"notifications/internal/lib/language"
+	"notifications/internal/repo/delivery"
 	"notifications/internal/repo/push"
 	"notifications/internal/repo/repomodel"
-	"notifications/internal/repo/rom"
 	"notifications/internal/repo/user"
-	"notifications/pkg/lib/broker/nats"
+	"notifications/internal/service/quiethours"
+	usersrv "notifications/internal/service/user"
 	"notifications/pkg/lib/notifier/firebase"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/observer/sentry"
@@ -27,12 +29,14 @@ import (
 type external struct {
 	logger      logger.Logger
 	sentry      sentry.Sentry
-	nats        nats.Event
-	fcmSender   firebase.Sender
 	userRepo    user.Repo
 	pushRepo    push.Repo
-	romRepo     rom.Repo
+	transactor  tx.Transactor
+	quietHours  quiethours.Service
+	countries   country.Registry
 	idGenerator *snowflake.Node
+	*tracker
+	*devices
 }
 
 func (e *external) Clean() {}
@@ -58,23 +62,48 @@ func (e *external) Send(ctx context.Context, request *Request) (string, error) {
 		return "", err
 	}
 
+	var item = e.queue(ctx, request.DeliveryID, selectedUser.UserID, request.ExternalRequest.ID, request.ExternalRequest.APIClient)
+
+	if optedOut(ctx, e.userRepo, e.logger, selectedUser.UserID, request.ExternalRequest.PushType) {
+		e.complete(ctx, item, DeliverySuppressedOptedOut, "", nil)
+		e.logger.Warning("user opted out of the push category", zap.String("requestID", request.ExternalRequest.ID))
+		return _optedOutMessageID, nil
+	}
+
+	if !urgent(request, request.ExternalRequest.PushType) {
+		if until, ok := e.quietHours.Until(ctx, selectedUser, time.Now()); ok {
+			err = deferPush(ctx, e.quietHours, e.tracker, until, item, request)
+			if err != nil {
+				e.logger.Error("cannot defer push", zap.Error(err), zap.String("requestID", request.ExternalRequest.ID))
+				return "", err
+			}
+			return _quietHoursMessageID, nil
+		}
+	}
+
 	if request.ShowInFeed {
-		return e.sendStateful(ctx, selectedUser, request)
+		return e.sendStateful(ctx, selectedUser, request, item)
 	}
 
-	return e.sendStateless(ctx, selectedUser, request)
+	return e.sendStateless(ctx, selectedUser, request, item)
 }
 
-func (e *external) sendStateless(ctx context.Context, user *user.User, request *Request) (string, error) {
+func (e *external) sendStateless(ctx context.Context, user *user.User, request *Request, item *delivery.Delivery) (string, error) {
+	if len(user.ActiveDevices()) == 0 {
+		e.complete(ctx, item, DeliverySuppressedDisabled, "", nil)
+		e.logger.Warning("user has no active devices", zap.String("requestID", request.ExternalRequest.ID))
+		return "", resp.Wrap(resp.ErrBadRequest, "user has no active devices")
+	}
+
 	var (
 		title = request.ExternalRequest.Title.Get(user.Language)
 		body  = request.ExternalRequest.Body.Get(user.Language)
 	)
 	if strset.IsEmpty(title) {
-		title = request.ExternalRequest.Title.Get(language.RU)
+		title = request.ExternalRequest.Title.Get(e.fallbackLanguage(user))
 	}
 	if strset.IsEmpty(body) {
-		body = request.ExternalRequest.Body.Get(language.RU)
+		body = request.ExternalRequest.Body.Get(e.fallbackLanguage(user))
 	}
 
 	var (
@@ -88,23 +117,15 @@ func (e *external) sendStateless(ctx context.Context, user *user.User, request *
 	)
 
 	message.Data = data
-	message.Token = user.Token
 	firebase.AndroidMSG(message, data, firebase.AndroidHighestPriority)
 	firebase.IosMSG(message, data, firebase.ApnsHighestPriority)
 
-	messageID, err := e.fcmSender.SendPush(ctx, message)
-	if err != nil {
-		if !firebase.IsValidationErr(err) {
-			e.sentry.CaptureException(err)
-			e.logger.Error("error in fcm.SendPush", zap.Error(err), zap.String("requestID", request.ExternalRequest.ID))
-			return "", err
-		}
-		err = e.nats.Publish(stream.Notifications, subject.NotificationsFcmRegistrationTokenRemoved, user.UserID)
-		if err != nil {
-			e.sentry.CaptureException(err)
-			e.logger.Error("error on publish event", zap.Error(err), zap.String("requestID", request.ExternalRequest.ID))
-			return "", err
-		}
+	messageID, err := e.sendDevices(ctx, user.UserID, user.ActiveDevices(), message)
+	e.complete(ctx, item, DeliverySent, messageID, err)
+	if err != nil && !usersrv.IsInvalidTokenErr(err) {
+		e.sentry.CaptureException(err)
+		e.logger.Error("error in fcm.SendPush", zap.Error(err), zap.String("requestID", request.ExternalRequest.ID))
+		return "", err
 	}
 
 	messageID = messageID[strings.LastIndex(messageID, _slashDelim)+1:]
@@ -112,43 +133,33 @@ func (e *external) sendStateless(ctx context.Context, user *user.User, request *
 	return messageID, nil
 }
 
-func (e *external) sendStateful(ctx context.Context, user *user.User, request *Request) (messageID string, err error) {
-	savedPush, err := e.pushRepo.Insert(ctx, &push.Push{
-		ID:        int(e.idGenerator.Generate().Int64()),
+func (e *external) sendStateful(ctx context.Context, user *user.User, request *Request, item *delivery.Delivery) (messageID string, err error) {
+	item.PushID = int(e.idGenerator.Generate().Int64())
+
+	err = savePush(ctx, e.transactor, &push.Push{
+		ID:        item.PushID,
 		UserID:    user.UserID,
 		Status:    _approved,
 		Title:     request.ExternalRequest.Title,
 		Body:      request.ExternalRequest.Body,
 		Type:      request.ExternalRequest.PushType,
 		APIClient: request.ExternalRequest.APIClient,
-	})
-	if err != nil {
-		e.sentry.CaptureException(err)
-		e.logger.Error("error in push.Insert", zap.Error(err), zap.String("requestID", request.ExternalRequest.ID))
-		return "", err
-	}
-
-	err = e.romRepo.InsertInbox(ctx, &rom.Inbox{
-		ID:        savedPush.ID,
-		UserID:    savedPush.UserID,
-		CountryID: user.CountryID,
-		Type:      savedPush.Type,
-		Title:     savedPush.Title,
-		Body:      savedPush.Body,
-		ExtraData: map[string]string{},
-	})
+	}, user.CountryID)
 	if err != nil {
+		e.complete(ctx, item, DeliveryFailed, "", err)
 		e.sentry.CaptureException(err)
-		e.logger.Error("err in romRepo.InsertInbox", zap.Error(err), zap.String("requestID", request.ExternalRequest.ID))
+		e.logger.Error("error in savePush", zap.Error(err), zap.String("requestID", request.ExternalRequest.ID))
 		return "", err
 	}
 
 	if user.Status != _active {
+		e.complete(ctx, item, DeliverySuppressedInactive, "", nil)
 		e.logger.Warning("user status is not active", zap.String("requestID", request.ExternalRequest.ID))
 		return _inactiveUserMessageID, nil
 	}
-	if !user.PushEnabled {
-		e.logger.Warning("user push is disabled", zap.String("requestID", request.ExternalRequest.ID))
+	if !user.PushEnabled || len(user.ActiveDevices()) == 0 {
+		e.complete(ctx, item, DeliverySuppressedDisabled, "", nil)
+		e.logger.Warning("user push is disabled or user has no active devices", zap.String("requestID", request.ExternalRequest.ID))
 		return _disabledPushMessageID, nil
 	}
 
@@ -157,10 +168,10 @@ func (e *external) sendStateful(ctx context.Context, user *user.User, request *R
 		body  = request.ExternalRequest.Body.Get(user.Language)
 	)
 	if strset.IsEmpty(title) {
-		title = request.ExternalRequest.Title.Get(language.RU)
+		title = request.ExternalRequest.Title.Get(e.fallbackLanguage(user))
 	}
 	if strset.IsEmpty(body) {
-		body = request.ExternalRequest.Body.Get(language.RU)
+		body = request.ExternalRequest.Body.Get(e.fallbackLanguage(user))
 	}
 
 	data := make(map[string]string)
@@ -170,24 +181,20 @@ func (e *external) sendStateful(ctx context.Context, user *user.User, request *R
 	data[_badge] = _badge1
 	data[_category] = _defaultCategory
 	data[_sectionName] = _defaultSectionName
+	data[_pushID] = strset.IntToStr(item.PushID)
 
 	message := new(messaging.Message)
 	message.Data = data
-	message.Token = user.Token
 	firebase.AndroidMSG(message, data, firebase.AndroidHighestPriority)
 	firebase.IosMSG(message, data, firebase.ApnsHighestPriority)
 
-	msgID, err := e.fcmSender.SendPush(ctx, message)
+	msgID, err := e.sendDevices(ctx, user.UserID, user.ActiveDevices(), message)
+	e.complete(ctx, item, DeliverySent, msgID, err)
 	if err != nil {
-		if !firebase.IsValidationErr(err) {
+		if !usersrv.IsInvalidTokenErr(err) {
 			e.sentry.CaptureException(err)
 			e.logger.Error("error in fcm.SendPush", zap.Error(err), zap.String("requestID", request.ExternalRequest.ID))
-			return _fcmPushMessageID, nil
-		}
-		err = e.nats.Publish(stream.Notifications, subject.NotificationsFcmRegistrationTokenRemoved, user.UserID)
-		if err != nil {
-			e.sentry.CaptureException(err)
-			e.logger.Error("error on publish event", zap.Error(err), zap.String("requestID", request.ExternalRequest.ID))
+			return "", err
 		}
 		return _fcmPushMessageID, nil
 	}
@@ -196,3 +203,13 @@ func (e *external) sendStateful(ctx context.Context, user *user.User, request *R
 
 	return messageID, nil
 }
+
+// fallbackLanguage returns the default language of the user's country, russian if the country is unknown
+func (e *external) fallbackLanguage(user *user.User) string {
+	userCountry, err := e.countries.ByID(user.CountryID)
+	if err != nil {
+		e.logger.Warning("failed to get user country", zap.Error(err), zap.Int("userID", user.UserID))
+		return language.RU
+	}
+	return userCountry.Language
+}
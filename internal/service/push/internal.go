@@ -3,6 +3,7 @@ package push
 import (
 	"context"
 	"errors"
+	"maps"
 	"time"
 
 	"firebase.google.com/go/v4/messaging"
@@ -10,14 +11,14 @@ import (
 	"go.uber.org/zap"
 
 	"notifications/internal/api/resp"
-	"notifications/internal/api/transport/broker/stream"
-	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/db/tx"
 	"notifications/internal/lib/language"
 	"notifications/internal/repo/push"
 	"notifications/internal/repo/repomodel"
-	"notifications/internal/repo/rom"
 	"notifications/internal/repo/user"
-	"notifications/pkg/lib/broker/nats"
+	"notifications/internal/service/quiethours"
+	"notifications/internal/service/template"
+	usersrv "notifications/internal/service/user"
 	"notifications/pkg/lib/cache"
 	"notifications/pkg/lib/notifier/firebase"
 	"notifications/pkg/lib/observer/logger"
@@ -28,13 +29,16 @@ import (
 type internal struct {
 	logger      logger.Logger
 	sentry      sentry.Sentry
-	nats        nats.Event
 	cache       cache.Cache
-	fcmSender   firebase.Sender
 	userRepo    user.Repo
 	pushRepo    push.Repo
-	romRepo     rom.Repo
+	transactor  tx.Transactor
+	templates   template.Service
+	quietHours  quiethours.Service
+	users       usersrv.Service
 	idGenerator *snowflake.Node
+	*tracker
+	*devices
 }
 
 func (i *internal) Send(ctx context.Context, request *Request) (string, error) {
@@ -49,15 +53,38 @@ func (i *internal) Send(ctx context.Context, request *Request) (string, error) {
 		return "", err
 	}
 
-	if !strset.IsEmpty(selectedUser.Token) && !strset.IsEmpty(request.InternalRequest.Token) && selectedUser.Token != request.InternalRequest.Token {
-		err = i.userRepo.UpdateToken(ctx, request.InternalRequest.UserID, request.InternalRequest.Token)
+	if !strset.IsEmpty(selectedUser.Token) && !strset.IsEmpty(request.InternalRequest.Token) && !hasDevice(selectedUser.ActiveDevices(), request.InternalRequest.Token) {
+		err = i.users.RegisterDevice(ctx, usersrv.Device{UserID: selectedUser.UserID, Token: request.InternalRequest.Token})
 		if err != nil {
 			i.sentry.CaptureException(err)
-			i.logger.Error("err occurred during updating token", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
+			i.logger.Error("err occurred during registering device", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
 		}
+		selectedUser.Devices = append([]user.DeviceToken{{Token: request.InternalRequest.Token, Provider: user.ProviderFcm}}, selectedUser.ActiveDevices()...)
 		selectedUser.Token = request.InternalRequest.Token
 	}
 
+	if optedOut(ctx, i.userRepo, i.logger, selectedUser.UserID, request.InternalRequest.Data[_pushType]) {
+		i.complete(ctx, i.queue(ctx, request.DeliveryID, selectedUser.UserID, request.InternalRequest.Data[_trID], _defaultAPIClient), DeliverySuppressedOptedOut, "", nil)
+		i.logger.Warning("user opted out of the push category", zap.Int("userID", selectedUser.UserID))
+		return "", nil
+	}
+
+	if !urgent(request, request.InternalRequest.Data[_pushType]) {
+		if until, ok := i.quietHours.Until(ctx, selectedUser, time.Now()); ok {
+			// the device is already registered, the deferred push must not register the token again
+			request.InternalRequest.Token = ""
+			item := i.queue(ctx, request.DeliveryID, selectedUser.UserID, request.InternalRequest.Data[_trID], _defaultAPIClient)
+			return "", deferPush(ctx, i.quietHours, i.tracker, until, item, request)
+		}
+	}
+
+	if !strset.IsEmpty(request.InternalRequest.TemplateKey) {
+		err = i.render(ctx, selectedUser, request)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	switch {
 	case request.ShowInFeed:
 		return i.sendStateful(ctx, selectedUser, request)
@@ -73,80 +100,74 @@ func (i *internal) sendStateful(ctx context.Context, user *user.User, request *R
 	title.SetAll(request.InternalRequest.Data[_title])
 	body.SetAll(request.InternalRequest.Data[_message])
 
-	savedPush, err := i.pushRepo.Insert(ctx, &push.Push{
-		ID:        int(i.idGenerator.Generate().Int64()),
-		UserID:    user.UserID,
-		Status:    _approved,
-		Title:     title,
-		Body:      body,
-		Type:      _push,
-		APIClient: _defaultAPIClient,
-	})
-	if err != nil {
-		i.sentry.CaptureException(err)
-		i.logger.Error("error in push.Insert", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
-		return "", err
-	}
+	var (
+		item     = i.queue(ctx, request.DeliveryID, user.UserID, request.InternalRequest.Data[_trID], _defaultAPIClient)
+		savedKey = ":stateful:" + request.InternalRequest.MsgID
+	)
 
-	err = i.romRepo.InsertInbox(ctx, &rom.Inbox{
-		ID:        savedPush.ID,
-		UserID:    savedPush.UserID,
-		CountryID: user.CountryID,
-		Type:      savedPush.Type,
-		Title:     savedPush.Title,
-		Body:      savedPush.Body,
-		ExtraData: map[string]string{},
-	})
-	if err != nil {
-		// delete saved push if error occurred while inserting in rom in order to avoid inconsistency
-		// because notification db and rom db are on different servers
-		errP := i.pushRepo.DeleteByIDs(ctx, []int{savedPush.ID})
-		if errP != nil {
-			i.sentry.CaptureException(errP)
-			i.logger.Error("error in push.DeleteByIDs", zap.Error(errP), zap.Int("userID", request.InternalRequest.UserID))
+	// the push could be saved on the previous delivery of the same message
+	if strset.IsEmpty(request.InternalRequest.MsgID) || i.cache.Get(ctx, savedKey, &item.PushID) != nil {
+		item.PushID = int(i.idGenerator.Generate().Int64())
+
+		err := savePush(ctx, i.transactor, &push.Push{
+			ID:        item.PushID,
+			UserID:    user.UserID,
+			Status:    _approved,
+			Title:     title,
+			Body:      body,
+			Type:      _push,
+			APIClient: _defaultAPIClient,
+		}, user.CountryID)
+		if err != nil {
+			i.complete(ctx, item, DeliveryFailed, "", err)
+			i.sentry.CaptureException(err)
+			i.logger.Error("error in savePush", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
+			return "", err
+		}
+
+		if !strset.IsEmpty(request.InternalRequest.MsgID) {
+			if err = i.cache.Set(ctx, savedKey, item.PushID, time.Hour); err != nil {
+				i.sentry.CaptureException(err)
+				i.logger.Error("error in cache.Set", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
+			}
 		}
-		i.sentry.CaptureException(err)
-		i.logger.Error("err in romRepo.InsertInbox", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
-		return "", err
 	}
 
 	// if user status is not active or push is disabled, save the state but do not send push
 	if user.Status != _active {
+		i.complete(ctx, item, DeliverySuppressedInactive, "", nil)
 		i.logger.Warning("user status is not active", zap.Int("userID", request.InternalRequest.UserID))
 		return "", nil
 	}
-	if !user.PushEnabled || strset.IsEmpty(user.Token) {
+	if !user.PushEnabled || len(user.ActiveDevices()) == 0 {
+		i.complete(ctx, item, DeliverySuppressedDisabled, "", nil)
 		i.logger.Warning("user push is disabled or token is empty", zap.Int("userID", user.UserID))
 		return "", nil
 	}
 
-	message := new(messaging.Message)
-	message.Data = request.InternalRequest.Data
-	message.Token = user.Token
-	firebase.AndroidMSG(message, request.InternalRequest.Data, firebase.AndroidHighestPriority)
-	firebase.IosMSG(message, request.InternalRequest.Data, firebase.ApnsHighestPriority)
+	var data = make(map[string]string, len(request.InternalRequest.Data)+1)
+	maps.Copy(data, request.InternalRequest.Data)
+	data[_pushID] = strset.IntToStr(item.PushID)
 
-	_, err = i.fcmSender.SendPush(ctx, message)
-	if err != nil {
-		if !firebase.IsValidationErr(err) {
-			i.sentry.CaptureException(err)
-			i.logger.Error("error in fcm.SendPush", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
-			return "", err
-		}
+	message := new(messaging.Message)
+	message.Data = data
+	firebase.AndroidMSG(message, data, firebase.AndroidHighestPriority)
+	firebase.IosMSG(message, data, firebase.ApnsHighestPriority)
 
-		err = i.nats.Publish(stream.Notifications, subject.NotificationsFcmRegistrationTokenRemoved, user.UserID)
-		if err != nil {
-			i.sentry.CaptureException(err)
-			i.logger.Error("error on publish event", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
-			return "", err
-		}
+	messageID, err := i.sendDevices(ctx, user.UserID, user.ActiveDevices(), message)
+	i.complete(ctx, item, DeliverySent, messageID, err)
+	if err != nil && !usersrv.IsInvalidTokenErr(err) {
+		i.sentry.CaptureException(err)
+		i.logger.Error("error in fcm.SendPush", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
+		return "", err
 	}
 
 	return "", nil
 }
 
 func (i *internal) sendStatelessAsync(ctx context.Context, user *user.User, request *Request) (string, error) {
-	if !user.PushEnabled || strset.IsEmpty(user.Token) {
+	if !user.PushEnabled || len(user.ActiveDevices()) == 0 {
+		i.complete(ctx, i.queue(ctx, request.DeliveryID, user.UserID, request.InternalRequest.Data[_trID], _defaultAPIClient), DeliverySuppressedDisabled, "", nil)
 		return "", nil
 	}
 
@@ -168,10 +189,7 @@ func (i *internal) sendStatelessAsync(ctx context.Context, user *user.User, requ
 
 	var (
 		pushType = request.InternalRequest.Data[_pushType]
-		message  = &messaging.Message{
-			Data:  request.InternalRequest.Data,
-			Token: user.Token,
-		}
+		message  = &messaging.Message{Data: request.InternalRequest.Data}
 	)
 
 	if pushType != _silent {
@@ -179,20 +197,23 @@ func (i *internal) sendStatelessAsync(ctx context.Context, user *user.User, requ
 		firebase.IosMSG(message, request.InternalRequest.Data, firebase.ApnsHighestPriority)
 	}
 
-	_, err := i.fcmSender.SendPush(ctx, message)
+	var item = i.queue(ctx, request.DeliveryID, user.UserID, trID, _defaultAPIClient)
+
+	messageID, err := i.sendDevices(ctx, user.UserID, user.ActiveDevices(), message)
+	i.complete(ctx, item, DeliverySent, messageID, err)
 	if err != nil {
 		i.logger.Warning("error in fcm.SendPush", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
 
-		if !firebase.IsValidationErr(err) {
+		if !usersrv.IsInvalidTokenErr(err) {
 			i.sentry.CaptureException(err)
 			i.logger.Error("error in fcm.SendPush", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
-			return "", err
-		}
 
-		err = i.nats.Publish(stream.Notifications, subject.NotificationsFcmRegistrationTokenRemoved, request.InternalRequest.UserID)
-		if err != nil {
-			i.sentry.CaptureException(err)
-			i.logger.Error("error on publish event", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
+			// release the deduplication key, so the push can be retried
+			if !strset.IsEmpty(trID) {
+				if cErr := i.cache.Delete(ctx, ":stateless:"+trID); cErr != nil {
+					i.logger.Error("error in cache.Delete", zap.Error(cErr), zap.Int("userID", request.InternalRequest.UserID))
+				}
+			}
 			return "", err
 		}
 	}
@@ -201,7 +222,8 @@ func (i *internal) sendStatelessAsync(ctx context.Context, user *user.User, requ
 }
 
 func (i *internal) sendStatelessSync(ctx context.Context, user *user.User, request *Request) (string, error) {
-	if !user.PushEnabled || strset.IsEmpty(user.Token) {
+	if !user.PushEnabled || len(user.ActiveDevices()) == 0 {
+		i.complete(ctx, i.queue(ctx, request.DeliveryID, user.UserID, request.InternalRequest.Data[_trID], _defaultAPIClient), DeliverySuppressedDisabled, "", nil)
 		i.logger.Error("user push is disabled or token is empty", zap.Int("userID", request.InternalRequest.UserID))
 		return "", resp.ErrBadRequest
 	}
@@ -222,35 +244,48 @@ func (i *internal) sendStatelessSync(ctx context.Context, user *user.User, reque
 		}
 	}
 
-	var message = &messaging.Message{
-		Data:  request.InternalRequest.Data,
-		Token: user.Token,
-	}
+	var message = &messaging.Message{Data: request.InternalRequest.Data}
 
 	firebase.AndroidMSG(message, request.InternalRequest.Data, firebase.AndroidHighestPriority)
 	firebase.IosMSG(message, request.InternalRequest.Data, firebase.ApnsHighestPriority)
 
-	messageID, err := i.fcmSender.SendPush(ctx, message)
+	var item = i.queue(ctx, request.DeliveryID, user.UserID, trID, _defaultAPIClient)
+
+	messageID, err := i.sendDevices(ctx, user.UserID, user.ActiveDevices(), message)
+	i.complete(ctx, item, DeliverySent, messageID, err)
 	if err != nil {
-		if !firebase.IsValidationErr(err) {
+		if !usersrv.IsInvalidTokenErr(err) {
 			i.sentry.CaptureException(err)
 			i.logger.Error("error in fcm.SendPush", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
 		} else {
 			i.logger.Warning("error in fcm.SendPush", zap.Error(err), zap.Int("userID", request.InternalRequest.UserID))
 		}
-
-		nErr := i.nats.Publish(stream.Notifications, subject.NotificationsFcmRegistrationTokenRemoved, request.InternalRequest.UserID)
-		if nErr != nil {
-			i.sentry.CaptureException(nErr)
-			i.logger.Error("error on publish event", zap.Error(nErr), zap.Int("userID", request.InternalRequest.UserID))
-			return "", nErr
-		}
 		return "", err
 	}
 
 	return messageID, nil
 }
 
+// render sets the title and message of the push from the template in the language of the user
+func (i *internal) render(ctx context.Context, user *user.User, request *Request) error {
+	content, err := i.templates.Render(ctx, request.InternalRequest.TemplateKey, template.Recipient{
+		UserID:   user.UserID,
+		Language: user.Language,
+	}, request.InternalRequest.Variables)
+	if err != nil {
+		i.logger.Error("error in templates.Render", zap.Error(err), zap.String("templateKey", request.InternalRequest.TemplateKey))
+		return err
+	}
+
+	var data = make(map[string]string, len(request.InternalRequest.Data)+2)
+	maps.Copy(data, request.InternalRequest.Data)
+	data[_title] = content.Title
+	data[_message] = content.Body
+	request.InternalRequest.Data = data
+
+	return nil
+}
+
 func (i *internal) Clean() {
 	err := i.pushRepo.Clean(context.Background())
 	if err != nil {
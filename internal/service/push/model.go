@@ -3,6 +3,7 @@ package push
 import (
 	"errors"
 	"slices"
+	"time"
 
 	"notifications/internal/lib/language"
 	"notifications/pkg/util/strset"
@@ -15,9 +16,14 @@ const (
 	_inactiveUserMessageID = "inactive_user#fake_message_id"
 	_disabledPushMessageID = "disabled_push#fake_message_id"
 	_fcmPushMessageID      = "firebase_error#fake_message_id"
+	_optedOutMessageID     = "opted_out#fake_message_id"
+	_quietHoursMessageID   = "quiet_hours#fake_message_id"
 	_defaultAPIClient      = "my.app"
 )
 
+// errNoDevices is returned for the user without the active devices, there is no provider to send the push to
+var errNoDevices = errors.New("user has no active devices")
+
 // Push types
 const (
 	_pushType = "pushType"
@@ -34,6 +40,8 @@ const (
 	_sectionName = "sectionName"
 	_category    = "category"
 	_trID        = "transactionID"
+	// _pushID is reported back by the app to track the push
+	_pushID = "pushID"
 )
 
 const (
@@ -50,6 +58,31 @@ const (
 
 const _active = "active"
 
+// _apnsPriority is the header of the APNs config of FCM the priority of the push to APNs is taken from
+const _apnsPriority = "apns-priority"
+
+// Delivery statuses
+const (
+	DeliveryQueued             = "queued"
+	DeliverySent               = "sent"
+	DeliveryFailed             = "failed"
+	DeliveryTokenInvalid       = "token_invalid"
+	DeliverySuppressedInactive = "suppressed_inactive"
+	DeliverySuppressedDisabled = "suppressed_disabled"
+	DeliverySuppressedOptedOut = "suppressed_opted_out"
+	DeliveryDeferred           = "deferred_quiet_hours"
+)
+
+type Delivery struct {
+	RequestID    string    `json:"requestID"`
+	Status       string    `json:"status"`
+	MessageID    string    `json:"messageID"`
+	ErrorCode    string    `json:"errorCode"`
+	ErrorMessage string    `json:"errorMessage"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
 type Message struct {
 	UserID     int
 	Token      string
@@ -63,12 +96,19 @@ type Request struct {
 	ShowInFeed      bool
 	IsInternal      bool
 	Sync            bool
+	// DeliveryID is set when the push is deferred by quiet hours, so the same delivery is completed when it's sent
+	DeliveryID int
 }
 
 type InternalRequest struct {
 	UserID int
 	Token  string
 	Data   map[string]string
+	// MsgID identifies the broker message across redeliveries, so a retried stateful push isn't saved twice
+	MsgID string
+	// TemplateKey and Variables are rendered to the title and message of Data in the language of the user
+	TemplateKey string
+	Variables   map[string]any
 }
 
 type ExternalRequest struct {
@@ -9,13 +9,20 @@ import (
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"notifications/internal/db/tx"
+	"notifications/internal/lib/country"
+	"notifications/internal/repo/delivery"
 	"notifications/internal/repo/push"
-	"notifications/internal/repo/rom"
 	"notifications/internal/repo/user"
-	"notifications/pkg/lib/broker/nats"
+	"notifications/internal/service/quiethours"
+	"notifications/internal/service/template"
+	usersrv "notifications/internal/service/user"
 	"notifications/pkg/lib/cache"
 	"notifications/pkg/lib/config"
+	"notifications/pkg/lib/notifier/apns"
 	"notifications/pkg/lib/notifier/firebase"
+	"notifications/pkg/lib/notifier/hms"
+	"notifications/pkg/lib/notifier/webpush"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/observer/sentry"
 )
@@ -25,6 +32,7 @@ var Module = fx.Provide(New)
 type Service interface {
 	sender
 	cleaner
+	deliveryReader
 }
 
 type sender interface {
@@ -35,18 +43,30 @@ type cleaner interface {
 	Clean()
 }
 
+type deliveryReader interface {
+	// GetDelivery returns the delivery status of the external push by X-RequestId of the api client
+	GetDelivery(ctx context.Context, apiClient, requestID string) (*Delivery, error)
+}
+
 type Params struct {
 	fx.In
 
-	Config    config.Config
-	Logger    logger.Logger
-	Sentry    sentry.Sentry
-	Nats      nats.Event
-	Cache     cache.Cache
-	FcmSender firebase.Sender
-	UserRepo  user.Repo
-	PushRepo  push.Repo
-	RomRepo   rom.Repo
+	Config       config.Config
+	Logger       logger.Logger
+	Sentry       sentry.Sentry
+	Cache        cache.Cache
+	FcmSender    firebase.Sender
+	HmsSender    hms.Sender
+	ApnsSender   apns.Sender
+	WebSender    webpush.Sender
+	UserRepo     user.Repo
+	PushRepo     push.Repo
+	DeliveryRepo delivery.Repo
+	Transactor   tx.Transactor
+	Templates    template.Service
+	QuietHours   quiethours.Service
+	Countries    country.Registry
+	Users        usersrv.Service
 }
 
 type service struct {
@@ -66,27 +86,49 @@ func New(p Params) Service {
 		return nil
 	}
 
+	var deliveryTracker = &tracker{
+		logger:       p.Logger,
+		sentry:       p.Sentry,
+		deliveryRepo: p.DeliveryRepo,
+		idGenerator:  idGenerator,
+	}
+
+	var deviceSender = &devices{
+		logger:     p.Logger,
+		sentry:     p.Sentry,
+		users:      p.Users,
+		fcmSender:  p.FcmSender,
+		hmsSender:  p.HmsSender,
+		apnsSender: p.ApnsSender,
+		webSender:  p.WebSender,
+	}
+
 	return &service{
 		channel: map[bool]Service{
 			true: &internal{
 				logger:      p.Logger,
 				sentry:      p.Sentry,
-				nats:        p.Nats,
 				cache:       p.Cache,
-				fcmSender:   p.FcmSender,
 				userRepo:    p.UserRepo,
 				pushRepo:    p.PushRepo,
-				romRepo:     p.RomRepo,
+				transactor:  p.Transactor,
+				templates:   p.Templates,
+				quietHours:  p.QuietHours,
+				users:       p.Users,
+				tracker:     deliveryTracker,
+				devices:     deviceSender,
 				idGenerator: idGenerator,
 			},
 			false: &external{
 				logger:      p.Logger,
 				sentry:      p.Sentry,
-				nats:        p.Nats,
-				fcmSender:   p.FcmSender,
 				userRepo:    p.UserRepo,
 				pushRepo:    p.PushRepo,
-				romRepo:     p.RomRepo,
+				transactor:  p.Transactor,
+				quietHours:  p.QuietHours,
+				countries:   p.Countries,
+				tracker:     deliveryTracker,
+				devices:     deviceSender,
 				idGenerator: idGenerator,
 			},
 		},
@@ -100,3 +142,7 @@ func (s *service) Send(ctx context.Context, request *Request) (string, error) {
 func (s *service) Clean() {
 	s.channel[true].Clean()
 }
+
+func (s *service) GetDelivery(ctx context.Context, apiClient, requestID string) (*Delivery, error) {
+	return s.channel[false].GetDelivery(ctx, apiClient, requestID)
+}
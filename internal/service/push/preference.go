@@ -0,0 +1,35 @@
+package push
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/repo/user"
+	"notifications/pkg/lib/observer/logger"
+)
+
+// pushCategory maps the push type to the preference category of the user
+func pushCategory(pushType string) string {
+	if pushType == _otp {
+		return user.CategoryOTP
+	}
+	return user.CategoryTransactional
+}
+
+// optedOut reports if the user disabled pushes of the category, otp can't be suppressed
+// The push is delivered if the preference can't be read
+func optedOut(ctx context.Context, userRepo user.Repo, logger logger.Logger, userID int, pushType string) bool {
+	var category = pushCategory(pushType)
+	if !user.Suppressible(category) {
+		return false
+	}
+
+	optedOut, err := userRepo.IsOptedOut(ctx, userID, category, user.ChannelPush)
+	if err != nil {
+		logger.Error("err occurred during getting preference", zap.Error(err), zap.Int("userID", userID), zap.String("category", category))
+		return false
+	}
+
+	return optedOut
+}
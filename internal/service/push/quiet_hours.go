@@ -0,0 +1,35 @@
+package push
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/transport/broker/stream"
+	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/repo/delivery"
+	"notifications/internal/service/quiethours"
+)
+
+// urgent reports if the push is sent regardless of the quiet hours: otp and sync pushes are awaited by the user
+// and silent pushes aren't shown
+func urgent(request *Request, pushType string) bool {
+	return request.Sync || pushType == _otp || pushType == _silent
+}
+
+// deferPush publishes the push to be sent again at the end of the quiet hours, the delivery stays deferred meanwhile
+func deferPush(ctx context.Context, quietHours quiethours.Service, t *tracker, until time.Time, item *delivery.Delivery, request *Request) error {
+	request.DeliveryID = item.ID
+
+	err := quietHours.Defer(ctx, until, stream.Notifications, subject.NotificationsPushDeferred, request)
+	if err != nil {
+		t.complete(ctx, item, DeliveryFailed, "", err)
+		return err
+	}
+
+	t.complete(ctx, item, DeliveryDeferred, "", nil)
+	t.logger.Info("push is deferred by quiet hours", zap.Int("userID", item.UserID), zap.Time("until", until))
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"notifications/internal/db/tx"
+	"notifications/internal/repo/outbox"
+	"notifications/internal/repo/push"
+	"notifications/internal/repo/rom"
+)
+
+// savePush inserts the push and the outbox message for the rom inbox in one transaction,
+// the inbox is written to the rom db by the outbox relay because notification db and rom db are on different servers
+func savePush(ctx context.Context, transactor tx.Transactor, item *push.Push, countryID int8) (err error) {
+	tx := transactor.New()
+	err = tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			if errX := tx.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	_, err = tx.PushRepo().Insert(ctx, item)
+	if err != nil {
+		return err
+	}
+
+	message, err := outbox.NewRomInbox(&rom.Inbox{
+		ID:        item.ID,
+		UserID:    item.UserID,
+		CountryID: countryID,
+		Type:      item.Type,
+		Title:     item.Title,
+		Body:      item.Body,
+		ExtraData: map[string]string{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.OutboxRepo().Insert(ctx, message)
+}
@@ -0,0 +1,55 @@
+package quiethours
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/lib/country"
+	"notifications/internal/repo/outbox"
+	"notifications/internal/repo/user"
+	"notifications/pkg/lib/observer/logger"
+	"notifications/pkg/lib/observer/sentry"
+)
+
+var Module = fx.Provide(New)
+
+type Service interface {
+	// Until returns the end of the quiet hours of the user in UTC if the time is inside them,
+	// the user's override is applied over the quiet hours of the country
+	Until(ctx context.Context, user *user.User, t time.Time) (time.Time, bool)
+	// UntilForCountries returns the end of the quiet hours in UTC if the time is inside the ones of any of the countries,
+	// all the countries if none is given. Topic messages reach all the subscribers at once, so the overrides of the users are not applied
+	UntilForCountries(t time.Time, countryIDs ...int8) (time.Time, bool)
+	// Defer publishes the message to the broker through the outbox not before the time
+	Defer(ctx context.Context, until time.Time, stream, subj string, msg any) error
+}
+
+type Params struct {
+	fx.In
+
+	Logger     logger.Logger
+	Sentry     sentry.Sentry
+	Countries  country.Registry
+	UserRepo   user.Repo
+	OutboxRepo outbox.Repo
+}
+
+type service struct {
+	logger     logger.Logger
+	sentry     sentry.Sentry
+	countries  country.Registry
+	userRepo   user.Repo
+	outboxRepo outbox.Repo
+}
+
+func New(p Params) Service {
+	return &service{
+		logger:     p.Logger,
+		sentry:     p.Sentry,
+		countries:  p.Countries,
+		userRepo:   p.UserRepo,
+		outboxRepo: p.OutboxRepo,
+	}
+}
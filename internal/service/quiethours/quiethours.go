@@ -0,0 +1,105 @@
+package quiethours
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/lib/country"
+	"notifications/internal/lib/quiethours"
+	"notifications/internal/repo/outbox"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/user"
+)
+
+func (s *service) Until(ctx context.Context, u *user.User, t time.Time) (time.Time, bool) {
+	userCountry, err := s.countries.ByID(u.CountryID)
+	if err != nil {
+		// the local time of the user is unknown
+		s.logger.Warning("quiet hours are not applied", zap.Error(err), zap.Int("userID", u.UserID))
+		return time.Time{}, false
+	}
+
+	var window = userCountry.QuietHours
+
+	override, err := s.userRepo.GetQuietHours(ctx, u.UserID)
+	switch {
+	case err == nil && !override.Enabled:
+		return time.Time{}, false
+	case err == nil:
+		window, err = quiethours.Parse(override.Start, override.End)
+		if err != nil {
+			s.logger.Warning("quiet hours of the user are not valid", zap.Error(err), zap.Int("userID", u.UserID))
+			window = userCountry.QuietHours
+		}
+	case !errors.Is(err, repomodel.ErrNotFound):
+		// the quiet hours of the country are applied if the override can't be read
+		s.logger.Error("err occurred during getting quiet hours", zap.Error(err), zap.Int("userID", u.UserID))
+	}
+
+	return until(window, t, userCountry)
+}
+
+func (s *service) UntilForCountries(t time.Time, countryIDs ...int8) (time.Time, bool) {
+	var countries = s.countries.GetAll()
+	if len(countryIDs) != 0 {
+		countries = make([]country.Country, 0, len(countryIDs))
+		for _, id := range countryIDs {
+			c, err := s.countries.ByID(id)
+			if err != nil {
+				s.logger.Warning("quiet hours are not applied", zap.Error(err))
+				continue
+			}
+			countries = append(countries, c)
+		}
+	}
+
+	var (
+		latest   time.Time
+		deferred bool
+	)
+
+	// the end of a window can fall inside the window of another country, so the windows are chained
+	for range len(countries) {
+		var moved bool
+		for _, c := range countries {
+			if end, ok := until(c.QuietHours, t, c); ok {
+				t, latest, moved, deferred = end, end, true, true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	return latest, deferred
+}
+
+func (s *service) Defer(ctx context.Context, until time.Time, stream, subj string, msg any) error {
+	message, err := outbox.NewBroker(stream, subj, msg)
+	if err != nil {
+		return err
+	}
+
+	message.NextAttemptAt = until
+
+	err = s.outboxRepo.Insert(ctx, message)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during saving deferred message to outbox", zap.Error(err), zap.String("subject", subj))
+		return err
+	}
+
+	return nil
+}
+
+// until returns the end of the window in UTC if the time is inside it in the timezone of the country
+func until(window quiethours.Window, t time.Time, c country.Country) (time.Time, bool) {
+	var local = c.Local(t)
+	if !window.Contains(local) {
+		return time.Time{}, false
+	}
+	return window.Until(local).UTC(), true
+}
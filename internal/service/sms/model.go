@@ -1,10 +1,18 @@
 package sms
 
 const (
-	_phone             = "phone"
-	_text              = "text"
 	_defaultSender     = "my.app"
 	_defaultPriority   = 2
 	_defaultExpiration = 480 // seconds
 	_defaultType       = 2
 )
+
+type Message struct {
+	Phone string
+	Text  string
+	// TemplateKey is rendered to the Text with the Variables, the language is taken from the user by UserID or Phone if it's not set
+	TemplateKey string
+	Language    string
+	UserID      int
+	Variables   map[string]any
+}
@@ -5,6 +5,7 @@ import (
 
 	"go.uber.org/fx"
 
+	"notifications/internal/service/template"
 	"notifications/pkg/lib/notifier/sms"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/observer/sentry"
@@ -13,27 +14,30 @@ import (
 var Module = fx.Provide(New)
 
 type Service interface {
-	Send(context.Context, map[string]string) error
+	Send(context.Context, Message) error
 }
 
 type Params struct {
 	fx.In
 
-	Logger logger.Logger
-	Sentry sentry.Sentry
-	Sms    sms.SMS
+	Logger    logger.Logger
+	Sentry    sentry.Sentry
+	Sms       sms.SMS
+	Templates template.Service
 }
 
 type service struct {
-	logger logger.Logger
-	sentry sentry.Sentry
-	sms    sms.SMS
+	logger    logger.Logger
+	sentry    sentry.Sentry
+	sms       sms.SMS
+	templates template.Service
 }
 
 func New(p Params) Service {
 	return &service{
-		logger: p.Logger,
-		sentry: p.Sentry,
-		sms:    p.Sms,
+		logger:    p.Logger,
+		sentry:    p.Sentry,
+		sms:       p.Sms,
+		templates: p.Templates,
 	}
 }
@@ -6,13 +6,28 @@ import (
 
 	"go.uber.org/zap"
 
+	"notifications/internal/service/template"
 	"notifications/pkg/lib/notifier/sms"
+	"notifications/pkg/util/strset"
 )
 
-func (s *service) Send(ctx context.Context, message map[string]string) error {
+func (s *service) Send(ctx context.Context, message Message) error {
+	if !strset.IsEmpty(message.TemplateKey) {
+		content, err := s.templates.Render(ctx, message.TemplateKey, template.Recipient{
+			UserID:   message.UserID,
+			Phone:    message.Phone,
+			Language: message.Language,
+		}, message.Variables)
+		if err != nil {
+			s.logger.Error("err occurred during rendering template", zap.Error(err), zap.String("templateKey", message.TemplateKey))
+			return err
+		}
+		message.Text = content.Body
+	}
+
 	err := s.sms.Send(ctx, sms.Request{
-		Phone:         message[_phone],
-		Text:          message[_text],
+		Phone:         message.Phone,
+		Text:          message.Text,
 		SenderAddress: _defaultSender,
 		Priority:      _defaultPriority,
 		ExpiresIn:     _defaultExpiration,
@@ -0,0 +1,249 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/api/transport/broker/stream"
+	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/repo/outbox"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/template"
+	"notifications/internal/service/admin"
+)
+
+func (s *service) GetTemplates(ctx context.Context, limit, offset uint) ([]*Template, error) {
+	if limit == 0 {
+		limit = _defaultLimit
+	}
+
+	list, err := s.templateRepo.GetList(ctx, limit, offset)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("failed to get templates", zap.Error(err))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "templates not found")
+	}
+
+	var templates = make([]*Template, 0, len(list))
+	for _, item := range list {
+		var t = new(Template)
+		t.toService(item)
+		templates = append(templates, t)
+	}
+
+	return templates, nil
+}
+
+func (s *service) GetTemplate(ctx context.Context, key string) (*Template, error) {
+	item, err := s.templateRepo.GetByKey(ctx, key)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("failed to get template", zap.Error(err), zap.String("key", key))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "template not found")
+	}
+
+	var t = new(Template)
+	t.toService(item)
+
+	return t, nil
+}
+
+func (s *service) Create(ctx context.Context, a admin.Admin, request *Request) (_ *Template, err error) {
+	err = validate(request)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := s.transactor.New()
+	err = tx.Begin(ctx)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err on tx.Begin", zap.Error(err))
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			if errX := tx.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+		}
+	}()
+
+	created, err := tx.TemplateRepo().Create(ctx, toRepo(request))
+	if err != nil {
+		if errors.Is(err, repomodel.ErrUniqueViolation) {
+			return nil, resp.Wrap(resp.ErrDuplicateItem, "template with the key already exists")
+		}
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to create template", zap.Error(err), zap.String("key", request.Key))
+		return nil, err
+	}
+
+	err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
+		AdminId:   a.ID,
+		IpAddress: a.IP,
+		EventName: admin.CreateTemplateEvent,
+		OldData:   template.Template{},
+		NewData:   *created,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+		return nil, err
+	}
+
+	var t = new(Template)
+	t.toService(created)
+
+	return t, nil
+}
+
+func (s *service) Update(ctx context.Context, a admin.Admin, request *Request) (_ *Template, err error) {
+	err = validate(request)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := s.templateRepo.GetByKey(ctx, request.Key)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("failed to get template", zap.Error(err), zap.String("key", request.Key))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrNotFound, "template not found")
+	}
+
+	tx := s.transactor.New()
+	err = tx.Begin(ctx)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err on tx.Begin", zap.Error(err))
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			if errX := tx.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+			return
+		}
+		s.invalidate(ctx, request.Key)
+	}()
+
+	updated, err := tx.TemplateRepo().Update(ctx, toRepo(request))
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to update template", zap.Error(err), zap.String("key", request.Key))
+		return nil, err
+	}
+
+	err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
+		AdminId:   a.ID,
+		IpAddress: a.IP,
+		EventName: admin.UpdateTemplateEvent,
+		OldData:   *selected,
+		NewData:   *updated,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+		return nil, err
+	}
+
+	var t = new(Template)
+	t.toService(updated)
+
+	return t, nil
+}
+
+func (s *service) Delete(ctx context.Context, a admin.Admin, key string) (err error) {
+	selected, err := s.templateRepo.GetByKey(ctx, key)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("failed to get template", zap.Error(err), zap.String("key", key))
+			return err
+		}
+		return resp.Wrap(resp.ErrNotFound, "template not found")
+	}
+
+	tx := s.transactor.New()
+	err = tx.Begin(ctx)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err on tx.Begin", zap.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			if errX := tx.Rollback(ctx); errX != nil {
+				err = errors.Join(err, fmt.Errorf("errX: %w", errX))
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = errors.Join(err, errors.New("tx commit failed"))
+			return
+		}
+		s.invalidate(ctx, key)
+	}()
+
+	err = tx.TemplateRepo().Delete(ctx, key)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to delete template", zap.Error(err), zap.String("key", key))
+		return err
+	}
+
+	err = outbox.Publish(ctx, tx.OutboxRepo(), stream.Audit, subject.AuditAdd, admin.Audit{
+		AdminId:   a.ID,
+		IpAddress: a.IP,
+		EventName: admin.DeleteTemplateEvent,
+		OldData:   *selected,
+		NewData:   template.Template{},
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to save audit event to outbox", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) invalidate(ctx context.Context, key string) {
+	err := s.cache.Delete(ctx, _cacheKey+key)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err from cache.Delete", zap.Error(err), zap.String("key", key))
+	}
+}
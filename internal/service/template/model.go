@@ -0,0 +1,86 @@
+package template
+
+import (
+	"time"
+
+	"notifications/internal/lib/language"
+	"notifications/internal/repo/template"
+)
+
+// Variable types
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeDate    = "date"
+)
+
+const (
+	_keyRegex      = "^[a-z0-9_.-]{1,64}$"
+	_cacheKey      = ":template:"
+	_cacheTTL      = 5 * time.Minute
+	_dateLayout    = "2006-01-02"
+	_missingKeyErr = "missingkey=error"
+	_defaultLimit  = 20
+)
+
+type Template struct {
+	Key       string            `json:"key"`
+	Title     language.Language `json:"title"`
+	Body      language.Language `json:"body"`
+	Variables []Variable        `json:"variables"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+type Variable struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type Request struct {
+	Key       string
+	Title     language.Language
+	Body      language.Language
+	Variables []Variable
+}
+
+type Recipient struct {
+	UserID   int
+	Phone    string
+	Language string
+}
+
+type Content struct {
+	Language string `json:"language"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+}
+
+func (t *Template) toService(item *template.Template) {
+	t.Key = item.Key
+	t.Title = item.Title
+	t.Body = item.Body
+	t.CreatedAt = item.CreatedAt
+	t.UpdatedAt = item.UpdatedAt
+
+	t.Variables = make([]Variable, 0, len(item.Variables))
+	for _, v := range item.Variables {
+		t.Variables = append(t.Variables, Variable{Name: v.Name, Type: v.Type})
+	}
+}
+
+func toRepo(request *Request) *template.Template {
+	var item = &template.Template{
+		Key:       request.Key,
+		Title:     request.Title,
+		Body:      request.Body,
+		Variables: make([]template.Variable, 0, len(request.Variables)),
+	}
+
+	for _, v := range request.Variables {
+		item.Variables = append(item.Variables, template.Variable{Name: v.Name, Type: v.Type})
+	}
+
+	return item
+}
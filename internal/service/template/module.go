@@ -0,0 +1,76 @@
+package template
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"notifications/internal/db/tx"
+	"notifications/internal/lib/country"
+	"notifications/internal/repo/template"
+	"notifications/internal/repo/user"
+	"notifications/internal/service/admin"
+	"notifications/pkg/lib/cache"
+	"notifications/pkg/lib/observer/logger"
+	"notifications/pkg/lib/observer/sentry"
+)
+
+var Module = fx.Provide(New)
+
+type Service interface {
+	reader
+	writer
+	renderer
+}
+
+type reader interface {
+	GetTemplates(ctx context.Context, limit, offset uint) ([]*Template, error)
+	GetTemplate(ctx context.Context, key string) (*Template, error)
+}
+
+type writer interface {
+	Create(context.Context, admin.Admin, *Request) (*Template, error)
+	Update(context.Context, admin.Admin, *Request) (*Template, error)
+	Delete(ctx context.Context, a admin.Admin, key string) error
+}
+
+type renderer interface {
+	// Render validates the variables against the template and renders it in the language of the recipient
+	// The language is taken from the recipient, then from the stored user, then it's the default one of the user's country
+	// and falls back to russian if the user is unknown
+	Render(ctx context.Context, key string, recipient Recipient, variables map[string]any) (*Content, error)
+}
+
+type Params struct {
+	fx.In
+
+	Logger       logger.Logger
+	Sentry       sentry.Sentry
+	Cache        cache.Cache
+	Countries    country.Registry
+	TemplateRepo template.Repo
+	UserRepo     user.Repo
+	Transactor   tx.Transactor
+}
+
+type service struct {
+	logger       logger.Logger
+	sentry       sentry.Sentry
+	cache        cache.Cache
+	countries    country.Registry
+	templateRepo template.Repo
+	userRepo     user.Repo
+	transactor   tx.Transactor
+}
+
+func New(p Params) Service {
+	return &service{
+		logger:       p.Logger,
+		sentry:       p.Sentry,
+		cache:        p.Cache,
+		countries:    p.Countries,
+		templateRepo: p.TemplateRepo,
+		userRepo:     p.UserRepo,
+		transactor:   p.Transactor,
+	}
+}
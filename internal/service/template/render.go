@@ -0,0 +1,115 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/lib/language"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/template"
+	"notifications/internal/repo/user"
+	"notifications/pkg/util/strset"
+)
+
+func (s *service) Render(ctx context.Context, key string, recipient Recipient, variables map[string]any) (*Content, error) {
+	item, err := s.getCached(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var t = new(Template)
+	t.toService(item)
+
+	vals, err := values(t.Variables, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var content = &Content{Language: s.language(ctx, recipient)}
+
+	content.Title, err = execute(key, pick(t.Title, content.Language), vals)
+	if err != nil {
+		s.logger.Error("failed to render template title", zap.Error(err), zap.String("key", key))
+		return nil, resp.Wrap(resp.ErrBadRequest, err.Error())
+	}
+
+	content.Body, err = execute(key, pick(t.Body, content.Language), vals)
+	if err != nil {
+		s.logger.Error("failed to render template body", zap.Error(err), zap.String("key", key))
+		return nil, resp.Wrap(resp.ErrBadRequest, err.Error())
+	}
+
+	return content, nil
+}
+
+func (s *service) getCached(ctx context.Context, key string) (*template.Template, error) {
+	var item = new(template.Template)
+
+	err := s.cache.Get(ctx, _cacheKey+key, item)
+	if err == nil {
+		return item, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		s.logger.Warning("err from cache.Get", zap.Error(err), zap.String("key", key))
+	}
+
+	item, err = s.templateRepo.GetByKey(ctx, key)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("failed to get template", zap.Error(err), zap.String("key", key))
+			return nil, err
+		}
+		return nil, resp.Wrap(resp.ErrBadRequest, "template not found: "+key)
+	}
+
+	err = s.cache.Set(ctx, _cacheKey+key, item, _cacheTTL)
+	if err != nil {
+		s.logger.Warning("err from cache.Set", zap.Error(err), zap.String("key", key))
+	}
+
+	return item, nil
+}
+
+func (s *service) language(ctx context.Context, recipient Recipient) string {
+	if slices.Contains(language.GetAll(), recipient.Language) {
+		return recipient.Language
+	}
+
+	var (
+		selectedUser *user.User
+		err          error
+	)
+
+	switch {
+	case recipient.UserID != 0:
+		selectedUser, err = s.userRepo.GetByUserID(ctx, recipient.UserID)
+	case !strset.IsEmpty(recipient.Phone):
+		selectedUser, err = s.userRepo.GetActiveByPhone(ctx, recipient.Phone)
+	default:
+		return language.RU
+	}
+
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.logger.Warning("failed to get user language", zap.Error(err), zap.Int("userID", recipient.UserID))
+		}
+		return language.RU
+	}
+
+	userCountry, err := s.countries.ByID(selectedUser.CountryID)
+	if err != nil {
+		s.logger.Warning("failed to get user country", zap.Error(err), zap.Int("userID", selectedUser.UserID))
+		if !slices.Contains(language.GetAll(), selectedUser.Language) {
+			return language.RU
+		}
+		return selectedUser.Language
+	}
+
+	return userCountry.ResolveLanguage(selectedUser.Language)
+}
@@ -0,0 +1,147 @@
+package template
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/lib/language"
+)
+
+var _keyPattern = regexp.MustCompile(_keyRegex)
+
+// validate checks the key, the variable declarations and that the texts refer only to the declared variables
+func validate(request *Request) error {
+	if !_keyPattern.MatchString(request.Key) {
+		return resp.Wrap(resp.ErrBadRequest, "key is not valid, allowed: "+_keyRegex)
+	}
+
+	if !request.Body.ValidAny() {
+		return resp.Wrap(resp.ErrBadRequest, "body cannot be empty")
+	}
+
+	var sample = make(map[string]string, len(request.Variables))
+	for _, v := range request.Variables {
+		if !_keyPattern.MatchString(v.Name) || strings.ContainsAny(v.Name, ".-") {
+			return resp.Wrap(resp.ErrBadRequest, "variable name is not valid: "+v.Name)
+		}
+		if !slices.Contains([]string{TypeString, TypeNumber, TypeInteger, TypeDate}, v.Type) {
+			return resp.Wrap(resp.ErrBadRequest, "variable type is not valid: "+v.Type)
+		}
+		if _, ok := sample[v.Name]; ok {
+			return resp.Wrap(resp.ErrBadRequest, "variable is declared twice: "+v.Name)
+		}
+		sample[v.Name] = v.Name
+	}
+
+	for name, texts := range map[string]language.Language{"title": request.Title, "body": request.Body} {
+		for _, field := range texts.GetAllWithLang() {
+			if _, err := execute(request.Key, field.Val, sample); err != nil {
+				return resp.Wrap(resp.ErrBadRequest, fmt.Sprintf("%s.%s: %s", name, field.Key, err.Error()))
+			}
+		}
+	}
+
+	return nil
+}
+
+// values checks that all the declared variables are supplied with the values of their types
+// and formats them to be rendered
+func values(declared []Variable, variables map[string]any) (map[string]string, error) {
+	var result = make(map[string]string, len(declared))
+
+	for _, v := range declared {
+		value, ok := variables[v.Name]
+		if !ok || value == nil {
+			return nil, resp.Wrap(resp.ErrBadRequest, "variable is not supplied: "+v.Name)
+		}
+
+		formatted, ok := format(v.Type, value)
+		if !ok {
+			return nil, resp.Wrap(resp.ErrBadRequest, fmt.Sprintf("variable %s must be %s", v.Name, v.Type))
+		}
+
+		result[v.Name] = formatted
+	}
+
+	return result, nil
+}
+
+func format(varType string, value any) (string, bool) {
+	switch varType {
+	case TypeNumber:
+		switch val := value.(type) {
+		case float64:
+			return strconv.FormatFloat(val, 'f', -1, 64), true
+		case string:
+			_, err := strconv.ParseFloat(val, 64)
+			return val, err == nil
+		}
+	case TypeInteger:
+		switch val := value.(type) {
+		case float64:
+			return strconv.FormatInt(int64(val), 10), val == math.Trunc(val)
+		case string:
+			_, err := strconv.ParseInt(val, 10, 64)
+			return val, err == nil
+		}
+	case TypeDate:
+		if val, ok := value.(string); ok {
+			if _, err := time.Parse(_dateLayout, val); err == nil {
+				return val, true
+			}
+			_, err := time.Parse(time.RFC3339, val)
+			return val, err == nil
+		}
+	case TypeString:
+		switch val := value.(type) {
+		case string:
+			return val, true
+		case float64, bool:
+			return fmt.Sprint(val), true
+		}
+	}
+
+	return "", false
+}
+
+func execute(name, text string, values map[string]string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New(name).Option(_missingKeyErr).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	err = tmpl.Execute(&buf, values)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// pick returns the text in the language, falling back to russian and then to any filled language
+func pick(texts language.Language, lang string) string {
+	if text := texts.Get(lang); text != "" {
+		return text
+	}
+	if text := texts.Get(language.RU); text != "" {
+		return text
+	}
+	for _, text := range texts.GetAll() {
+		if text != "" {
+			return text
+		}
+	}
+	return ""
+}
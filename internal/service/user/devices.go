@@ -0,0 +1,228 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/transport/broker/stream"
+	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/user"
+	"notifications/pkg/lib/notifier/apns"
+	"notifications/pkg/lib/notifier/firebase"
+	"notifications/pkg/lib/notifier/hms"
+	"notifications/pkg/lib/notifier/webpush"
+	"notifications/pkg/util/strset"
+)
+
+func (s *service) RegisterDevice(ctx context.Context, device Device) error {
+	selectedUser, err := s.userRepo.GetByUserID(ctx, device.UserID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting user", zap.Error(err), zap.Int("userID", device.UserID))
+			return err
+		}
+		s.logger.Warning("user not found", zap.Int("userID", device.UserID))
+		return nil
+	}
+
+	if strset.IsEmpty(device.Token) {
+		return nil
+	}
+	if device.Provider == user.ProviderWebPush && (strset.IsEmpty(device.P256dh) || strset.IsEmpty(device.Auth)) {
+		s.logger.Warning("web push subscription has no keys", zap.Int("userID", device.UserID))
+		return nil
+	}
+	if device.Provider == user.ProviderWebPush && !webpush.IsValidEndpoint(device.Token) {
+		s.logger.Warning("web push endpoint is not allowed", zap.Int("userID", device.UserID), zap.String("endpoint", device.Token))
+		return nil
+	}
+
+	// the token the user registered before the devices is kept as the device of its own
+	if len(selectedUser.Devices) == 0 && !strset.IsEmpty(selectedUser.Token) && selectedUser.Token != device.Token {
+		err = s.userRepo.UpsertDevice(ctx, user.Device{UserID: device.UserID, Token: selectedUser.Token})
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during registering device", zap.Error(err), zap.Int("userID", device.UserID))
+			return err
+		}
+	}
+
+	err = s.userRepo.UpsertDevice(ctx, user.Device{
+		UserID:     device.UserID,
+		Token:      device.Token,
+		Provider:   device.Provider,
+		P256dh:     device.P256dh,
+		Auth:       device.Auth,
+		Platform:   device.Platform,
+		AppVersion: device.AppVersion,
+		Locale:     device.Locale,
+	})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during registering device", zap.Error(err), zap.Int("userID", device.UserID))
+		return err
+	}
+
+	// the token of the user is the one of the latest registered app, the browsers are not apps
+	if device.Provider != user.ProviderWebPush && selectedUser.Token != device.Token {
+		err = s.userRepo.UpdateToken(ctx, device.UserID, device.Token)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during updating firebase token", zap.Error(err), zap.Int("userID", device.UserID))
+			return err
+		}
+	}
+
+	if slices.ContainsFunc(selectedUser.ActiveDevices(), func(d user.DeviceToken) bool { return d.Token == device.Token }) {
+		return nil
+	}
+
+	eventRelations, err := s.userRepo.GetTopicsByUserID(ctx, device.UserID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting topics", zap.Error(err), zap.Int("userID", device.UserID))
+			return err
+		}
+		return nil
+	}
+
+	// the new device gets the pushes of the events the user is subscribed to
+	var devices = []user.DeviceToken{{Token: device.Token, Provider: provider(device.Provider)}}
+	for _, rel := range eventRelations {
+		s.subscribeDevices(ctx, devices, buildTopic(rel.Topic, rel.Lang))
+	}
+
+	return nil
+}
+
+func (s *service) RemoveDevice(ctx context.Context, userID int, token string) error {
+	selectedUser, err := s.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting user", zap.Error(err), zap.Int("userID", userID))
+			return err
+		}
+		return nil
+	}
+
+	if strset.IsEmpty(token) {
+		token = selectedUser.Token
+	}
+	if strset.IsEmpty(token) {
+		return nil
+	}
+
+	err = s.userRepo.DeactivateDevice(ctx, userID, token)
+	if err != nil && !errors.Is(err, repomodel.ErrNotFound) {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during deactivating device", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+
+	if selectedUser.Token != token {
+		return nil
+	}
+
+	// the token of the user falls back to the one of the latest seen app left
+	var next string
+	for _, device := range selectedUser.Devices {
+		if device.Token != token && device.Provider != user.ProviderWebPush {
+			next = device.Token
+			break
+		}
+	}
+
+	err = s.userRepo.UpdateToken(ctx, userID, next)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during updating firebase token", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) PublishTokenRemoved(userID int, token string) {
+	err := s.nats.Publish(stream.Notifications, subject.NotificationsFcmRegistrationTokenRemoved, TokenRemoved{UserID: userID, Token: token})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("error on publish event", zap.Error(err), zap.Int("userID", userID))
+	}
+}
+
+// IsInvalidTokenErr reports if the push failed since the token of the device cannot be used anymore and should be removed
+func IsInvalidTokenErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	return firebase.IsValidationErr(err) || firebase.IsTokenErr(err) || hms.IsValidationErr(err) || apns.IsTokenErr(err) || webpush.IsExpiredErr(err)
+}
+
+// subscribeDevices subscribes the devices to the topic at their providers, the providers without topics are skipped
+func (s *service) subscribeDevices(ctx context.Context, devices []user.DeviceToken, topic string) {
+	for provider, tokens := range user.ByProvider(devices) {
+		if !user.HasTopics(provider) {
+			continue
+		}
+
+		var (
+			response any
+			err      error
+		)
+		switch provider {
+		case user.ProviderHms:
+			response, err = s.hmsTopicMan.Subscribe(ctx, tokens, topic)
+		default:
+			response, err = s.fcmTopicMan.SubscribeTokens(ctx, tokens, topic)
+		}
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during subscribing to topic", zap.Error(err), zap.String("topic", topic), zap.String("provider", provider))
+			continue
+		}
+
+		s.logger.Info("Subscribed to topic", zap.Any("response", response), zap.String("topic", topic), zap.String("provider", provider))
+	}
+}
+
+// unsubscribeDevices unsubscribes the devices from the topic at their providers, the providers without topics are skipped
+func (s *service) unsubscribeDevices(ctx context.Context, devices []user.DeviceToken, topic string) {
+	for provider, tokens := range user.ByProvider(devices) {
+		if !user.HasTopics(provider) {
+			continue
+		}
+
+		var (
+			response any
+			err      error
+		)
+		switch provider {
+		case user.ProviderHms:
+			response, err = s.hmsTopicMan.Unsubscribe(ctx, tokens, topic)
+		default:
+			response, err = s.fcmTopicMan.UnsubscribeTokens(ctx, tokens, topic)
+		}
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during unsubscribing from topic", zap.Error(err), zap.String("topic", topic), zap.String("provider", provider))
+			continue
+		}
+
+		s.logger.Info("Unsubscribed from topic", zap.Any("response", response), zap.String("topic", topic), zap.String("provider", provider))
+	}
+}
+
+// provider returns the provider of the device, FCM if it's not known
+func provider(provider string) string {
+	if provider == "" {
+		return user.ProviderFcm
+	}
+	return provider
+}
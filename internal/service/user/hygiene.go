@@ -0,0 +1,170 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"firebase.google.com/go/v4/messaging"
+	"go.uber.org/zap"
+
+	"notifications/internal/api/transport/broker/stream"
+	"notifications/internal/api/transport/broker/subject"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/user"
+	"notifications/internal/service/admin"
+	"notifications/pkg/lib/cache"
+	"notifications/pkg/lib/notifier/firebase"
+)
+
+const (
+	_hygieneLease    = "job:token-hygiene"
+	_hygieneLeaseTTL = 30 * time.Second
+	// _hygieneBatch is the max number of the messages of SendEachDryRun
+	_hygieneBatch = 500
+	// _revalidateAfter is the time the valid stale token is not validated again
+	_revalidateAfter = 7 * 24 * time.Hour
+
+	_defaultStaleDays   = 30
+	_defaultHygieneRate = 100
+)
+
+// ValidateTokens validates the FCM tokens of the devices not seen for the stale days by the dry run in batches, the invalid
+// ones are published to be removed. The batches are paced by the rate of the tokens per second and the run is stopped
+// once FCM reports the quota is exceeded, the tokens left are validated by the next run. The stats of the run are
+// published to the audit
+func (s *service) ValidateTokens() {
+	lease, err := s.cache.Acquire(context.Background(), _hygieneLease, _hygieneLeaseTTL)
+	if err != nil {
+		if !errors.Is(err, cache.ErrLocked) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during acquiring token hygiene lease", zap.Error(err))
+			return
+		}
+		s.logger.Info("token hygiene is run by another pod")
+		return
+	}
+
+	defer func() {
+		if err := lease.Release(context.Background()); err != nil {
+			s.logger.Error("err occurred during releasing token hygiene lease", zap.Error(err))
+		}
+	}()
+
+	var (
+		ctx             = lease.Context()
+		startedAt       = time.Now()
+		seenBefore      = startedAt.AddDate(0, 0, -s.hygiene.staleDays)
+		validatedBefore = startedAt.Add(-_revalidateAfter)
+		pace            = time.NewTicker(time.Second * _hygieneBatch / time.Duration(s.hygiene.rate))
+		stats           = HygieneStats{StartedAt: startedAt}
+		cursor          string
+	)
+	defer pace.Stop()
+
+loop:
+	for {
+		devices, err := s.userRepo.GetStaleDevices(ctx, user.ProviderFcm, seenBefore, validatedBefore, cursor, _hygieneBatch)
+		if err != nil {
+			if !errors.Is(err, repomodel.ErrNotFound) {
+				s.sentry.CaptureException(err)
+				s.logger.Error("err occurred during getting stale devices", zap.Error(err))
+			}
+			break loop
+		}
+
+		cursor = devices[len(devices)-1].Token
+
+		throttled, err := s.validateBatch(ctx, devices, &stats)
+		if err != nil {
+			break loop
+		}
+		if throttled {
+			stats.Throttled = true
+			s.logger.Warning("token hygiene is stopped by the FCM quota", zap.String("cursor", cursor))
+			break loop
+		}
+		if len(devices) < _hygieneBatch {
+			break loop
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Warning("token hygiene is interrupted", zap.Error(context.Cause(ctx)))
+			break loop
+		case <-pace.C:
+		}
+	}
+
+	stats.FinishedAt = time.Now()
+	s.logger.Info("token hygiene is finished",
+		zap.Int("checked", stats.Checked),
+		zap.Int("valid", stats.Valid),
+		zap.Int("invalid", stats.Invalid),
+		zap.Int("failed", stats.Failed),
+		zap.Bool("throttled", stats.Throttled),
+		zap.Duration("duration", stats.FinishedAt.Sub(stats.StartedAt)))
+
+	s.publishHygieneStats(stats)
+}
+
+// publishHygieneStats publishes the stats of the run to the audit, the run is made by the system, not an admin
+func (s *service) publishHygieneStats(stats HygieneStats) {
+	err := s.nats.Publish(stream.Audit, subject.AuditAdd, admin.Audit{
+		EventName: admin.TokenHygieneEvent,
+		NewData:   stats,
+		CreatedAt: stats.FinishedAt,
+	})
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("failed to publish audit event", zap.Error(err))
+	}
+}
+
+// validateBatch validates the tokens of the devices by the dry run and reports if FCM throttled it,
+// the valid tokens are not validated again for _revalidateAfter
+func (s *service) validateBatch(ctx context.Context, devices []user.Device, stats *HygieneStats) (throttled bool, err error) {
+	var messages = make([]*messaging.Message, 0, len(devices))
+	for _, device := range devices {
+		messages = append(messages, &messaging.Message{Token: device.Token})
+	}
+
+	response, err := s.fcmSender.SendEachDryRun(ctx, messages)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during validating tokens", zap.Error(err))
+		return false, err
+	}
+
+	var valid = make([]string, 0, len(devices))
+	for i, res := range response.Responses {
+		switch {
+		case res.Success:
+			valid = append(valid, devices[i].Token)
+		// the message of the dry run has the token only, so the invalid argument is the malformed token
+		case IsInvalidTokenErr(res.Error) || firebase.ErrCode(res.Error) == firebase.ErrCodeInvalidArgument:
+			stats.Invalid++
+			s.PublishTokenRemoved(devices[i].UserID, devices[i].Token)
+		default:
+			stats.Failed++
+			switch firebase.ErrCode(res.Error) {
+			case firebase.ErrCodeQuotaExceeded, firebase.ErrCodeMessageRateExceeded:
+				throttled = true
+			}
+		}
+	}
+
+	stats.Checked += len(devices)
+	stats.Valid += len(valid)
+
+	if len(valid) != 0 {
+		err = s.userRepo.MarkDevicesValidated(ctx, valid)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during marking devices validated", zap.Error(err))
+			return throttled, err
+		}
+	}
+
+	return throttled, nil
+}
@@ -0,0 +1,234 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	firebaseapp "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+	"github.com/getsentry/sentry-go"
+	"google.golang.org/api/option"
+
+	"notifications/internal/repo/user"
+	"notifications/internal/service/admin"
+	"notifications/pkg/lib/broker/nats"
+	"notifications/pkg/lib/notifier/firebase"
+	"notifications/pkg/lib/observer/logger"
+)
+
+const _testProjectID = "test"
+
+// _fcmErrors are the replies of the local FCM server by the token, the other tokens are valid
+var _fcmErrors = map[string]struct {
+	status    int
+	grpc      string
+	errorCode string
+}{
+	"unregistered": {status: http.StatusNotFound, grpc: "NOT_FOUND", errorCode: "UNREGISTERED"},
+	"malformed":    {status: http.StatusBadRequest, grpc: "INVALID_ARGUMENT", errorCode: "INVALID_ARGUMENT"},
+	"quota":        {status: http.StatusTooManyRequests, grpc: "RESOURCE_EXHAUSTED", errorCode: "QUOTA_EXCEEDED"},
+	"internal":     {status: http.StatusInternalServerError, grpc: "INTERNAL", errorCode: "INTERNAL"},
+}
+
+// fakeSender is the FCM sender of the messaging client of the local FCM server, so the errors of the responses
+// are the ones of the SDK
+type fakeSender struct {
+	firebase.Sender
+	client *messaging.Client
+}
+
+func (f *fakeSender) SendEachDryRun(ctx context.Context, messages []*messaging.Message) (*messaging.BatchResponse, error) {
+	return f.client.SendEachDryRun(ctx, messages)
+}
+
+func newFakeSender(t *testing.T) *fakeSender {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ValidateOnly bool `json:"validate_only"`
+			Message      struct {
+				Token string `json:"token"`
+			} `json:"message"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || !request.ValidateOnly {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		reply, ok := _fcmErrors[request.Message.Token]
+		if !ok {
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "projects/" + _testProjectID + "/messages/1"})
+			return
+		}
+
+		w.WriteHeader(reply.status)
+		_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{
+			"code":    reply.status,
+			"status":  reply.grpc,
+			"message": request.Message.Token,
+			"details": []map[string]any{{
+				"@type":     "type.googleapis.com/google.firebase.fcm.v1.FcmError",
+				"errorCode": reply.errorCode,
+			}},
+		}})
+	}))
+	t.Cleanup(srv.Close)
+
+	var ctx = context.Background()
+
+	app, err := firebaseapp.NewApp(ctx, &firebaseapp.Config{ProjectID: _testProjectID},
+		option.WithEndpoint(srv.URL+"/v1"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := app.Messaging(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &fakeSender{client: client}
+}
+
+type fakeRepo struct {
+	user.Repo
+	validated []string
+}
+
+func (f *fakeRepo) MarkDevicesValidated(_ context.Context, tokens []string) error {
+	f.validated = append(f.validated, tokens...)
+	return nil
+}
+
+type fakeNats struct {
+	nats.Event
+
+	mu      sync.Mutex
+	removed []string
+	audits  []admin.Audit
+}
+
+func (f *fakeNats) Publish(_, _ string, msg any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch msg := msg.(type) {
+	case TokenRemoved:
+		f.removed = append(f.removed, msg.Token)
+	case admin.Audit:
+		f.audits = append(f.audits, msg)
+	}
+	return nil
+}
+
+type fakeSentry struct{}
+
+func (fakeSentry) CaptureException(error) {}
+
+func (fakeSentry) CurrentHub() *sentry.Hub { return nil }
+
+func Test_validateBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokens    []string
+		stats     HygieneStats
+		throttled bool
+		validated []string
+		removed   []string
+	}{
+		{
+			name:      "valid tokens",
+			tokens:    []string{"t1", "t2"},
+			stats:     HygieneStats{Checked: 2, Valid: 2},
+			validated: []string{"t1", "t2"},
+		},
+		{
+			name:      "invalid tokens are removed",
+			tokens:    []string{"t1", "unregistered", "malformed", "t2"},
+			stats:     HygieneStats{Checked: 4, Valid: 2, Invalid: 2},
+			validated: []string{"t1", "t2"},
+			removed:   []string{"malformed", "unregistered"},
+		},
+		{
+			name:   "failed token is kept",
+			tokens: []string{"internal"},
+			stats:  HygieneStats{Checked: 1, Failed: 1},
+		},
+		{
+			name:      "quota exceeded throttles",
+			tokens:    []string{"t1", "quota", "unregistered"},
+			stats:     HygieneStats{Checked: 3, Valid: 1, Invalid: 1, Failed: 1},
+			throttled: true,
+			validated: []string{"t1"},
+			removed:   []string{"unregistered"},
+		},
+	}
+
+	var sender = newFakeSender(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				repo   = new(fakeRepo)
+				broker = new(fakeNats)
+				s      = &service{
+					logger:    logger.NewNop(),
+					sentry:    fakeSentry{},
+					nats:      broker,
+					fcmSender: sender,
+					userRepo:  repo,
+				}
+				devices = make([]user.Device, 0, len(tt.tokens))
+				stats   HygieneStats
+			)
+			for i, token := range tt.tokens {
+				devices = append(devices, user.Device{UserID: i + 1, Token: token})
+			}
+
+			throttled, err := s.validateBatch(context.Background(), devices, &stats)
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if throttled != tt.throttled {
+				t.Errorf("throttled = %v, want %v", throttled, tt.throttled)
+			}
+			if stats != tt.stats {
+				t.Errorf("stats = %+v, want %+v", stats, tt.stats)
+			}
+			if !reflect.DeepEqual(repo.validated, tt.validated) {
+				t.Errorf("validated = %q, want %q", repo.validated, tt.validated)
+			}
+
+			slices.Sort(broker.removed)
+			if !reflect.DeepEqual(broker.removed, tt.removed) {
+				t.Errorf("removed = %q, want %q", broker.removed, tt.removed)
+			}
+		})
+	}
+}
+
+func Test_publishHygieneStats(t *testing.T) {
+	var (
+		broker = new(fakeNats)
+		s      = &service{logger: logger.NewNop(), sentry: fakeSentry{}, nats: broker}
+		now    = time.Now()
+		stats  = HygieneStats{Checked: 3, Valid: 1, Invalid: 1, Failed: 1, Throttled: true, StartedAt: now.Add(-time.Minute), FinishedAt: now}
+	)
+
+	s.publishHygieneStats(stats)
+
+	var want = []admin.Audit{{EventName: admin.TokenHygieneEvent, NewData: stats, CreatedAt: now}}
+	if !reflect.DeepEqual(broker.audits, want) {
+		t.Errorf("audits = %+v, want %+v", broker.audits, want)
+	}
+}
@@ -1,5 +1,7 @@
 package user
 
+import "time"
+
 type User struct {
 	Phone             string
 	PersonExternalRef string
@@ -13,3 +15,52 @@ type User struct {
 const (
 	_deleted = "deleted"
 )
+
+// Device is the app installation of the user, the attributes which are not known are empty.
+// Provider is the push provider which issued the token, FCM if it's empty. The browser subscription
+// of Web Push is the device of its endpoint as the token and the keys P256dh and Auth
+type Device struct {
+	UserID     int
+	Token      string
+	Provider   string
+	P256dh     string
+	Auth       string
+	Platform   string
+	AppVersion string
+	Locale     string
+}
+
+// HygieneStats are the results of the run of the token hygiene published to the audit, the failed tokens
+// are validated by the next run. Throttled is set if the run is stopped by the FCM quota
+type HygieneStats struct {
+	Checked    int
+	Valid      int
+	Invalid    int
+	Failed     int
+	Throttled  bool
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// TokenRemoved is the payload of NotificationsFcmRegistrationTokenRemoved published when the token of the device
+// of the user is rejected by the push provider
+type TokenRemoved struct {
+	UserID int    `json:"userID"`
+	Token  string `json:"token"`
+}
+
+type Preference struct {
+	Category     string     `json:"category"`
+	Channel      string     `json:"channel"`
+	Enabled      bool       `json:"enabled"`
+	Suppressible bool       `json:"suppressible"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty"`
+}
+
+// QuietHours overrides the quiet hours of the user's country, "15:04" in local time.
+// Disabled turns the quiet hours off, enabled without start and end resets them to the country's ones
+type QuietHours struct {
+	Start   string
+	End     string
+	Enabled bool
+}
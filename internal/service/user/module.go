@@ -5,9 +5,14 @@ import (
 
 	"go.uber.org/fx"
 
+	"notifications/internal/lib/country"
 	"notifications/internal/repo/event"
 	"notifications/internal/repo/user"
+	"notifications/pkg/lib/broker/nats"
+	"notifications/pkg/lib/cache"
+	"notifications/pkg/lib/config"
 	"notifications/pkg/lib/notifier/firebase"
+	"notifications/pkg/lib/notifier/hms"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/observer/sentry"
 )
@@ -16,19 +21,50 @@ var Module = fx.Provide(New)
 
 type Service interface {
 	CreateUser(context.Context, User) error
-	UpdateToken(ctx context.Context, userID int, token string) error
+	// RegisterDevice adds the device to the ones of the user and subscribes it to the topics of the user
+	RegisterDevice(ctx context.Context, device Device) error
+	// RemoveDevice deactivates the device of the token, the token of the user is removed if the token is empty
+	RemoveDevice(ctx context.Context, userID int, token string) error
+	// PublishTokenRemoved publishes the token rejected by the provider, the device of the token is removed by RemoveDevice
+	PublishTokenRemoved(userID int, token string)
 	UpdateUserSettings(ctx context.Context, userID int, language string, isEnabled *bool) error
 	UpdateStatus(ctx context.Context, userID int, status string) error
 	UpdatePhone(ctx context.Context, userID int, phone string) error
 	UpdatePersonExternalRef(ctx context.Context, userID int, personExternalRef string) error
+	preferences
+	quietHours
+	tokenHygiene
+}
+
+type preferences interface {
+	// GetPreferences returns the preferences of the user for all the categories and channels, enabled by default
+	GetPreferences(ctx context.Context, userID int) ([]Preference, error)
+	// UpdatePreferences saves the preferences, the ones of non-suppressible categories are ignored
+	UpdatePreferences(ctx context.Context, userID int, preferences []Preference) error
+}
+
+type quietHours interface {
+	// UpdateQuietHours saves the override of the quiet hours of the user
+	UpdateQuietHours(ctx context.Context, userID int, quietHours QuietHours) error
+}
+
+type tokenHygiene interface {
+	// ValidateTokens validates the stale FCM tokens by the dry run and removes the invalid ones, the job of the worker
+	ValidateTokens()
 }
 
 type Params struct {
 	fx.In
 
+	Config      config.Config
 	Logger      logger.Logger
 	Sentry      sentry.Sentry
+	Nats        nats.Event
+	Cache       cache.Cache
+	FcmSender   firebase.Sender
 	FcmTopicMan firebase.TopicManager
+	HmsTopicMan hms.TopicManager
+	Countries   country.Registry
 	UserRepo    user.Repo
 	EventRepo   event.Repo
 }
@@ -36,17 +72,46 @@ type Params struct {
 type service struct {
 	logger      logger.Logger
 	sentry      sentry.Sentry
+	nats        nats.Event
+	cache       cache.Cache
+	fcmSender   firebase.Sender
 	fcmTopicMan firebase.TopicManager
+	hmsTopicMan hms.TopicManager
+	countries   country.Registry
 	userRepo    user.Repo
 	eventRepo   event.Repo
+	hygiene     hygieneOptions
+}
+
+// hygieneOptions are the stale days of the token validated by the hygiene and the rate of the tokens per second
+type hygieneOptions struct {
+	staleDays int
+	rate      int
 }
 
 func New(p Params) Service {
+	var hygiene = hygieneOptions{
+		staleDays: p.Config.GetInt("tokenHygiene.staleDays"),
+		rate:      p.Config.GetInt("tokenHygiene.rate"),
+	}
+	if hygiene.staleDays <= 0 {
+		hygiene.staleDays = _defaultStaleDays
+	}
+	if hygiene.rate <= 0 {
+		hygiene.rate = _defaultHygieneRate
+	}
+
 	return &service{
 		logger:      p.Logger,
 		sentry:      p.Sentry,
+		nats:        p.Nats,
+		cache:       p.Cache,
+		fcmSender:   p.FcmSender,
 		userRepo:    p.UserRepo,
 		eventRepo:   p.EventRepo,
 		fcmTopicMan: p.FcmTopicMan,
+		hmsTopicMan: p.HmsTopicMan,
+		countries:   p.Countries,
+		hygiene:     hygiene,
 	}
 }
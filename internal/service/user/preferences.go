@@ -0,0 +1,145 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/api/resp"
+	"notifications/internal/repo/repomodel"
+	"notifications/internal/repo/user"
+)
+
+var (
+	_categories = []string{user.CategoryOTP, user.CategoryTransactional, user.CategoryMarketing}
+	_channels   = []string{user.ChannelPush, user.ChannelSms, user.ChannelEmail}
+)
+
+func (s *service) GetPreferences(ctx context.Context, userID int) ([]Preference, error) {
+	_, err := s.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting user", zap.Error(err), zap.Int("userID", userID))
+			return nil, err
+		}
+		return nil, resp.ErrUserNotFound
+	}
+
+	stored, err := s.userRepo.GetPreferences(ctx, userID)
+	if err != nil && !errors.Is(err, repomodel.ErrNotFound) {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during getting preferences", zap.Error(err), zap.Int("userID", userID))
+		return nil, err
+	}
+
+	var preferences = make([]Preference, 0, len(_categories)*len(_channels))
+	for _, category := range _categories {
+		for _, channel := range _channels {
+			var p = Preference{
+				Category:     category,
+				Channel:      channel,
+				Enabled:      true,
+				Suppressible: user.Suppressible(category),
+			}
+
+			idx := slices.IndexFunc(stored, func(item user.Preference) bool {
+				return item.Category == category && item.Channel == channel
+			})
+			if idx != -1 && p.Suppressible {
+				p.Enabled = stored[idx].Enabled
+				p.UpdatedAt = &stored[idx].UpdatedAt
+			}
+
+			preferences = append(preferences, p)
+		}
+	}
+
+	return preferences, nil
+}
+
+func (s *service) UpdatePreferences(ctx context.Context, userID int, preferences []Preference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	selectedUser, err := s.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting user", zap.Error(err), zap.Int("userID", userID))
+			return err
+		}
+		return nil
+	}
+
+	var list = make([]user.Preference, 0, len(preferences))
+	for _, p := range preferences {
+		if !slices.Contains(_categories, p.Category) || !slices.Contains(_channels, p.Channel) {
+			s.logger.Warning("unknown preference", zap.Int("userID", userID), zap.String("category", p.Category), zap.String("channel", p.Channel))
+			continue
+		}
+		if !user.Suppressible(p.Category) {
+			s.logger.Warning("preference of non-suppressible category is ignored", zap.Int("userID", userID), zap.String("category", p.Category))
+			continue
+		}
+		list = append(list, user.Preference{Category: p.Category, Channel: p.Channel, Enabled: p.Enabled})
+	}
+
+	if len(list) == 0 {
+		return nil
+	}
+
+	wasOptedOut, err := s.userRepo.IsOptedOut(ctx, userID, user.CategoryMarketing, user.ChannelPush)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during getting preference", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+
+	err = s.userRepo.UpsertPreferences(ctx, userID, list)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during updating preferences", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+
+	idx := slices.IndexFunc(list, func(p user.Preference) bool {
+		return p.Category == user.CategoryMarketing && p.Channel == user.ChannelPush
+	})
+	if idx != -1 && list[idx].Enabled == wasOptedOut {
+		s.resubscribeTopics(ctx, selectedUser, list[idx].Enabled)
+	}
+
+	return nil
+}
+
+// resubscribeTopics unsubscribes the user from the topics of the events when marketing pushes are disabled
+// and subscribes back when they are enabled, the relations are kept to know the topics
+func (s *service) resubscribeTopics(ctx context.Context, selectedUser *user.User, enabled bool) {
+	var devices = selectedUser.ActiveDevices()
+	if len(devices) == 0 {
+		return
+	}
+
+	relations, err := s.userRepo.GetTopicsByUserID(ctx, selectedUser.UserID)
+	if err != nil {
+		if !errors.Is(err, repomodel.ErrNotFound) {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during getting topics", zap.Error(err), zap.Int("userID", selectedUser.UserID))
+		}
+		return
+	}
+
+	for _, rel := range relations {
+		topic := buildTopic(rel.Topic, rel.Lang)
+
+		if enabled {
+			s.subscribeDevices(ctx, devices, topic)
+		} else {
+			s.unsubscribeDevices(ctx, devices, topic)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package user
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/lib/quiethours"
+	"notifications/internal/repo/user"
+	"notifications/pkg/util/strset"
+)
+
+func (s *service) UpdateQuietHours(ctx context.Context, userID int, quietHours QuietHours) error {
+	if quietHours.Enabled && strset.IsEmpty(quietHours.Start) && strset.IsEmpty(quietHours.End) {
+		err := s.userRepo.DeleteQuietHours(ctx, userID)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("err occurred during deleting quiet hours", zap.Error(err), zap.Int("userID", userID))
+			return err
+		}
+		return nil
+	}
+
+	var override = user.QuietHours{Enabled: quietHours.Enabled}
+	if quietHours.Enabled {
+		_, err := quiethours.Parse(quietHours.Start, quietHours.End)
+		if err != nil {
+			s.logger.Warning("quiet hours are ignored", zap.Error(err), zap.Int("userID", userID))
+			return nil
+		}
+		override.Start, override.End = quietHours.Start, quietHours.End
+	}
+
+	err := s.userRepo.UpsertQuietHours(ctx, userID, override)
+	if err != nil {
+		s.sentry.CaptureException(err)
+		s.logger.Error("err occurred during updating quiet hours", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+
+	return nil
+}
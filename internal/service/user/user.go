@@ -23,13 +23,20 @@ func (s *service) CreateUser(ctx context.Context, request User) error {
 	}
 
 	if selectedUser == nil {
+		userCountry, err := s.countries.ByID(request.CountryID)
+		if err != nil {
+			s.sentry.CaptureException(err)
+			s.logger.Error("user of unknown country cannot be created", zap.Error(err), zap.Int("userID", request.UserID))
+			return err
+		}
+
 		err = s.userRepo.Create(ctx, user.User{
 			UserID:            request.UserID,
 			Phone:             request.Phone,
 			PersonExternalRef: request.PersonExternalRef,
 			Token:             request.Token,
 			Status:            request.Status,
-			Language:          request.Language,
+			Language:          userCountry.ResolveLanguage(request.Language),
 			CountryID:         request.CountryID,
 		})
 		if err != nil {
@@ -37,60 +44,15 @@ func (s *service) CreateUser(ctx context.Context, request User) error {
 			s.logger.Error("err occurred during creating user", zap.Error(err), zap.Any("request", request))
 			return err
 		}
-	}
-
-	return nil
-}
-
-func (s *service) UpdateToken(ctx context.Context, userID int, token string) error {
-	selectedUser, err := s.userRepo.GetByUserID(ctx, userID)
-	if err != nil {
-		if !errors.Is(err, repomodel.ErrNotFound) {
-			s.sentry.CaptureException(err)
-			s.logger.Error("err occurred during getting user", zap.Error(err), zap.Int("userID", userID))
-			return err
-		}
-		s.logger.Warning("user not found", zap.Int("userID", userID))
-		return nil
-	}
-
-	if selectedUser.Token == token {
-		return nil
-	}
-
-	err = s.userRepo.UpdateToken(ctx, userID, token)
-	if err != nil {
-		s.sentry.CaptureException(err)
-		s.logger.Error("err occurred during updating firebase token", zap.Error(err), zap.Int("userID", userID), zap.String("token", token))
-		return err
-	}
-
-	if strset.IsEmpty(selectedUser.Token) {
-		return nil
-	}
-
-	eventRelations, err := s.userRepo.GetTopicsByUserID(ctx, userID)
-	if err != nil && errors.Is(err, repomodel.ErrNotFound) {
-		return nil
-	}
-
-	for _, rel := range eventRelations {
-		topic := buildTopic(rel.Topic, rel.Lang)
-		response, err := s.fcmTopicMan.Unsubscribe(ctx, selectedUser.Token, topic)
-		if err != nil {
-			s.sentry.CaptureException(err)
-			s.logger.Error("err occurred during unsubscribing from topic", zap.Error(err), zap.String("topic", topic))
-		}
 
-		s.logger.Info("Unsubscribed from topic", zap.Any("response", response), zap.String("topic", topic))
-
-		response, err = s.fcmTopicMan.Subscribe(ctx, token, topic)
-		if err != nil {
-			s.sentry.CaptureException(err)
-			s.logger.Error("err occurred during unsubscribing from topic", zap.Error(err), zap.String("topic", topic))
+		if !strset.IsEmpty(request.Token) {
+			err = s.userRepo.UpsertDevice(ctx, user.Device{UserID: request.UserID, Token: request.Token})
+			if err != nil {
+				s.sentry.CaptureException(err)
+				s.logger.Error("err occurred during registering device", zap.Error(err), zap.Int("userID", request.UserID))
+				return err
+			}
 		}
-
-		s.logger.Info("Subscribed to topic", zap.Any("response", response), zap.String("topic", topic))
 	}
 
 	return nil
@@ -107,31 +69,26 @@ func (s *service) UpdateUserSettings(ctx context.Context, userID int, language s
 		return nil
 	}
 
+	if !strset.IsEmpty(language) {
+		userCountry, err := s.countries.ByID(selectedUser.CountryID)
+		if err == nil && !userCountry.Supports(language) {
+			s.logger.Warning("language is not supported in the country of the user", zap.Int("userID", userID), zap.String("language", language))
+			language = selectedUser.Language
+		}
+	}
+
 	if isEnabled == nil && selectedUser.Language == language {
 		return nil
 	}
 
-	if (selectedUser.Language != language && !strset.IsEmpty(language)) && !strset.IsEmpty(selectedUser.Token) {
+	var devices = selectedUser.ActiveDevices()
+	if (selectedUser.Language != language && !strset.IsEmpty(language)) && len(devices) != 0 {
 		eventRelations, _ := s.userRepo.GetTopicsByUserID(ctx, userID)
 
 		for _, rel := range eventRelations {
 			topic := buildTopic(rel.Topic, rel.Lang)
-			response, err := s.fcmTopicMan.Unsubscribe(ctx, selectedUser.Token, topic)
-			if err != nil {
-				s.sentry.CaptureException(err)
-				s.logger.Error("err occurred during unsubscribing from topic", zap.Error(err), zap.String("topic", topic))
-			}
-
-			s.logger.Info("Unsubscribed from topic", zap.Any("response", response), zap.String("topic", topic))
-
-			newTopic := replaceLastSegment(topic, language)
-			response, err = s.fcmTopicMan.Subscribe(ctx, selectedUser.Token, newTopic)
-			if err != nil {
-				s.sentry.CaptureException(err)
-				s.logger.Error("err occurred during subscribing from topic", zap.Error(err), zap.String("topic", topic))
-			}
-
-			s.logger.Info("Subscribed to topic", zap.Any("response", response), zap.String("newTopic", newTopic))
+			s.unsubscribeDevices(ctx, devices, topic)
+			s.subscribeDevices(ctx, devices, replaceLastSegment(topic, language))
 		}
 
 		if len(eventRelations) != 0 {
@@ -0,0 +1,141 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+type DeadLetter struct {
+	Sequence   uint64
+	Subject    string
+	Stream     string
+	OrigSubj   string
+	Consumer   string
+	Error      string
+	Data       []byte
+	Attempts   int
+	FailedAt   time.Time
+	ReceivedAt time.Time
+}
+
+func (n *natsConn) DeadLetters(ctx context.Context, dlqStream, filter string, fromSeq uint64, limit int) ([]DeadLetter, error) {
+	stream, err := n.js.Stream(ctx, dlqStream)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrStreamNotFound) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+
+	var list = make([]DeadLetter, 0, limit)
+	for seq := max(fromSeq, 1); len(list) < limit; {
+		msg, err := stream.GetMsg(ctx, seq, jetstream.WithGetMsgSubject(filter))
+		if err != nil {
+			if errors.Is(err, jetstream.ErrMsgNotFound) {
+				break
+			}
+			return nil, err
+		}
+
+		list = append(list, toDeadLetter(msg))
+		seq = msg.Sequence + 1
+	}
+
+	if len(list) == 0 {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	return list, nil
+}
+
+func (n *natsConn) DeadLetter(ctx context.Context, dlqStream string, seq uint64) (*DeadLetter, error) {
+	msg, err := n.getDeadLetter(ctx, dlqStream, seq)
+	if err != nil {
+		return nil, err
+	}
+
+	var item = toDeadLetter(msg)
+	return &item, nil
+}
+
+// Replay publishes the dead letter to its original subject as a new message and removes it from the dlq
+func (n *natsConn) Replay(ctx context.Context, dlqStream string, seq uint64) error {
+	msg, err := n.getDeadLetter(ctx, dlqStream, seq)
+	if err != nil {
+		return err
+	}
+
+	var item = toDeadLetter(msg)
+	if item.OrigSubj == "" {
+		return errors.New("dead letter has no original subject")
+	}
+
+	_, err = n.js.PublishMsg(ctx, &nats.Msg{Subject: item.OrigSubj, Data: item.Data})
+	if err != nil {
+		n.logger.Error("err occurred during replay", zap.Error(err), zap.String("subject", item.OrigSubj), zap.Uint64("seq", seq))
+		return err
+	}
+
+	stream, err := n.js.Stream(ctx, dlqStream)
+	if err != nil {
+		return err
+	}
+
+	err = stream.DeleteMsg(ctx, seq)
+	if err != nil {
+		n.logger.Error("err occurred during deleting replayed msg", zap.Error(err), zap.Uint64("seq", seq))
+		return err
+	}
+
+	n.logger.Info("Dead letter replayed", zap.String("subject", item.OrigSubj), zap.Uint64("seq", seq))
+
+	return nil
+}
+
+func (n *natsConn) getDeadLetter(ctx context.Context, dlqStream string, seq uint64) (*jetstream.RawStreamMsg, error) {
+	stream, err := n.js.Stream(ctx, dlqStream)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrStreamNotFound) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+
+	msg, err := stream.GetMsg(ctx, seq)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+func toDeadLetter(msg *jetstream.RawStreamMsg) DeadLetter {
+	var item = DeadLetter{
+		Sequence:   msg.Sequence,
+		Subject:    msg.Subject,
+		Data:       msg.Data,
+		ReceivedAt: msg.Time,
+	}
+
+	if msg.Header != nil {
+		item.Stream = msg.Header.Get(HeaderDlqStream)
+		item.OrigSubj = msg.Header.Get(HeaderDlqSubject)
+		item.Consumer = msg.Header.Get(HeaderDlqConsumer)
+		item.Error = msg.Header.Get(HeaderDlqError)
+		item.Attempts, _ = strconv.Atoi(msg.Header.Get(HeaderDlqAttempts))
+		item.FailedAt, _ = time.Parse(time.RFC3339, msg.Header.Get(HeaderDlqFailedAt))
+	}
+
+	return item
+}
@@ -16,7 +16,16 @@ var Module = fx.Provide(New)
 type Event interface {
 	Publish(stream, subj string, msg any) error
 	Subscribe(stream, subj, consumer string, handler jetstream.MessageHandler, opts ...SubscriptionOptions)
+	SubscribeWithRetry(stream, subj, consumer string, handler RetryHandler, policy RetryPolicy, opts ...SubscriptionOptions)
 	Reply(subj, qGroup string, handler nats.MsgHandler)
+	deadLetterQueue
+}
+
+type deadLetterQueue interface {
+	// DeadLetters returns the dead letters of the stream starting from the sequence, filtered by subject (wildcards are allowed)
+	DeadLetters(ctx context.Context, dlqStream, filter string, fromSeq uint64, limit int) ([]DeadLetter, error)
+	DeadLetter(ctx context.Context, dlqStream string, seq uint64) (*DeadLetter, error)
+	Replay(ctx context.Context, dlqStream string, seq uint64) error
 }
 
 type Params struct {
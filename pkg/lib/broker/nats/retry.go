@@ -0,0 +1,160 @@
+package nats
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+// Headers attached to the dead letter messages
+const (
+	HeaderDlqStream   = "Dlq-Stream"
+	HeaderDlqSubject  = "Dlq-Subject"
+	HeaderDlqConsumer = "Dlq-Consumer"
+	HeaderDlqError    = "Dlq-Error"
+	HeaderDlqAttempts = "Dlq-Attempts"
+	HeaderDlqFailedAt = "Dlq-Failed-At"
+)
+
+const (
+	_defaultBackoff    = time.Second
+	_defaultMaxBackoff = time.Minute
+	_dlqMsgMaxAge      = 7 * 24 * time.Hour
+)
+
+// RetryHandler processes the message, the message is acked if nil is returned,
+// otherwise it's redelivered according to the RetryPolicy
+type RetryHandler func(jetstream.Msg) error
+
+type RetryPolicy struct {
+	// MaxAttempts is the number of deliveries before the message is moved to the dead letter queue
+	MaxAttempts int
+	// Backoff is the delay before the first redelivery, doubled on each next attempt up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// DlqStream and DlqSubject are the stream and subject the exhausted messages are published to
+	DlqStream  string
+	DlqSubject string
+}
+
+type permanentErr struct {
+	err error
+}
+
+func (e *permanentErr) Error() string { return e.err.Error() }
+func (e *permanentErr) Unwrap() error { return e.err }
+
+// Permanent marks the error as not retryable, so the message is moved to the dead letter queue at once
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentErr{err: err}
+}
+
+func (n *natsConn) SubscribeWithRetry(stream, subj, consumer string, handler RetryHandler, policy RetryPolicy, options ...SubscriptionOptions) {
+	err := n.upsertStream(n.ctx, policy.DlqSubject, &jetstream.StreamConfig{
+		Name:        policy.DlqStream,
+		Compression: jetstream.S2Compression,
+		MaxAge:      _dlqMsgMaxAge,
+		Storage:     jetstream.FileStorage,
+		Retention:   jetstream.LimitsPolicy,
+		Replicas:    n.replicas,
+	})
+	if err != nil {
+		n.logger.Error("can't upsert dlq stream",
+			zap.Error(err),
+			zap.String("streamName", policy.DlqStream),
+			zap.String("subject", policy.DlqSubject))
+		return
+	}
+
+	// redelivery is controlled by the policy, the server must not drop the message before it's moved to the dlq
+	options = append(options, WithMaxDelivery(-1))
+
+	n.Subscribe(stream, subj, consumer, n.withRetry(stream, consumer, handler, policy), options...)
+}
+
+func (n *natsConn) withRetry(stream, consumer string, handler RetryHandler, policy RetryPolicy) jetstream.MessageHandler {
+	return func(msg jetstream.Msg) {
+		err := handler(msg)
+		if err == nil {
+			if err = msg.Ack(); err != nil {
+				n.logger.Error("msg ack error", zap.Error(err), zap.String("subject", msg.Subject()))
+			}
+			return
+		}
+
+		var attempt uint64 = 1
+		meta, mErr := msg.Metadata()
+		if mErr == nil {
+			attempt = meta.NumDelivered
+		}
+
+		var permanent *permanentErr
+		if !errors.As(err, &permanent) && attempt < uint64(policy.MaxAttempts) {
+			n.logger.Warning("msg handling failed, redelivering",
+				zap.Error(err),
+				zap.String("subject", msg.Subject()),
+				zap.Uint64("attempt", attempt))
+
+			if nErr := msg.NakWithDelay(policy.delay(attempt)); nErr != nil {
+				n.logger.Error("msg nak error", zap.Error(nErr), zap.String("subject", msg.Subject()))
+			}
+			return
+		}
+
+		dlqMsg := nats.NewMsg(policy.DlqSubject)
+		dlqMsg.Data = msg.Data()
+		dlqMsg.Header.Set(HeaderDlqStream, stream)
+		dlqMsg.Header.Set(HeaderDlqSubject, msg.Subject())
+		dlqMsg.Header.Set(HeaderDlqConsumer, consumer)
+		dlqMsg.Header.Set(HeaderDlqError, err.Error())
+		dlqMsg.Header.Set(HeaderDlqAttempts, strconv.FormatUint(attempt, 10))
+		dlqMsg.Header.Set(HeaderDlqFailedAt, time.Now().UTC().Format(time.RFC3339))
+
+		_, pErr := n.js.PublishMsg(n.ctx, dlqMsg)
+		if pErr != nil {
+			// keep the message in the stream, it will be moved to the dlq on the next delivery
+			n.logger.Error("can't publish msg to dlq", zap.Error(pErr), zap.String("subject", msg.Subject()))
+			if nErr := msg.NakWithDelay(policy.delay(attempt)); nErr != nil {
+				n.logger.Error("msg nak error", zap.Error(nErr), zap.String("subject", msg.Subject()))
+			}
+			return
+		}
+
+		n.logger.Error("msg moved to dlq",
+			zap.Error(err),
+			zap.String("subject", msg.Subject()),
+			zap.String("dlqSubject", policy.DlqSubject),
+			zap.Uint64("attempt", attempt))
+
+		if tErr := msg.Term(); tErr != nil {
+			n.logger.Error("msg term error", zap.Error(tErr), zap.String("subject", msg.Subject()))
+		}
+	}
+}
+
+func (p RetryPolicy) delay(attempt uint64) time.Duration {
+	var (
+		backoff    = p.Backoff
+		maxBackoff = p.MaxBackoff
+	)
+
+	if backoff <= 0 {
+		backoff = _defaultBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = _defaultMaxBackoff
+	}
+
+	for i := uint64(1); i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maxBackoff)
+}
@@ -90,6 +90,20 @@ func (c *cache) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, _defaultServicePrefix+key).Err()
 }
 
+// RunScript evaluates the lua script atomically, keys are prefixed the same way as in other commands.
+// In cluster mode all the keys must belong to the same hash slot, use hash tags e.g. {key} for that.
+func (c *cache) RunScript(ctx context.Context, script *redis.Script, keys []string, args ...any) (any, error) {
+	var prefixed = make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, _defaultServicePrefix+key)
+	}
+
+	if c.isCluster {
+		return script.Run(ctx, c.clientCluster, prefixed, args...).Result()
+	}
+	return script.Run(ctx, c.client, prefixed, args...).Result()
+}
+
 func (c *cache) Pipeline() redis.Pipeliner {
 	if c.isCluster {
 		return c.clientCluster.Pipeline()
@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrLocked is returned by Acquire if the lease is held by another owner
+	ErrLocked = errors.New("lease is held by another owner")
+	// ErrLeaseLost is returned by the fenced writes and is the cause of the context of the lease
+	// if the lease is expired or taken over
+	ErrLeaseLost = errors.New("lease is lost")
+	// ErrLeaseReleased is the cause of the context of the released lease
+	ErrLeaseReleased = errors.New("lease is released")
+)
+
+const (
+	_leasePrefix  = ":lease:"
+	_tokenPostfix = ":token"
+)
+
+// the lease key holds the fencing token of the owner, the token is increased with every acquire of the lease
+var (
+	_acquireScript = redis.NewScript(`
+		if redis.call('EXISTS', KEYS[1]) == 1 then
+			return 0
+		end
+		local token = redis.call('INCR', KEYS[2])
+		redis.call('SET', KEYS[1], token, 'PX', ARGV[1])
+		return token`)
+
+	_renewScript = redis.NewScript(`
+		if redis.call('GET', KEYS[1]) == ARGV[1] then
+			return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+		end
+		return 0`)
+
+	_releaseScript = redis.NewScript(`
+		if redis.call('GET', KEYS[1]) == ARGV[1] then
+			return redis.call('DEL', KEYS[1])
+		end
+		return 0`)
+
+	_fencedSetScript = redis.NewScript(`
+		if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+			return 0
+		end
+		if tonumber(ARGV[3]) > 0 then
+			redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+		else
+			redis.call('SET', KEYS[2], ARGV[2])
+		end
+		return 1`)
+
+	_fencedDeleteScript = redis.NewScript(`
+		if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+			return 0
+		end
+		redis.call('DEL', KEYS[2])
+		return 1`)
+)
+
+// Lease is the distributed lock of the name held by one owner until it's released or expired, it's renewed
+// every third of its TTL while it's held. The keys of the lease are in the hash slot of the name
+type Lease struct {
+	cache  *cache
+	key    string
+	name   string
+	token  int64
+	ttl    time.Duration
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+// Acquire acquires the lease of the name for the ttl, ErrLocked if it's held by another owner.
+// The lease is held until it's released, the ctx is done or it cannot be renewed in time
+func (c *cache) Acquire(ctx context.Context, name string, ttl time.Duration) (*Lease, error) {
+	var key = _leasePrefix + "{" + name + "}"
+
+	res, err := c.RunScript(ctx, _acquireScript, []string{key, key + _tokenPostfix}, ttl.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+
+	token, _ := res.(int64)
+	if token == 0 {
+		return nil, ErrLocked
+	}
+
+	var l = &Lease{
+		cache: c,
+		key:   key,
+		name:  name,
+		token: token,
+		ttl:   ttl,
+	}
+	l.ctx, l.cancel = context.WithCancelCause(ctx)
+
+	go l.keepAlive()
+
+	return l, nil
+}
+
+// Token returns the fencing token of the lease, the token of the next owner is greater
+func (l *Lease) Token() int64 {
+	return l.token
+}
+
+// Context returns the context which is done once the lease is lost or released, the cause is ErrLeaseLost
+// or ErrLeaseReleased
+func (l *Lease) Context() context.Context {
+	return l.ctx
+}
+
+// Release releases the lease if it's still held
+func (l *Lease) Release(ctx context.Context) error {
+	l.cancel(ErrLeaseReleased)
+	_, err := l.cache.RunScript(ctx, _releaseScript, []string{l.key}, l.token)
+	return err
+}
+
+// Set saves the value under the key of the lease if the lease is still held by the owner, ErrLeaseLost otherwise
+func (l *Lease) Set(ctx context.Context, key string, value any, dur time.Duration) error {
+	bytes, err := sonic.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	res, err := l.cache.RunScript(ctx, _fencedSetScript, []string{l.key, l.key + ":" + key}, l.token, bytes, dur.Milliseconds())
+	if err != nil {
+		return err
+	}
+	if ok, _ := res.(int64); ok == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Get reads the value saved under the key of the lease, redis.Nil if there is none
+func (l *Lease) Get(ctx context.Context, key string, value any) error {
+	return l.cache.Get(ctx, l.key+":"+key, value)
+}
+
+// Delete deletes the key of the lease if the lease is still held by the owner, ErrLeaseLost otherwise
+func (l *Lease) Delete(ctx context.Context, key string) error {
+	res, err := l.cache.RunScript(ctx, _fencedDeleteScript, []string{l.key, l.key + ":" + key}, l.token)
+	if err != nil {
+		return err
+	}
+	if ok, _ := res.(int64); ok == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// keepAlive renews the lease until it's done, the lease is lost if it's taken over or not renewed within its TTL
+func (l *Lease) keepAlive() {
+	var (
+		ticker  = time.NewTicker(l.ttl / 3)
+		renewed = time.Now()
+	)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C:
+			res, err := l.cache.RunScript(l.ctx, _renewScript, []string{l.key}, l.token, l.ttl.Milliseconds())
+			if err != nil {
+				l.cache.logger.Warning("err occurred during renewing lease", zap.Error(err), zap.String("name", l.name))
+				if time.Since(renewed) >= l.ttl {
+					l.cancel(ErrLeaseLost)
+				}
+				continue
+			}
+			if ok, _ := res.(int64); ok == 0 {
+				l.cancel(ErrLeaseLost)
+				continue
+			}
+			renewed = time.Now()
+		}
+	}
+}
@@ -33,6 +33,8 @@ type Cache interface {
 	writer
 	reader
 	pipeliner
+	scripter
+	locker
 }
 
 type writer interface {
@@ -53,6 +55,15 @@ type pipeliner interface {
 	Pipeline() redis.Pipeliner
 }
 
+// locker acquires the leases which are safe across the pods, the writes of the lease are fenced by its token
+type locker interface {
+	Acquire(ctx context.Context, name string, ttl time.Duration) (*Lease, error)
+}
+
+type scripter interface {
+	RunScript(ctx context.Context, script *redis.Script, keys []string, args ...any) (any, error)
+}
+
 const (
 	_defaultPoolSize     = 100
 	_defaultPoolTimeout  = 2 * time.Minute
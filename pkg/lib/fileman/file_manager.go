@@ -26,6 +26,19 @@ func (f *file) Upload(file io.Reader, bucket *string, dir, fileName string) erro
 	return nil
 }
 
+func (f *file) Download(bucket *string, dir, fileName string) (io.ReadCloser, error) {
+	output, err := f.awsS3.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: bucket,
+		Key:    aws.String(dir + fileName),
+	})
+	if err != nil {
+		f.logger.Error("err from f.awsS3.GetObject", zap.Error(err))
+		return nil, err
+	}
+
+	return output.Body, nil
+}
+
 func (f *file) Remove(bucket *string, dir, fileName string) error {
 	_, err := f.awsS3.DeleteObject(context.Background(), &s3.DeleteObjectInput{
 		Bucket: bucket,
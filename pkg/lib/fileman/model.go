@@ -2,6 +2,7 @@ package fileman
 
 const (
 	Csv  = "csv"
+	Xlsx = "xlsx"
 	PNG  = "png"
 	JPG  = "jpg"
 	JPEG = "jpeg"
@@ -18,6 +18,7 @@ var Module = fx.Provide(New)
 
 type FileManager interface {
 	Upload(uploadFile io.Reader, bucket *string, dir, fileName string) error
+	Download(bucket *string, dir, fileName string) (io.ReadCloser, error)
 	Remove(bucket *string, dir, fileName string) error
 }
 
@@ -7,9 +7,12 @@ import (
 	"notifications/pkg/lib/cache"
 	"notifications/pkg/lib/config"
 	"notifications/pkg/lib/fileman"
+	"notifications/pkg/lib/notifier/apns"
 	"notifications/pkg/lib/notifier/firebase"
+	"notifications/pkg/lib/notifier/hms"
 	"notifications/pkg/lib/notifier/sms"
 	"notifications/pkg/lib/notifier/telegram"
+	"notifications/pkg/lib/notifier/webpush"
 	"notifications/pkg/lib/observer/logger"
 	"notifications/pkg/lib/observer/sentry"
 	"notifications/pkg/lib/scheduler"
@@ -27,6 +30,9 @@ var Module = fx.Options(
 	ratelimiter.Module,
 	fileman.Module,
 	firebase.Module,
+	hms.Module,
+	apns.Module,
+	webpush.Module,
 	telegram.Module,
 	sms.Module,
 	tinypng.Module,
@@ -0,0 +1,47 @@
+package apns
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+)
+
+// providerToken returns the cached JWT of the team signed by the .p8 key, the token is signed again once it expires
+func (a *apns) providerToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && time.Now().Before(a.expiresAt) {
+		return a.token, nil
+	}
+
+	var now = time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": a.options.TeamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = a.options.KeyID
+
+	signed, err := token.SignedString(a.key)
+	if err != nil {
+		a.logger.Error("apns: cannot sign provider token", zap.Error(err))
+		return "", err
+	}
+
+	a.token = signed
+	a.expiresAt = now.Add(_tokenTTL)
+
+	return a.token, nil
+}
+
+// resetToken drops the cached provider token if it's the rejected one
+func (a *apns) resetToken(rejected string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == rejected {
+		a.token = ""
+	}
+}
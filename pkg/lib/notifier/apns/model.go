@@ -0,0 +1,166 @@
+package apns
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Notification is the push to the device token, the notification without alert is delivered to the app only
+type Notification struct {
+	Token string
+	// Topic is the bundle ID of the app, the bundle ID of the options if it's empty
+	Topic string
+	// PushType is PushTypeAlert, PushTypeBackground or PushTypeVoip, PushTypeAlert if it's empty
+	PushType   string
+	CollapseID string
+	// Expiration is the time the push is dropped if it's not delivered, APNs doesn't store it if it's zero
+	Expiration time.Time
+	// Priority is PriorityHigh or PriorityNormal, PriorityHigh if it's zero, the background pushes are always of PriorityNormal
+	Priority int
+
+	Title    string
+	Body     string
+	Badge    *int
+	Sound    string
+	Category string
+	ThreadID string
+	// InterruptionLevel is one of the levels of iOS 15, active if it's empty
+	InterruptionLevel string
+	// RelevanceScore from 0 to 1 sorts the notifications of the app in the summary
+	RelevanceScore   *float64
+	ContentAvailable bool
+	MutableContent   bool
+	Data             map[string]string
+}
+
+const (
+	PushTypeAlert      = "alert"
+	PushTypeBackground = "background"
+	PushTypeVoip       = "voip"
+)
+
+const (
+	PriorityHigh   = 10
+	PriorityNormal = 5
+)
+
+const (
+	InterruptionPassive       = "passive"
+	InterruptionActive        = "active"
+	InterruptionTimeSensitive = "time-sensitive"
+	InterruptionCritical      = "critical"
+)
+
+const (
+	_defaultCategory = "c"
+	_defaultSound    = "s"
+	_defaultThreadID = "t"
+
+	_titleKey   = "title"
+	_messageKey = "message"
+)
+
+// AlertMSG sets the data of the notification and the alert of its title and message keys
+func AlertMSG(n *Notification, data map[string]string, priority int) {
+	n.PushType = PushTypeAlert
+	n.Priority = priority
+	n.Data = data
+	n.Title = data[_titleKey]
+	n.Body = data[_messageKey]
+	n.Category = _defaultCategory
+	n.Sound = _defaultSound
+	n.ThreadID = _defaultThreadID
+	n.ContentAvailable = true
+	n.MutableContent = true
+}
+
+// BackgroundMSG sets the data of the notification delivered to the app only
+func BackgroundMSG(n *Notification, data map[string]string) {
+	n.PushType = PushTypeBackground
+	n.Priority = PriorityNormal
+	n.Data = data
+	n.ContentAvailable = true
+}
+
+// Response is the result of the push, ApnsID is the ID of the notification given by APNs
+type Response struct {
+	ApnsID string
+}
+
+// Error is the rejection of the push by APNs
+type Error struct {
+	Status int
+	Reason string
+	ApnsID string
+}
+
+func (e *Error) Error() string {
+	return "apns: " + e.Reason
+}
+
+// Reasons of the rejected pushes
+const (
+	ReasonBadDeviceToken         = "BadDeviceToken"
+	ReasonUnregistered           = "Unregistered"
+	ReasonDeviceTokenNotForTopic = "DeviceTokenNotForTopic"
+	ReasonBadCollapseID          = "BadCollapseId"
+	ReasonBadExpirationDate      = "BadExpirationDate"
+	ReasonBadPriority            = "BadPriority"
+	ReasonPayloadTooLarge        = "PayloadTooLarge"
+	ReasonExpiredProviderToken   = "ExpiredProviderToken"
+	ReasonInvalidProviderToken   = "InvalidProviderToken"
+	ReasonTooManyRequests        = "TooManyRequests"
+	ReasonInternalServerError    = "InternalServerError"
+	ReasonServiceUnavailable     = "ServiceUnavailable"
+	ReasonUnknown                = "Unknown"
+)
+
+// ErrNotConfigured is returned by the sender without the signing key of the provider token
+var ErrNotConfigured = errors.New("apns: provider is not configured")
+
+// ErrReason returns the reason of the rejected push, ReasonUnknown if the error is not of APNs
+func ErrReason(err error) string {
+	var e *Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &e):
+		return e.Reason
+	default:
+		return ReasonUnknown
+	}
+}
+
+// IsTokenErr reports if the push failed since the device token cannot be used anymore and should be removed
+func IsTokenErr(err error) bool {
+	return slices.Contains([]string{ReasonBadDeviceToken, ReasonUnregistered, ReasonDeviceTokenNotForTopic}, ErrReason(err))
+}
+
+// isAuthErr reports if the provider token is rejected and must be signed again
+func isAuthErr(err error) bool {
+	return slices.Contains([]string{ReasonExpiredProviderToken, ReasonInvalidProviderToken}, ErrReason(err))
+}
+
+type payload map[string]any
+
+type aps struct {
+	Alert             *alert   `json:"alert,omitempty"`
+	Badge             *int     `json:"badge,omitempty"`
+	Sound             string   `json:"sound,omitempty"`
+	Category          string   `json:"category,omitempty"`
+	ThreadID          string   `json:"thread-id,omitempty"`
+	ContentAvailable  int      `json:"content-available,omitempty"`
+	MutableContent    int      `json:"mutable-content,omitempty"`
+	InterruptionLevel string   `json:"interruption-level,omitempty"`
+	RelevanceScore    *float64 `json:"relevance-score,omitempty"`
+}
+
+type alert struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type errorResponse struct {
+	Reason string `json:"reason"`
+}
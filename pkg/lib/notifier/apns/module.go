@@ -0,0 +1,109 @@
+package apns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/imroc/req/v3"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"notifications/pkg/lib/config"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Sender interface {
+	// Send sends the notification to the device token over HTTP/2
+	Send(ctx context.Context, notification *Notification) (*Response, error)
+}
+
+// Options of the APNs client, the URL can point to the sandbox or a local fake server
+type Options struct {
+	KeyID    string
+	TeamID   string
+	BundleID string
+	// Key is the .p8 signing key of the provider token in PEM
+	Key     []byte
+	URL     string
+	Timeout time.Duration
+}
+
+const (
+	_url     = "https://api.push.apple.com"
+	_keyPath = "./apns.p8"
+	_timeout = 10 * time.Second
+	// _tokenTTL is the time the provider token is used, APNs accepts it up to an hour
+	// and rejects the tokens signed more often than once in 20 minutes
+	_tokenTTL = 50 * time.Minute
+)
+
+type Params struct {
+	fx.In
+
+	Config config.Config
+	Logger logger.Logger
+}
+
+type apns struct {
+	logger  logger.Logger
+	options Options
+	client  *req.Client
+	key     *ecdsa.PrivateKey
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func New(p Params) Sender {
+	var keyPath = p.Config.GetString("apns.keyPath")
+	if keyPath == "" {
+		keyPath = _keyPath
+	}
+
+	key, err := os.ReadFile(keyPath)
+	if err != nil {
+		p.Logger.Warning("apns: signing key is not found, the provider is disabled", zap.Error(err), zap.String("keyPath", keyPath))
+	}
+
+	return NewClient(Options{
+		KeyID:    p.Config.GetString("apns.keyID"),
+		TeamID:   p.Config.GetString("apns.teamID"),
+		BundleID: p.Config.GetString("apns.bundleID"),
+		Key:      key,
+		URL:      p.Config.GetString("apns.url"),
+	}, p.Logger)
+}
+
+// NewClient creates the APNs client of the token-based auth, the sender without the key returns ErrNotConfigured
+func NewClient(options Options, logger logger.Logger) Sender {
+	if options.URL == "" {
+		options.URL = _url
+	}
+	if options.Timeout == 0 {
+		options.Timeout = _timeout
+	}
+
+	var a = &apns{
+		logger:  logger,
+		options: options,
+		client:  req.C().SetTimeout(options.Timeout).EnableForceHTTP2(),
+	}
+
+	if len(options.Key) != 0 {
+		key, err := jwt.ParseECPrivateKeyFromPEM(options.Key)
+		if err != nil {
+			logger.Error("apns: cannot parse signing key", zap.Error(err))
+			return a
+		}
+		a.key = key
+	}
+
+	return a
+}
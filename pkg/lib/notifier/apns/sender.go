@@ -0,0 +1,144 @@
+package apns
+
+import (
+	"context"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+const (
+	_authorization  = "authorization"
+	_headerPushType = "apns-push-type"
+	_headerTopic    = "apns-topic"
+	_headerPriority = "apns-priority"
+	_headerExpire   = "apns-expiration"
+	_headerCollapse = "apns-collapse-id"
+	_headerApnsID   = "apns-id"
+
+	_voipSuffix = ".voip"
+)
+
+func (a *apns) Send(ctx context.Context, notification *Notification) (*Response, error) {
+	if a.key == nil {
+		return nil, ErrNotConfigured
+	}
+
+	var (
+		url     = a.options.URL + "/3/device/" + notification.Token
+		headers = buildHeaders(notification, a.options.BundleID)
+		body    = buildPayload(notification)
+	)
+
+	providerToken, err := a.providerToken()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := a.post(ctx, url, providerToken, headers, body)
+	if !isAuthErr(err) {
+		return response, err
+	}
+
+	a.resetToken(providerToken)
+
+	providerToken, err = a.providerToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return a.post(ctx, url, providerToken, headers, body)
+}
+
+func (a *apns) post(ctx context.Context, url, providerToken string, headers map[string]string, body payload) (*Response, error) {
+	var errResponse errorResponse
+
+	resp, err := a.client.R().
+		SetContext(ctx).
+		SetHeader(_authorization, "bearer "+providerToken).
+		SetHeaders(headers).
+		SetBody(body).
+		SetErrorResult(&errResponse).
+		Post(url)
+	if err != nil {
+		a.logger.Error("apns: cannot send request", zap.Error(err))
+		return nil, err
+	}
+
+	var apnsID = resp.Header.Get(_headerApnsID)
+
+	if resp.IsErrorState() {
+		if errResponse.Reason == "" {
+			errResponse.Reason = ReasonUnknown
+		}
+		return nil, &Error{Status: resp.StatusCode, Reason: errResponse.Reason, ApnsID: apnsID}
+	}
+
+	return &Response{ApnsID: apnsID}, nil
+}
+
+func buildHeaders(n *Notification, bundleID string) map[string]string {
+	var (
+		pushType = n.PushType
+		topic    = n.Topic
+		priority = n.Priority
+	)
+	if pushType == "" {
+		pushType = PushTypeAlert
+	}
+	if topic == "" {
+		topic = bundleID
+		if pushType == PushTypeVoip {
+			topic += _voipSuffix
+		}
+	}
+	switch {
+	case pushType == PushTypeBackground:
+		priority = PriorityNormal
+	case priority == 0:
+		priority = PriorityHigh
+	}
+
+	var headers = map[string]string{
+		_headerPushType: pushType,
+		_headerTopic:    topic,
+		_headerPriority: strconv.Itoa(priority),
+	}
+	if !n.Expiration.IsZero() {
+		headers[_headerExpire] = strconv.FormatInt(n.Expiration.Unix(), 10)
+	}
+	if n.CollapseID != "" {
+		headers[_headerCollapse] = n.CollapseID
+	}
+
+	return headers
+}
+
+// buildPayload puts the aps dictionary and the data of the notification as the custom keys
+func buildPayload(n *Notification) payload {
+	var body = make(payload, len(n.Data)+1)
+	for key, value := range n.Data {
+		body[key] = value
+	}
+
+	var dictionary = aps{
+		Badge:             n.Badge,
+		Sound:             n.Sound,
+		Category:          n.Category,
+		ThreadID:          n.ThreadID,
+		InterruptionLevel: n.InterruptionLevel,
+		RelevanceScore:    n.RelevanceScore,
+	}
+	if n.Title != "" || n.Body != "" {
+		dictionary.Alert = &alert{Title: n.Title, Body: n.Body}
+	}
+	if n.ContentAvailable {
+		dictionary.ContentAvailable = 1
+	}
+	if n.MutableContent {
+		dictionary.MutableContent = 1
+	}
+	body["aps"] = dictionary
+
+	return body
+}
@@ -30,6 +30,61 @@ func IsValidationErr(err error) bool {
 	}, err.Error())
 }
 
+// Error codes of the FCM v1 API
+const (
+	ErrCodeUnregistered          = "UNREGISTERED"
+	ErrCodeInvalidArgument       = "INVALID_ARGUMENT"
+	ErrCodeSenderIDMismatch      = "SENDER_ID_MISMATCH"
+	ErrCodeQuotaExceeded         = "QUOTA_EXCEEDED"
+	ErrCodeUnavailable           = "UNAVAILABLE"
+	ErrCodeInternal              = "INTERNAL"
+	ErrCodeThirdPartyAuthError   = "THIRD_PARTY_AUTH_ERROR"
+	ErrCodeMessageRateExceeded   = "MESSAGE_RATE_EXCEEDED"
+	ErrCodeMismatchedCredential  = "MISMATCHED_CREDENTIAL"
+	ErrCodeInvalidAPNSCredential = "INVALID_APNS_CREDENTIALS"
+	ErrCodeUnknown               = "UNKNOWN"
+)
+
+// ErrCode maps the error returned by the messaging client to the FCM error code
+func ErrCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case messaging.IsUnregistered(err), messaging.IsRegistrationTokenNotRegistered(err):
+		return ErrCodeUnregistered
+	case messaging.IsInvalidArgument(err):
+		return ErrCodeInvalidArgument
+	case messaging.IsSenderIDMismatch(err):
+		return ErrCodeSenderIDMismatch
+	case messaging.IsQuotaExceeded(err):
+		return ErrCodeQuotaExceeded
+	case messaging.IsMessageRateExceeded(err):
+		return ErrCodeMessageRateExceeded
+	case messaging.IsUnavailable(err), messaging.IsServerUnavailable(err):
+		return ErrCodeUnavailable
+	case messaging.IsInternal(err):
+		return ErrCodeInternal
+	case messaging.IsThirdPartyAuthError(err):
+		return ErrCodeThirdPartyAuthError
+	case messaging.IsMismatchedCredential(err):
+		return ErrCodeMismatchedCredential
+	case messaging.IsInvalidAPNSCredentials(err):
+		return ErrCodeInvalidAPNSCredential
+	default:
+		return ErrCodeUnknown
+	}
+}
+
+// IsTokenErr reports if the message failed since the token cannot be used anymore and should be removed
+func IsTokenErr(err error) bool {
+	switch ErrCode(err) {
+	case ErrCodeUnregistered, ErrCodeSenderIDMismatch:
+		return true
+	default:
+		return false
+	}
+}
+
 const (
 	_apnsPriorityHeader    = "apns-priority"
 	ApnsHighestPriority    = "10"
@@ -0,0 +1,85 @@
+package hms
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const _clientCredentials = "client_credentials"
+
+// token returns the cached access token of the app, the token is issued by the client credentials once it expires
+func (h *hms) token(ctx context.Context) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.accessToken != "" && time.Now().Before(h.expiresAt) {
+		return h.accessToken, nil
+	}
+
+	var response tokenResponse
+
+	resp, err := h.client.R().
+		SetContext(ctx).
+		SetFormData(map[string]string{
+			"grant_type":    _clientCredentials,
+			"client_id":     h.options.AppID,
+			"client_secret": h.options.ClientSecret,
+		}).
+		SetSuccessResult(&response).
+		SetErrorResult(&response).
+		Post(h.options.AuthURL)
+	if err != nil {
+		h.logger.Error("hms: cannot issue access token", zap.Error(err))
+		return "", err
+	}
+
+	if resp.IsErrorState() || response.AccessToken == "" {
+		h.logger.Error("hms: access token is not issued", zap.Int("status", resp.StatusCode), zap.String("response", resp.String()))
+		return "", &Error{Code: CodeAuthFailed, Msg: strconv.Itoa(response.Error) + " " + response.ErrorDescription}
+	}
+
+	h.accessToken = response.AccessToken
+	h.expiresAt = time.Now().Add(time.Duration(response.ExpiresIn)*time.Second - _tokenLeeway)
+
+	return h.accessToken, nil
+}
+
+// resetToken drops the cached access token if it's the rejected one
+func (h *hms) resetToken(rejected string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.accessToken == rejected {
+		h.accessToken = ""
+	}
+}
+
+// authorized calls the API with the access token and calls it once again with the new token if it's rejected
+func (h *hms) authorized(ctx context.Context, call func(accessToken string) error) error {
+	if h.options.AppID == "" || h.options.ClientSecret == "" {
+		return ErrNotConfigured
+	}
+
+	accessToken, err := h.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = call(accessToken)
+	if !isAuthErr(err) {
+		return err
+	}
+
+	h.resetToken(accessToken)
+
+	accessToken, errX := h.token(ctx)
+	if errX != nil {
+		return errors.Join(err, errX)
+	}
+
+	return call(accessToken)
+}
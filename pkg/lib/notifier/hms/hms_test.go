@@ -0,0 +1,409 @@
+package hms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"notifications/pkg/lib/observer/logger"
+)
+
+const (
+	_testAppID  = "100200300"
+	_testSecret = "secret"
+)
+
+// fakeHMS is the local Push Kit server, it issues the access tokens t1, t2... and answers the API calls by reply
+type fakeHMS struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	issued    int
+	expiresIn int
+	requests  []fakeRequest
+	reply     func(r fakeRequest) (status int, body any)
+}
+
+type fakeRequest struct {
+	Path        string
+	AccessToken string
+	Body        map[string]any
+}
+
+func newFakeHMS(t *testing.T) *fakeHMS {
+	t.Helper()
+
+	var f = &fakeHMS{expiresIn: 3600}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /oauth2/v3/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != _clientCredentials || r.FormValue("client_id") != _testAppID || r.FormValue("client_secret") != _testSecret {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": 1101, "error_description": "invalid client"})
+			return
+		}
+
+		f.mu.Lock()
+		f.issued++
+		var token = "t" + strconv.Itoa(f.issued)
+		f.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": token, "expires_in": f.expiresIn})
+	})
+	mux.HandleFunc("POST /v1/{app}/{action}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("app") != _testAppID {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var request = fakeRequest{Path: r.URL.Path, AccessToken: r.Header.Get(_authorization)[len("Bearer "):]}
+		if err := json.NewDecoder(r.Body).Decode(&request.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		f.mu.Lock()
+		f.requests = append(f.requests, request)
+		f.mu.Unlock()
+
+		status, body := f.reply(request)
+		writeJSON(w, status, body)
+	})
+
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+func (f *fakeHMS) client() (Sender, TopicManager) {
+	return NewClient(Options{
+		AppID:        _testAppID,
+		ClientSecret: _testSecret,
+		AuthURL:      f.URL + "/oauth2/v3/token",
+		PushURL:      f.URL,
+	}, logger.NewNop())
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func success(fakeRequest) (int, any) {
+	return http.StatusOK, map[string]any{"code": CodeSuccess, "msg": "Success", "requestId": "r1"}
+}
+
+func Test_TokenIsCached(t *testing.T) {
+	var fake = newFakeHMS(t)
+	fake.reply = success
+
+	sender, _ := fake.client()
+
+	for range 3 {
+		if _, err := sender.Send(context.Background(), &Message{Tokens: []string{"a"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if fake.issued != 1 {
+		t.Errorf("issued = %d, want 1", fake.issued)
+	}
+	for _, r := range fake.requests {
+		if r.AccessToken != "t1" {
+			t.Errorf("access token = %q, want t1", r.AccessToken)
+		}
+	}
+}
+
+func Test_NotConfigured(t *testing.T) {
+	var fake = newFakeHMS(t)
+	fake.reply = success
+
+	sender, topics := NewClient(Options{AppID: _testAppID, AuthURL: fake.URL + "/oauth2/v3/token", PushURL: fake.URL}, logger.NewNop())
+
+	if _, err := sender.Send(context.Background(), &Message{Topic: "news"}); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Send err = %v, want %v", err, ErrNotConfigured)
+	}
+	if _, err := topics.Subscribe(context.Background(), []string{"a"}, "news"); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Subscribe err = %v, want %v", err, ErrNotConfigured)
+	}
+	if fake.issued != 0 || len(fake.requests) != 0 {
+		t.Errorf("issued = %d, requests = %d, want none", fake.issued, len(fake.requests))
+	}
+}
+
+func Test_TokenIsIssuedOnceExpired(t *testing.T) {
+	var fake = newFakeHMS(t)
+	fake.reply = success
+	// the token expiring within the leeway is issued again by every call
+	fake.expiresIn = int(_tokenLeeway.Seconds())
+
+	sender, _ := fake.client()
+
+	for range 2 {
+		if _, err := sender.Send(context.Background(), &Message{Tokens: []string{"a"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if fake.issued != 2 {
+		t.Errorf("issued = %d, want 2", fake.issued)
+	}
+}
+
+func Test_TokenIsIssuedAgainOnceRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		reject func() (int, any)
+	}{
+		{
+			name: "auth expired code",
+			reject: func() (int, any) {
+				return http.StatusOK, map[string]any{"code": CodeAuthExpired, "msg": "token expired"}
+			},
+		},
+		{
+			name: "unauthorized status",
+			reject: func() (int, any) {
+				return http.StatusUnauthorized, map[string]any{"code": CodeAuthFailed, "msg": "oauth failed"}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fake = newFakeHMS(t)
+			fake.reply = func(r fakeRequest) (int, any) {
+				if r.AccessToken == "t1" {
+					return tt.reject()
+				}
+				return success(r)
+			}
+
+			sender, _ := fake.client()
+
+			response, err := sender.Send(context.Background(), &Message{Tokens: []string{"a", "b"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.SuccessCount != 2 {
+				t.Errorf("success count = %d, want 2", response.SuccessCount)
+			}
+			if fake.issued != 2 || len(fake.requests) != 2 || fake.requests[1].AccessToken != "t2" {
+				t.Errorf("issued = %d, requests = %+v, want the retry with t2", fake.issued, fake.requests)
+			}
+		})
+	}
+}
+
+func Test_Send(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  *Message
+		reply    func(fakeRequest) (int, any)
+		want     *Response
+		wantCode string
+	}{
+		{
+			name:    "success",
+			message: &Message{Tokens: []string{"a", "b"}, Title: "title", Body: "body", Data: map[string]string{"k": "v"}},
+			reply:   success,
+			want:    &Response{RequestID: "r1", SuccessCount: 2},
+		},
+		{
+			name:    "topic",
+			message: &Message{Topic: "news_ru", Data: map[string]string{"k": "v"}},
+			reply:   success,
+			want:    &Response{RequestID: "r1", SuccessCount: 1},
+		},
+		{
+			name:    "partial success",
+			message: &Message{Tokens: []string{"a", "b", "c"}},
+			reply: func(fakeRequest) (int, any) {
+				return http.StatusOK, map[string]any{
+					"code":      CodePartialSuccess,
+					"msg":       `{"success":1,"failure":2,"illegal_tokens":["b","c"]}`,
+					"requestId": "r2",
+				}
+			},
+			want: &Response{RequestID: "r2", SuccessCount: 1, FailureCount: 2, IllegalTokens: []string{"b", "c"}},
+		},
+		{
+			name:    "all tokens invalid",
+			message: &Message{Tokens: []string{"a"}},
+			reply: func(fakeRequest) (int, any) {
+				return http.StatusBadRequest, map[string]any{"code": CodeAllTokensInvalid, "msg": "all the tokens are invalid"}
+			},
+			wantCode: CodeAllTokensInvalid,
+		},
+		{
+			name:    "no permission",
+			message: &Message{Tokens: []string{"a"}},
+			reply: func(fakeRequest) (int, any) {
+				return http.StatusForbidden, map[string]any{"code": CodeNoPermission, "msg": "no permission"}
+			},
+			wantCode: CodeNoPermission,
+		},
+		{
+			name:    "unexpected status",
+			message: &Message{Tokens: []string{"a"}},
+			reply: func(fakeRequest) (int, any) {
+				return http.StatusBadGateway, "bad gateway"
+			},
+			wantCode: CodeUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fake = newFakeHMS(t)
+			fake.reply = tt.reply
+
+			sender, _ := fake.client()
+
+			response, err := sender.Send(context.Background(), tt.message)
+			if code := ErrCode(err); code != tt.wantCode {
+				t.Fatalf("err = %v, want code %q", err, tt.wantCode)
+			}
+			if !reflect.DeepEqual(response, tt.want) {
+				t.Errorf("response = %+v, want %+v", response, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SendRequest(t *testing.T) {
+	var fake = newFakeHMS(t)
+	fake.reply = success
+
+	sender, _ := fake.client()
+
+	var message = &Message{Tokens: []string{"a"}, Data: map[string]string{"k": "v"}}
+	NotificationMSG(message, map[string]string{"title": "hello", "message": "world"}, UrgencyHigh)
+
+	if _, err := sender.SendDryRun(context.Background(), message); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		body         = fake.requests[0].Body
+		msg          = body["message"].(map[string]any)
+		android      = msg["android"].(map[string]any)
+		notification = android["notification"].(map[string]any)
+	)
+
+	if fake.requests[0].Path != "/v1/"+_testAppID+"/messages:send" {
+		t.Errorf("path = %s", fake.requests[0].Path)
+	}
+	if body["validate_only"] != true {
+		t.Error("validate_only is not set by the dry run")
+	}
+	if msg["data"] != `{"message":"world","title":"hello"}` {
+		t.Errorf("data = %v", msg["data"])
+	}
+	if android["urgency"] != UrgencyHigh || notification["title"] != "hello" || notification["body"] != "world" {
+		t.Errorf("android = %v", android)
+	}
+}
+
+func Test_Topic(t *testing.T) {
+	tests := []struct {
+		name     string
+		manage   func(TopicManager) (*TopicResponse, error)
+		path     string
+		reply    func(fakeRequest) (int, any)
+		want     *TopicResponse
+		wantCode string
+	}{
+		{
+			name: "subscribe",
+			manage: func(m TopicManager) (*TopicResponse, error) {
+				return m.Subscribe(context.Background(), []string{"a", "b"}, "news_ru")
+			},
+			path: "/v1/" + _testAppID + "/topic:subscribe",
+			reply: func(fakeRequest) (int, any) {
+				return http.StatusOK, map[string]any{
+					"code":         CodeSuccess,
+					"successCount": 1,
+					"failureCount": 1,
+					"errors":       []map[string]any{{"index": 1, "reason": "invalid token"}},
+				}
+			},
+			want: &TopicResponse{SuccessCount: 1, FailureCount: 1, Errors: []TopicError{{Index: 1, Reason: "invalid token"}}},
+		},
+		{
+			name: "unsubscribe",
+			manage: func(m TopicManager) (*TopicResponse, error) {
+				return m.Unsubscribe(context.Background(), []string{"a", "b"}, "news_ru")
+			},
+			path: "/v1/" + _testAppID + "/topic:unsubscribe",
+			reply: func(fakeRequest) (int, any) {
+				return http.StatusOK, map[string]any{"code": CodeSuccess, "successCount": 2}
+			},
+			want: &TopicResponse{SuccessCount: 2},
+		},
+		{
+			name: "failure",
+			manage: func(m TopicManager) (*TopicResponse, error) {
+				return m.Subscribe(context.Background(), []string{"a", "b"}, "news_ru")
+			},
+			path: "/v1/" + _testAppID + "/topic:subscribe",
+			reply: func(fakeRequest) (int, any) {
+				return http.StatusOK, map[string]any{"code": CodeInvalidParameter, "msg": "invalid topic"}
+			},
+			wantCode: CodeInvalidParameter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fake = newFakeHMS(t)
+			fake.reply = tt.reply
+
+			_, manager := fake.client()
+
+			response, err := tt.manage(manager)
+			if code := ErrCode(err); code != tt.wantCode {
+				t.Fatalf("err = %v, want code %q", err, tt.wantCode)
+			}
+			if !reflect.DeepEqual(response, tt.want) {
+				t.Errorf("response = %+v, want %+v", response, tt.want)
+			}
+
+			var request = fake.requests[0]
+			if request.Path != tt.path || request.Body["topic"] != "news_ru" || len(request.Body["tokenArray"].([]any)) != 2 {
+				t.Errorf("request = %+v", request)
+			}
+		})
+	}
+}
+
+func Test_IsValidationErr(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: &Error{Code: CodeAllTokensInvalid}, want: true},
+		{err: errors.Join(errors.New("send"), &Error{Code: CodeAllTokensInvalid}), want: true},
+		{err: &Error{Code: CodeNoPermission}, want: false},
+		{err: &Error{Code: CodeAuthFailed}, want: false},
+		{err: &Error{Code: CodeRateExceeded}, want: false},
+		{err: &Error{Code: CodeInternal}, want: false},
+		{err: errors.New("connection refused"), want: false},
+		{err: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidationErr(tt.err); got != tt.want {
+			t.Errorf("IsValidationErr(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
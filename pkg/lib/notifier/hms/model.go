@@ -0,0 +1,172 @@
+package hms
+
+import (
+	"errors"
+	"slices"
+)
+
+// Message is the push to the tokens or the topic, the message without title and body is delivered to the app only
+type Message struct {
+	Tokens []string
+	Topic  string
+	Data   map[string]string
+	Title  string
+	Body   string
+	// Urgency is UrgencyHigh or UrgencyNormal, UrgencyNormal if it's empty
+	Urgency string
+}
+
+const (
+	UrgencyHigh   = "HIGH"
+	UrgencyNormal = "NORMAL"
+)
+
+const (
+	_titleKey   = "title"
+	_messageKey = "message"
+)
+
+// NotificationMSG sets the data of the message and the notification of its title and message keys
+func NotificationMSG(msg *Message, data map[string]string, urgency string) {
+	msg.Data = data
+	msg.Title = data[_titleKey]
+	msg.Body = data[_messageKey]
+	msg.Urgency = urgency
+}
+
+// Response is the result of the send, the tokens rejected by HMS are in IllegalTokens
+type Response struct {
+	RequestID     string
+	SuccessCount  int
+	FailureCount  int
+	IllegalTokens []string
+}
+
+// TopicResponse is the result of the topic subscription, the rejected tokens are in Errors by their index
+type TopicResponse struct {
+	SuccessCount int          `json:"successCount"`
+	FailureCount int          `json:"failureCount"`
+	Errors       []TopicError `json:"errors"`
+}
+
+type TopicError struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
+}
+
+// Error is the error code of the Push Kit API
+type Error struct {
+	Code      string
+	Msg       string
+	RequestID string
+}
+
+func (e *Error) Error() string {
+	return "hms: " + e.Code + " " + e.Msg
+}
+
+// Result codes of the Push Kit API
+const (
+	CodeSuccess            = "80000000"
+	CodePartialSuccess     = "80100000"
+	CodeInvalidParameter   = "80100001"
+	CodeTooManyTokens      = "80100002"
+	CodeInvalidMessage     = "80100003"
+	CodeInvalidTTL         = "80100004"
+	CodeAuthFailed         = "80200001"
+	CodeAuthExpired        = "80200003"
+	CodeNoPermission       = "80300002"
+	CodeAllTokensInvalid   = "80300007"
+	CodeMessageTooLarge    = "80300008"
+	CodeTooManyTokensLimit = "80300010"
+	CodeRateExceeded       = "80300013"
+	CodeInternal           = "81000001"
+	CodeUnknown            = "UNKNOWN"
+)
+
+// ErrNotConfigured is returned by the client without the credentials of the app, nothing is sent to Push Kit
+var ErrNotConfigured = errors.New("hms: app is not configured")
+
+// ErrCode returns the Push Kit code of the error, CodeUnknown if the error is not of the API
+func ErrCode(err error) string {
+	var e *Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &e):
+		return e.Code
+	default:
+		return CodeUnknown
+	}
+}
+
+// IsValidationErr reports if the message failed since the tokens cannot be used anymore and should be removed,
+// the errors of the app like CodeNoPermission are the failures of the provider and don't say anything about the tokens
+func IsValidationErr(err error) bool {
+	return ErrCode(err) == CodeAllTokensInvalid
+}
+
+// isAuthErr reports if the access token is rejected and must be issued again
+func isAuthErr(err error) bool {
+	return slices.Contains([]string{CodeAuthFailed, CodeAuthExpired}, ErrCode(err))
+}
+
+type sendRequest struct {
+	ValidateOnly bool        `json:"validate_only"`
+	Message      wireMessage `json:"message"`
+}
+
+type wireMessage struct {
+	Data    string      `json:"data,omitempty"`
+	Android wireAndroid `json:"android"`
+	Token   []string    `json:"token,omitempty"`
+	Topic   string      `json:"topic,omitempty"`
+}
+
+type wireAndroid struct {
+	Urgency      string            `json:"urgency"`
+	Notification *wireNotification `json:"notification,omitempty"`
+}
+
+type wireNotification struct {
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	ClickAction wireClickAction `json:"click_action"`
+}
+
+// wireClickAction of the type 3 opens the app
+type wireClickAction struct {
+	Type int `json:"type"`
+}
+
+const _openApp = 3
+
+type apiResponse struct {
+	Code      string `json:"code"`
+	Msg       string `json:"msg"`
+	RequestID string `json:"requestId"`
+}
+
+// partialResult is the msg of the partial success, a JSON of the counts and the rejected tokens
+type partialResult struct {
+	Success       int      `json:"success"`
+	Failure       int      `json:"failure"`
+	IllegalTokens []string `json:"illegal_tokens"`
+}
+
+type topicRequest struct {
+	Topic      string   `json:"topic"`
+	TokenArray []string `json:"tokenArray"`
+}
+
+type topicResponse struct {
+	apiResponse
+	TopicResponse
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            int    `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
@@ -0,0 +1,95 @@
+package hms
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"go.uber.org/fx"
+
+	"notifications/pkg/lib/config"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Sender interface {
+	// Send sends the message to the tokens or the topic, up to 1000 tokens at once
+	Send(ctx context.Context, message *Message) (*Response, error)
+	SendDryRun(ctx context.Context, message *Message) (*Response, error)
+}
+
+type TopicManager interface {
+	Subscribe(ctx context.Context, tokens []string, topic string) (*TopicResponse, error)
+	Unsubscribe(ctx context.Context, tokens []string, topic string) (*TopicResponse, error)
+}
+
+// Options of the Push Kit client, the URLs can point to a local fake server
+type Options struct {
+	AppID        string
+	ClientSecret string
+	AuthURL      string
+	PushURL      string
+	Timeout      time.Duration
+}
+
+const (
+	_authURL = "https://oauth-login.cloud.huawei.com/oauth2/v3/token"
+	_pushURL = "https://push-api.cloud.huawei.com"
+	_timeout = 10 * time.Second
+	// _tokenLeeway is the time the access token is issued again before it expires
+	_tokenLeeway = time.Minute
+)
+
+type Params struct {
+	fx.In
+
+	Config config.Config
+	Logger logger.Logger
+}
+
+type hms struct {
+	logger  logger.Logger
+	options Options
+	client  *req.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func New(p Params) (Sender, TopicManager) {
+	return NewClient(Options{
+		AppID:        p.Config.GetString("hms.appID"),
+		ClientSecret: p.Config.GetString("hms.clientSecret"),
+		AuthURL:      p.Config.GetString("hms.authURL"),
+		PushURL:      p.Config.GetString("hms.pushURL"),
+	}, p.Logger)
+}
+
+// NewClient creates the Push Kit client, the empty URLs are the ones of the HMS cloud. The client without
+// the credentials of the app returns ErrNotConfigured
+func NewClient(options Options, logger logger.Logger) (Sender, TopicManager) {
+	if options.AuthURL == "" {
+		options.AuthURL = _authURL
+	}
+	if options.PushURL == "" {
+		options.PushURL = _pushURL
+	}
+	if options.Timeout == 0 {
+		options.Timeout = _timeout
+	}
+
+	var h = &hms{
+		logger:  logger,
+		options: options,
+		client:  req.C().SetTimeout(options.Timeout),
+	}
+
+	if options.AppID == "" || options.ClientSecret == "" {
+		logger.Warning("hms: app credentials are not configured, the provider is disabled")
+	}
+
+	return h, h
+}
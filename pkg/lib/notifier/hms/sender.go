@@ -0,0 +1,121 @@
+package hms
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+)
+
+const _authorization = "Authorization"
+
+func (h *hms) Send(ctx context.Context, message *Message) (*Response, error) {
+	return h.send(ctx, message, false)
+}
+
+func (h *hms) SendDryRun(ctx context.Context, message *Message) (*Response, error) {
+	return h.send(ctx, message, true)
+}
+
+func (h *hms) send(ctx context.Context, message *Message, validateOnly bool) (*Response, error) {
+	request, err := buildRequest(message, validateOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		url      = h.options.PushURL + "/v1/" + h.options.AppID + "/messages:send"
+		response apiResponse
+	)
+
+	err = h.authorized(ctx, func(accessToken string) error {
+		response = apiResponse{}
+		return h.post(ctx, url, accessToken, request, &response, &response)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch response.Code {
+	case CodeSuccess:
+		return &Response{RequestID: response.RequestID, SuccessCount: max(len(message.Tokens), 1)}, nil
+	case CodePartialSuccess:
+		var result partialResult
+		if err = sonic.UnmarshalString(response.Msg, &result); err != nil {
+			h.logger.Warning("hms: cannot parse partial result", zap.Error(err), zap.String("msg", response.Msg))
+		}
+		return &Response{
+			RequestID:     response.RequestID,
+			SuccessCount:  result.Success,
+			FailureCount:  result.Failure,
+			IllegalTokens: result.IllegalTokens,
+		}, nil
+	default:
+		return nil, &Error{Code: response.Code, Msg: response.Msg, RequestID: response.RequestID}
+	}
+}
+
+// post calls the API with the access token and decodes the result, response is the code of the result.
+// The rejected access token is returned as CodeAuthExpired
+func (h *hms) post(ctx context.Context, url, accessToken string, body, result any, response *apiResponse) error {
+	resp, err := h.client.R().
+		SetContext(ctx).
+		SetHeader(_authorization, "Bearer "+accessToken).
+		SetBody(body).
+		SetSuccessResult(result).
+		SetErrorResult(result).
+		Post(url)
+	if err != nil {
+		h.logger.Error("hms: cannot send request", zap.Error(err), zap.String("url", url))
+		return err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return &Error{Code: CodeAuthExpired, Msg: response.Msg, RequestID: response.RequestID}
+	}
+	if resp.IsErrorState() && response.Code == "" {
+		h.logger.Error("hms: incorrect response status", zap.Int("status", resp.StatusCode), zap.String("response", resp.String()))
+		return &Error{Code: CodeUnknown, Msg: resp.String()}
+	}
+	if isAuthErr(&Error{Code: response.Code}) {
+		return &Error{Code: response.Code, Msg: response.Msg, RequestID: response.RequestID}
+	}
+
+	return nil
+}
+
+func buildRequest(message *Message, validateOnly bool) (*sendRequest, error) {
+	var request = &sendRequest{
+		ValidateOnly: validateOnly,
+		Message: wireMessage{
+			Token: message.Tokens,
+			Topic: message.Topic,
+			Android: wireAndroid{
+				Urgency: message.Urgency,
+			},
+		},
+	}
+
+	if request.Message.Android.Urgency == "" {
+		request.Message.Android.Urgency = UrgencyNormal
+	}
+
+	if len(message.Data) != 0 {
+		data, err := sonic.MarshalString(message.Data)
+		if err != nil {
+			return nil, err
+		}
+		request.Message.Data = data
+	}
+
+	if message.Title != "" || message.Body != "" {
+		request.Message.Android.Notification = &wireNotification{
+			Title:       message.Title,
+			Body:        message.Body,
+			ClickAction: wireClickAction{Type: _openApp},
+		}
+	}
+
+	return request, nil
+}
@@ -0,0 +1,33 @@
+package hms
+
+import "context"
+
+func (h *hms) Subscribe(ctx context.Context, tokens []string, topic string) (*TopicResponse, error) {
+	return h.manageTopic(ctx, "/topic:subscribe", tokens, topic)
+}
+
+func (h *hms) Unsubscribe(ctx context.Context, tokens []string, topic string) (*TopicResponse, error) {
+	return h.manageTopic(ctx, "/topic:unsubscribe", tokens, topic)
+}
+
+func (h *hms) manageTopic(ctx context.Context, route string, tokens []string, topic string) (*TopicResponse, error) {
+	var (
+		url      = h.options.PushURL + "/v1/" + h.options.AppID + route
+		request  = topicRequest{Topic: topic, TokenArray: tokens}
+		response topicResponse
+	)
+
+	err := h.authorized(ctx, func(accessToken string) error {
+		response = topicResponse{}
+		return h.post(ctx, url, accessToken, request, &response, &response.apiResponse)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Code != CodeSuccess {
+		return nil, &Error{Code: response.Code, Msg: response.Msg, RequestID: response.RequestID}
+	}
+
+	return &response.TopicResponse, nil
+}
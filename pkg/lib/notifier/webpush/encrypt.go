@@ -0,0 +1,108 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+)
+
+const (
+	_recordSize = 4096
+	_saltSize   = 16
+	_tagSize    = 16
+	_keySize    = 65
+	_headerSize = _saltSize + 4 + 1 + _keySize
+	// _maxPayload is the payload which fits the single record of the body of 4096 bytes the push services accept
+	_maxPayload = _recordSize - _headerSize - _tagSize - 1
+	// _lastRecord is the padding delimiter of the last record
+	_lastRecord = 0x02
+
+	_keyInfo   = "WebPush: info\x00"
+	_cekInfo   = "Content-Encoding: aes128gcm\x00"
+	_nonceInfo = "Content-Encoding: nonce\x00"
+)
+
+// encrypt encrypts the payload to the keys of the subscription by RFC 8291 into the single aes128gcm record of RFC 8188,
+// the key of the application server is ephemeral and sent in the header of the record
+func encrypt(subscription *Subscription, payload []byte) ([]byte, error) {
+	asKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var salt = make([]byte, _saltSize)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return seal(subscription, payload, asKey, salt)
+}
+
+// seal encrypts the payload by the key of the application server and the salt, they're random for every message
+func seal(subscription *Subscription, payload []byte, asKey *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(payload) > _maxPayload {
+		return nil, ErrPayloadTooLarge
+	}
+
+	uaPublic, err := decodeKey(subscription.P256dh)
+	if err != nil {
+		return nil, err
+	}
+	authSecret, err := decodeKey(subscription.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	ecdhSecret, err := asKey.ECDH(uaKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var asPublic = asKey.PublicKey().Bytes()
+
+	ikm, err := hkdf.Key(sha256.New, ecdhSecret, authSecret, _keyInfo+string(uaPublic)+string(asPublic), 32)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, _cekInfo, 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, _nonceInfo, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// header is salt || rs || idlen || keyid, the keyid is the public key of the application server
+	var body = make([]byte, _headerSize, _headerSize+len(payload)+1+_tagSize)
+	copy(body, salt)
+	binary.BigEndian.PutUint32(body[_saltSize:], _recordSize)
+	body[_saltSize+4] = _keySize
+	copy(body[_saltSize+5:], asPublic)
+
+	return gcm.Seal(body, nonce, append(payload[:len(payload):len(payload)], _lastRecord), nil), nil
+}
+
+// decodeKey decodes the key of the subscription, the browsers give it in base64url with or without the padding
+func decodeKey(key string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(key, "="))
+}
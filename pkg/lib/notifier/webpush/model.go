@@ -0,0 +1,96 @@
+package webpush
+
+import (
+	"errors"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Subscription is the push subscription of the browser, the keys are base64url encoded as the browser gives them
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Message is the payload encrypted to the subscription, the push service keeps it for TTL if the browser is offline
+type Message struct {
+	Payload []byte
+	TTL     time.Duration
+	// Urgency is UrgencyHigh, UrgencyNormal or UrgencyLow, UrgencyNormal if it's empty
+	Urgency string
+	// Topic replaces the pending message of the same topic, up to 32 characters of base64url alphabet
+	Topic string
+}
+
+const (
+	UrgencyHigh   = "high"
+	UrgencyNormal = "normal"
+	UrgencyLow    = "low"
+)
+
+// Response is the result of the push, Location is the URL of the message created by the push service
+type Response struct {
+	Location string
+}
+
+// Error is the rejection of the push by the push service
+type Error struct {
+	Status int
+	Body   string
+}
+
+func (e *Error) Error() string {
+	if e.Body == "" {
+		return "webpush: " + strconv.Itoa(e.Status) + " " + http.StatusText(e.Status)
+	}
+	return "webpush: " + strconv.Itoa(e.Status) + " " + e.Body
+}
+
+// ErrPayloadTooLarge is returned for the payload which doesn't fit the single record of the encrypted content
+var ErrPayloadTooLarge = errors.New("webpush: payload is too large")
+
+// ErrNotConfigured is returned by the sender without the VAPID keys
+var ErrNotConfigured = errors.New("webpush: VAPID keys are not configured")
+
+// Status returns the HTTP status of the rejected push, 0 if the error is not of the push service
+func Status(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Status
+	}
+	return 0
+}
+
+// IsExpiredErr reports if the push failed since the subscription expired or was unsubscribed and should be removed
+func IsExpiredErr(err error) bool {
+	return slices.Contains([]int{http.StatusNotFound, http.StatusGone}, Status(err))
+}
+
+// _internalSuffixes are the domains of the local networks, the push services are never there
+var _internalSuffixes = []string{".localhost", ".local", ".localdomain", ".internal", ".intranet", ".lan", ".home", ".corp"}
+
+// IsValidEndpoint reports if the endpoint of the subscription is the https URL of the public host, the endpoint
+// is given by the browser, so the push is never posted to the hosts of the local network
+func IsValidEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.User != nil || u.Opaque != "" {
+		return false
+	}
+	if port := u.Port(); port != "" && port != "443" {
+		return false
+	}
+
+	var host = strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	// the push services are addressed by the domain, the IP is the one of the local network or the bypass of the check
+	if _, err = netip.ParseAddr(host); err == nil || !strings.Contains(host, ".") {
+		return false
+	}
+
+	return !slices.ContainsFunc(_internalSuffixes, func(suffix string) bool { return strings.HasSuffix(host, suffix) })
+}
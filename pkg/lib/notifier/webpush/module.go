@@ -0,0 +1,128 @@
+package webpush
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"notifications/pkg/lib/config"
+	"notifications/pkg/lib/observer/logger"
+)
+
+var Module = fx.Provide(New)
+
+type Sender interface {
+	// Send encrypts the message to the subscription and posts it to the endpoint of the push service
+	Send(ctx context.Context, subscription *Subscription, message *Message) (*Response, error)
+}
+
+// Options of the Web Push client, the keys are base64url encoded and the public one is the applicationServerKey
+// the browser subscribes with, the subject is the mailto or https contact of the sender
+type Options struct {
+	PublicKey  string
+	PrivateKey string
+	Subject    string
+	Timeout    time.Duration
+}
+
+const (
+	_timeout = 10 * time.Second
+	// _vapidTTL is the time the VAPID token of the push service is used, the push services accept it up to a day
+	_vapidTTL = 12 * time.Hour
+)
+
+type Params struct {
+	fx.In
+
+	Config config.Config
+	Logger logger.Logger
+}
+
+type webPush struct {
+	logger  logger.Logger
+	options Options
+	client  *req.Client
+	key     *ecdsa.PrivateKey
+
+	mu     sync.Mutex
+	tokens map[string]vapidToken
+}
+
+type vapidToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+func New(p Params) Sender {
+	return NewClient(Options{
+		PublicKey:  p.Config.GetString("webPush.publicKey"),
+		PrivateKey: p.Config.GetString("webPush.privateKey"),
+		Subject:    p.Config.GetString("webPush.subject"),
+	}, p.Logger)
+}
+
+// NewClient creates the Web Push client, the sender without the VAPID keys returns ErrNotConfigured
+func NewClient(options Options, logger logger.Logger) Sender {
+	if options.Timeout == 0 {
+		options.Timeout = _timeout
+	}
+
+	var w = &webPush{
+		logger:  logger,
+		options: options,
+		client:  req.C().SetTimeout(options.Timeout),
+		tokens:  make(map[string]vapidToken),
+	}
+
+	if options.PrivateKey == "" {
+		logger.Warning("webpush: VAPID keys are not configured, the channel is disabled")
+		return w
+	}
+
+	key, err := parseKey(options.PublicKey, options.PrivateKey)
+	if err != nil {
+		logger.Error("webpush: cannot parse VAPID keys", zap.Error(err))
+		return w
+	}
+	w.key = key
+
+	return w
+}
+
+// parseKey builds the P-256 key of the VAPID keys, the public key is the uncompressed point and the private key is the scalar
+func parseKey(publicKey, privateKey string) (*ecdsa.PrivateKey, error) {
+	private, err := base64.RawURLEncoding.DecodeString(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdh.P256().NewPrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	// the uncompressed point is 0x04 || X || Y
+	public := key.PublicKey().Bytes()
+	if base64.RawURLEncoding.EncodeToString(public) != publicKey {
+		return nil, errors.New("webpush: public key doesn't match the private key")
+	}
+
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:]),
+		},
+		D: new(big.Int).SetBytes(private),
+	}, nil
+}
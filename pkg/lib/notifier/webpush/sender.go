@@ -0,0 +1,75 @@
+package webpush
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	_authorization   = "Authorization"
+	_contentEncoding = "Content-Encoding"
+	_contentType     = "Content-Type"
+	_ttl             = "TTL"
+	_urgency         = "Urgency"
+	_topic           = "Topic"
+	_location        = "Location"
+
+	_aes128gcm   = "aes128gcm"
+	_octetStream = "application/octet-stream"
+	// _defaultTTL is the time the push service keeps the message of the offline browser
+	_defaultTTL = 24 * time.Hour
+)
+
+func (w *webPush) Send(ctx context.Context, subscription *Subscription, message *Message) (*Response, error) {
+	if w.key == nil {
+		return nil, ErrNotConfigured
+	}
+
+	body, err := encrypt(subscription, message.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	authorization, err := w.authorization(subscription.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ttl     = message.TTL
+		urgency = message.Urgency
+	)
+	if ttl == 0 {
+		ttl = _defaultTTL
+	}
+	if urgency == "" {
+		urgency = UrgencyNormal
+	}
+
+	request := w.client.R().
+		SetContext(ctx).
+		SetHeader(_authorization, authorization).
+		SetHeader(_contentEncoding, _aes128gcm).
+		SetHeader(_contentType, _octetStream).
+		SetHeader(_ttl, strconv.Itoa(int(ttl.Seconds()))).
+		SetHeader(_urgency, urgency).
+		SetBodyBytes(body)
+	if message.Topic != "" {
+		request.SetHeader(_topic, message.Topic)
+	}
+
+	resp, err := request.Post(subscription.Endpoint)
+	if err != nil {
+		w.logger.Error("webpush: cannot send request", zap.Error(err))
+		return nil, err
+	}
+
+	if resp.IsErrorState() {
+		return nil, &Error{Status: resp.StatusCode, Body: resp.String()}
+	}
+
+	return &Response{Location: resp.Header.Get(_location)}, nil
+}
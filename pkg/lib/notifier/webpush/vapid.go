@@ -0,0 +1,44 @@
+package webpush
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+)
+
+// authorization returns the VAPID authorization of the push service of the endpoint, the token of the push service
+// is cached and signed again once it expires
+func (w *webPush) authorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	var audience = u.Scheme + "://" + u.Host
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if token, ok := w.tokens[audience]; ok && time.Now().Before(token.expiresAt) {
+		return token.value, nil
+	}
+
+	var expiresAt = time.Now().Add(_vapidTTL)
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": audience,
+		"exp": expiresAt.Unix(),
+		"sub": w.options.Subject,
+	}).SignedString(w.key)
+	if err != nil {
+		w.logger.Error("webpush: cannot sign VAPID token", zap.Error(err), zap.String("audience", audience))
+		return "", err
+	}
+
+	// the token is used a minute less than it's valid not to be expired on the way
+	var value = "vapid t=" + signed + ", k=" + w.options.PublicKey
+	w.tokens[audience] = vapidToken{value: value, expiresAt: expiresAt.Add(-time.Minute)}
+
+	return value, nil
+}
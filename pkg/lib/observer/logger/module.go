@@ -98,6 +98,11 @@ func New(p Params) Logger {
 	return &logger{log: log.Sugar()}
 }
 
+// NewNop returns the logger which writes nothing, it's used where the logs are not needed like the tests
+func NewNop() Logger {
+	return &logger{log: zap.NewNop().Sugar()}
+}
+
 func getEncoder(cfg config.Config) zapcore.Encoder {
 	var encoderCfg = zapcore.EncoderConfig{
 		MessageKey:   _message,
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var ErrNoOccurrence = errors.New("cron expression has no occurrence")
+
+// Parse checks the standard cron expression with 5 fields or the descriptor like `@weekly`,
+// the same as the ones of Cron, the timezone is given by the caller so `TZ=` is not allowed
+func Parse(cronExpression string) (cron.Schedule, error) {
+	if strings.HasPrefix(cronExpression, "TZ=") || strings.HasPrefix(cronExpression, "CRON_TZ=") {
+		return nil, errors.New("timezone cannot be set in the cron expression")
+	}
+	return cron.ParseStandard(cronExpression)
+}
+
+// Next returns the first occurrence of the cron expression after the time in the location
+func Next(cronExpression string, after time.Time, loc *time.Location) (time.Time, error) {
+	schedule, err := Parse(cronExpression)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := schedule.Next(after.In(loc))
+	if next.IsZero() {
+		return time.Time{}, ErrNoOccurrence
+	}
+
+	return next, nil
+}
@@ -1,6 +1,7 @@
 package ratelimiter
 
 import (
+	"context"
 	"time"
 
 	"go.uber.org/fx"
@@ -12,6 +13,12 @@ var Module = fx.Provide(New)
 
 type Limiter interface {
 	NewSlidingWindowLimiter(key string, rate float64, window time.Duration) *SlidingWindowRateLimiter
+	quota
+}
+
+type quota interface {
+	Allow(ctx context.Context, key string, policy Policy) (Result, error)
+	Usage(ctx context.Context, key string) (Usage, error)
 }
 
 type Params struct {
@@ -0,0 +1,218 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Exceeded limits
+const (
+	ExceededNone    = ""
+	ExceededRate    = "rate"
+	ExceededDaily   = "daily"
+	ExceededMonthly = "monthly"
+)
+
+const (
+	_dailyLayout   = "20060102"
+	_monthlyLayout = "200601"
+)
+
+// _script checks the sliding window (KEYS[1]) and optional daily (KEYS[2]) and monthly (KEYS[3]) counters.
+// The request is recorded only if all the limits allow it, and the whole check is atomic.
+// ARGV: now ms, window ms, rate, member, daily quota, daily ttl ms, monthly quota, monthly ttl ms.
+// Returns: allowed, exceeded limit (0 - none, 1 - rate, 2 - daily, 3 - monthly), retry after ms, rate count, daily used, monthly used.
+var _script = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local rate = tonumber(ARGV[3])
+local withQuota = #KEYS >= 3
+
+local daily, monthly = 0, 0
+if withQuota then
+	daily = tonumber(redis.call('GET', KEYS[2]) or 0)
+	monthly = tonumber(redis.call('GET', KEYS[3]) or 0)
+end
+
+local count = 0
+if rate > 0 then
+	redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+	count = redis.call('ZCARD', KEYS[1])
+	if count >= rate then
+		local retry = window
+		local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+		if oldest[2] then
+			retry = tonumber(oldest[2]) + window - now
+		end
+		return {0, 1, retry, count, daily, monthly}
+	end
+end
+
+if withQuota then
+	local dailyQuota = tonumber(ARGV[5])
+	local monthlyQuota = tonumber(ARGV[7])
+	if dailyQuota > 0 and daily >= dailyQuota then
+		return {0, 2, tonumber(ARGV[6]), count, daily, monthly}
+	end
+	if monthlyQuota > 0 and monthly >= monthlyQuota then
+		return {0, 3, tonumber(ARGV[8]), count, daily, monthly}
+	end
+end
+
+if rate > 0 then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	count = count + 1
+end
+
+if withQuota then
+	daily = redis.call('INCR', KEYS[2])
+	if daily == 1 then
+		redis.call('PEXPIRE', KEYS[2], ARGV[6])
+	end
+	monthly = redis.call('INCR', KEYS[3])
+	if monthly == 1 then
+		redis.call('PEXPIRE', KEYS[3], ARGV[8])
+	end
+end
+
+return {1, 0, 0, count, daily, monthly}
+`)
+
+type Policy struct {
+	Window time.Duration
+	// Rate is the number of requests allowed within the Window, 0 means unlimited
+	Rate int64
+	// DailyQuota and MonthlyQuota are counted in UTC calendar periods, 0 means unlimited
+	DailyQuota   int64
+	MonthlyQuota int64
+}
+
+type Result struct {
+	Exceeded   string
+	RetryAfter time.Duration
+	// Remaining values are -1 if the limit is unlimited
+	RateRemaining    int64
+	DailyRemaining   int64
+	MonthlyRemaining int64
+	Allowed          bool
+}
+
+type Usage struct {
+	DailyResetAt   time.Time
+	MonthlyResetAt time.Time
+	Daily          int64
+	Monthly        int64
+}
+
+func (l *limiter) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	var now = time.Now().UTC()
+	return l.run(ctx, quotaKeys(key, now), policy, now)
+}
+
+func (l *limiter) Usage(ctx context.Context, key string) (Usage, error) {
+	var (
+		now   = time.Now().UTC()
+		keys  = quotaKeys(key, now)
+		usage = Usage{
+			DailyResetAt:   dayEnd(now),
+			MonthlyResetAt: monthEnd(now),
+		}
+	)
+
+	err := l.cache.Get(ctx, keys[1], &usage.Daily)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return Usage{}, err
+	}
+
+	err = l.cache.Get(ctx, keys[2], &usage.Monthly)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return Usage{}, err
+	}
+
+	return usage, nil
+}
+
+func (l *limiter) run(ctx context.Context, keys []string, policy Policy, now time.Time) (Result, error) {
+	var (
+		member = strconv.FormatInt(now.UnixNano(), 10) + "-" + strconv.FormatUint(rand.Uint64(), 36)
+		args   = []any{
+			now.UnixMilli(),
+			policy.Window.Milliseconds(),
+			policy.Rate,
+			member,
+			policy.DailyQuota,
+			dayEnd(now).Sub(now).Milliseconds(),
+			policy.MonthlyQuota,
+			monthEnd(now).Sub(now).Milliseconds(),
+		}
+	)
+
+	raw, err := l.cache.RunScript(ctx, _script, keys, args...)
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := raw.([]any)
+	if !ok || len(values) != 6 {
+		return Result{}, errors.New("ratelimiter: unexpected script result")
+	}
+
+	var nums = make([]int64, 0, len(values))
+	for _, v := range values {
+		n, ok := v.(int64)
+		if !ok {
+			return Result{}, errors.New("ratelimiter: unexpected script value")
+		}
+		nums = append(nums, n)
+	}
+
+	var result = Result{
+		Allowed:          nums[0] == 1,
+		RetryAfter:       time.Duration(nums[2]) * time.Millisecond,
+		RateRemaining:    remaining(policy.Rate, nums[3]),
+		DailyRemaining:   remaining(policy.DailyQuota, nums[4]),
+		MonthlyRemaining: remaining(policy.MonthlyQuota, nums[5]),
+	}
+
+	switch nums[1] {
+	case 1:
+		result.Exceeded = ExceededRate
+	case 2:
+		result.Exceeded = ExceededDaily
+	case 3:
+		result.Exceeded = ExceededMonthly
+	}
+
+	return result, nil
+}
+
+// quotaKeys uses the same hash tag for all the keys, so they can be used in one script in cluster mode
+func quotaKeys(key string, now time.Time) []string {
+	var tag = "{" + key + "}"
+	return []string{
+		":rate-limit:" + tag,
+		":quota:" + tag + ":daily:" + now.Format(_dailyLayout),
+		":quota:" + tag + ":monthly:" + now.Format(_monthlyLayout),
+	}
+}
+
+func remaining(limit, used int64) int64 {
+	if limit <= 0 {
+		return -1
+	}
+	return max(limit-used, 0)
+}
+
+func dayEnd(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthEnd(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
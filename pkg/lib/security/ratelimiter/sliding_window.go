@@ -2,10 +2,7 @@ package ratelimiter
 
 import (
 	"context"
-	"fmt"
 	"time"
-
-	"notifications/pkg/util/strset"
 )
 
 type SlidingWindowRateLimiter struct {
@@ -23,70 +20,21 @@ func (l *limiter) NewSlidingWindowLimiter(key string, rate float64, window time.
 	}
 }
 
+// IsAllowed removes expired entries, counts and records the request in a single lua script,
+// so concurrent requests cannot pass the limit between separate redis calls
 func (sw *SlidingWindowRateLimiter) IsAllowed() bool {
-	err := sw.slidingWindow.increment()
-	if err != nil {
-		return false
-	}
-
-	err = sw.slidingWindow.removeExpired()
-	if err != nil {
-		return false
-	}
-
-	count, err := sw.slidingWindow.countRequest()
-	if err != nil {
-		return false
-	}
-
-	allowedRequests := int64(sw.slidingWindow.rate * sw.slidingWindow.window.Seconds())
-
-	return count <= allowedRequests
-}
-
-// 1e9 is a floating-point number that represents a billion using scientific notation, 1 * 10^9
-// "+inf" is a string that represents positive infinity
-const (
-	_billion  = 1e9
-	_infinity = "+inf"
-)
-
-func (l *limiter) increment() error {
-	var (
-		now    = time.Now().UnixNano()
-		rate   = float64(now)
-		member = strset.IntToStr(int(now))
-	)
-
-	_, err := l.cache.ZAdd(context.Background(), l.keyPrefix, rate, member)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func (l *limiter) removeExpired() error {
 	var (
-		now      = time.Now().UnixNano()
-		minScore = float64(now) - l.window.Seconds()*_billion
+		l      = sw.slidingWindow
+		policy = Policy{
+			Window: l.window,
+			Rate:   int64(l.rate * l.window.Seconds()),
+		}
 	)
 
-	_, err := l.cache.ZRemRangeByScore(context.Background(), l.keyPrefix, "0", fmt.Sprintf("%.0f", minScore))
+	result, err := l.run(context.Background(), []string{l.keyPrefix}, policy, time.Now())
 	if err != nil {
-		return err
+		return false
 	}
-	return nil
-}
-
-func (l *limiter) countRequest() (int64, error) {
-	var (
-		now      = time.Now().UnixNano()
-		minScore = float64(now) - l.window.Seconds()*_billion
-	)
 
-	count, err := l.cache.ZCount(context.Background(), l.keyPrefix, fmt.Sprintf("%.0f", minScore), _infinity)
-	if err != nil {
-		return 0, err
-	}
-	return count, nil
+	return result.Allowed
 }
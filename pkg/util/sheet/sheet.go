@@ -0,0 +1,19 @@
+package sheet
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// Reader reads the rows of a sheet one by one, Read returns io.EOF after the last row
+type Reader interface {
+	Read() ([]string, error)
+}
+
+// NewCSV returns the reader of the csv file, the rows may have different number of fields
+func NewCSV(r io.Reader) Reader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	return reader
+}
@@ -0,0 +1,226 @@
+package sheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	_workbook      = "xl/workbook.xml"
+	_workbookRels  = "xl/_rels/workbook.xml.rels"
+	_sharedStrings = "xl/sharedStrings.xml"
+	_firstSheet    = "xl/worksheets/sheet1.xml"
+)
+
+var ErrInvalidXLSX = errors.New("invalid xlsx file")
+
+type xlsx struct {
+	closer  io.Closer
+	decoder *xml.Decoder
+	strings []string
+}
+
+// NewXLSX returns the reader of the first sheet of the xlsx file, the rows are decoded while they are read,
+// only the shared strings of the workbook are kept in memory
+func NewXLSX(r io.ReaderAt, size int64) (Reader, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidXLSX, err)
+	}
+
+	var x = new(xlsx)
+
+	x.strings, err = sharedStrings(archive)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidXLSX, err)
+	}
+
+	sheet, err := archive.Open(firstSheet(archive))
+	if err != nil {
+		return nil, errors.Join(ErrInvalidXLSX, err)
+	}
+
+	x.closer = sheet
+	x.decoder = xml.NewDecoder(sheet)
+
+	return x, nil
+}
+
+// Read returns the values of the next row, the missing cells are returned as empty values
+func (x *xlsx) Read() ([]string, error) {
+	for {
+		token, err := x.decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				_ = x.closer.Close()
+			}
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline struct {
+					Text []string `xml:"t"`
+					Runs []string `xml:"r>t"`
+				} `xml:"is"`
+			} `xml:"c"`
+		}
+
+		err = x.decoder.DecodeElement(&row, &start)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidXLSX, err)
+		}
+
+		var values = make([]string, 0, len(row.Cells))
+		for _, cell := range row.Cells {
+			// the empty cells may be omitted, the column is taken from the reference as A1
+			if idx := column(cell.Ref); idx > len(values) {
+				values = append(values, make([]string, idx-len(values))...)
+			}
+
+			var value string
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(x.strings) {
+					return nil, ErrInvalidXLSX
+				}
+				value = x.strings[idx]
+			case "inlineStr":
+				value = strings.Join(cell.Inline.Text, "") + strings.Join(cell.Inline.Runs, "")
+			case "", "n":
+				value = number(cell.Value)
+			default:
+				value = cell.Value
+			}
+
+			values = append(values, value)
+		}
+
+		return values, nil
+	}
+}
+
+// sharedStrings reads the strings the cells of the type `s` refer to by index
+func sharedStrings(archive *zip.Reader) ([]string, error) {
+	file, err := archive.Open(_sharedStrings)
+	if err != nil {
+		// the workbook without strings doesn't have the file
+		return nil, nil
+	}
+	defer func() { _ = file.Close() }()
+
+	var (
+		decoder = xml.NewDecoder(file)
+		result  []string
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return result, nil
+			}
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "si" {
+			continue
+		}
+
+		var item struct {
+			Text []string `xml:"t"`
+			Runs []string `xml:"r>t"`
+		}
+		err = decoder.DecodeElement(&item, &start)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, strings.Join(item.Text, "")+strings.Join(item.Runs, ""))
+	}
+}
+
+// firstSheet resolves the path of the first sheet of the workbook, sheet1.xml is used if it cannot be resolved
+func firstSheet(archive *zip.Reader) string {
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	if decodeFile(archive, _workbook, &workbook) != nil || len(workbook.Sheets) == 0 {
+		return _firstSheet
+	}
+	if decodeFile(archive, _workbookRels, &rels) != nil {
+		return _firstSheet
+	}
+
+	for _, rel := range rels.Items {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+		return path.Join("xl", rel.Target)
+	}
+
+	return _firstSheet
+}
+
+func decodeFile(archive *zip.Reader, name string, v any) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	return xml.NewDecoder(file).Decode(v)
+}
+
+// column returns the zero-based index of the column of the cell reference, e.g. 2 for C10
+func column(ref string) int {
+	var idx int
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		idx = idx*26 + int(r-'A'+1)
+	}
+	return idx - 1
+}
+
+// number formats the numbers stored in the exponent notation, e.g. the long phones as 9.92111111111E+11
+func number(value string) string {
+	if !strings.ContainsAny(value, "eE") {
+		return value
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,182 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+const (
+	_testWorkbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Users" sheetId="2" r:id="rId7"/><sheet name="Other" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	_testRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId7" Target="worksheets/users.xml"/></Relationships>`
+	_testStrings = `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="3" uniqueCount="3">
+<si><t>userID</t></si><si><t>phone</t></si><si><r><t>rich </t></r><r><t>text</t></r></si></sst>`
+)
+
+func sheetXML(rows string) string {
+	return `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+func buildXLSX(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func readAll(r Reader) ([][]string, error) {
+	var rows [][]string
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+func Test_XLSX(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  [][]string
+		err   error
+	}{
+		{
+			name: "shared strings and numbers",
+			files: map[string]string{
+				_sharedStrings: _testStrings,
+				_firstSheet: sheetXML(`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+					`<row r="2"><c r="A2"><v>42</v></c><c r="B2" t="n"><v>9.92111111111E+11</v></c></row>` +
+					`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3" t="str"><v>formula</v></c></row>`),
+			},
+			want: [][]string{{"userID", "phone"}, {"42", "992111111111"}, {"rich text", "formula"}},
+		},
+		{
+			name: "inline strings",
+			files: map[string]string{
+				_firstSheet: sheetXML(`<row r="1"><c r="A1" t="inlineStr"><is><t>plain</t></is></c>` +
+					`<c r="B1" t="inlineStr"><is><r><t>ru</t></r><r><t>ns</t></r></is></c></row>`),
+			},
+			want: [][]string{{"plain", "runs"}},
+		},
+		{
+			name: "sparse cells",
+			files: map[string]string{
+				_firstSheet: sheetXML(`<row r="1"><c r="B1"><v>1</v></c><c r="D1"><v>2</v></c></row>` +
+					`<row r="2"></row>` +
+					`<row r="3"><c r="AA3"><v>3</v></c></row>`),
+			},
+			want: [][]string{{"", "1", "", "2"}, {}, {26: "3"}},
+		},
+		{
+			name: "cells without references",
+			files: map[string]string{
+				_firstSheet: sheetXML(`<row><c><v>1</v></c><c><v>2</v></c><c r="D1"><v>4</v></c><c><v>5</v></c></row>`),
+			},
+			want: [][]string{{"1", "2", "", "4", "5"}},
+		},
+		{
+			name: "first sheet is not sheet1",
+			files: map[string]string{
+				_workbook:                 _testWorkbook,
+				_workbookRels:             _testRels,
+				_firstSheet:               sheetXML(`<row r="1"><c r="A1"><v>2</v></c></row>`),
+				"xl/worksheets/users.xml": sheetXML(`<row r="1"><c r="A1"><v>1</v></c></row>`),
+			},
+			want: [][]string{{"1"}},
+		},
+		{
+			name: "absolute target of the first sheet",
+			files: map[string]string{
+				_workbook:           _testWorkbook,
+				_workbookRels:       `<Relationships><Relationship Id="rId7" Target="/xl/data/users.xml"/></Relationships>`,
+				"xl/data/users.xml": sheetXML(`<row r="1"><c r="A1"><v>1</v></c></row>`),
+			},
+			want: [][]string{{"1"}},
+		},
+		{
+			name: "shared string out of range",
+			files: map[string]string{
+				_sharedStrings: _testStrings,
+				_firstSheet:    sheetXML(`<row r="1"><c r="A1" t="s"><v>3</v></c></row>`),
+			},
+			err: ErrInvalidXLSX,
+		},
+		{
+			name: "missing sheet",
+			files: map[string]string{
+				_workbook: _testWorkbook,
+			},
+			err: ErrInvalidXLSX,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := buildXLSX(t, tt.files)
+
+			reader, err := NewXLSX(file, file.Size())
+			if err == nil {
+				var rows [][]string
+				rows, err = readAll(reader)
+				if err == nil && !reflect.DeepEqual(rows, tt.want) {
+					t.Errorf("rows = %q, want %q", rows, tt.want)
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func Test_XLSX_NotZip(t *testing.T) {
+	var file = bytes.NewReader([]byte("userID,phone\n1,992900000000\n"))
+
+	if _, err := NewXLSX(file, file.Size()); !errors.Is(err, ErrInvalidXLSX) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidXLSX)
+	}
+}
+
+func Test_column(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{ref: "A1", want: 0},
+		{ref: "C10", want: 2},
+		{ref: "Z1", want: 25},
+		{ref: "AA1", want: 26},
+		{ref: "AZ5", want: 51},
+		{ref: "", want: -1},
+	}
+
+	for _, tt := range tests {
+		if got := column(tt.ref); got != tt.want {
+			t.Errorf("column(%q) = %d, want %d", tt.ref, got, tt.want)
+		}
+	}
+}